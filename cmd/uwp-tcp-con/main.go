@@ -9,7 +9,7 @@ import (
 
 func main() {
 	app := cli.NewApp()
-	if err := app.Run(); err != nil {
+	if err := app.RunCommand(os.Args[1:]); err != nil {
 		fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
 	}
@@ -0,0 +1,349 @@
+package alert
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	RuleDown           = "down"
+	RuleRecovered      = "recovered"
+	RuleLatencyP95     = "latency_p95"
+	RuleVersionChanged = "version_changed"
+	RulePlayersAbove   = "players_above"
+	RuleMOTDChanged    = "motd_changed"
+)
+
+const (
+	defaultDownChecks     = 3
+	defaultLatencyWindow  = 20
+	defaultCooldown       = 5 * time.Minute
+	maxLatencyWindowLimit = 500
+)
+
+type Observation struct {
+	Target     string
+	Time       time.Time
+	Up         bool
+	Latency    time.Duration
+	Players    int
+	MaxPlayers int
+	Version    string
+	MOTDHash   string
+	Failure    string
+}
+
+type Rule struct {
+	Name            string   `json:"name"`
+	Type            string   `json:"type"`
+	Threshold       float64  `json:"threshold,omitempty"`
+	Checks          int      `json:"checks,omitempty"`
+	Targets         []string `json:"targets,omitempty"`
+	Channels        []string `json:"channels,omitempty"`
+	CooldownSeconds int      `json:"cooldown_seconds,omitempty"`
+}
+
+type Config struct {
+	Rules           []Rule    `json:"rules"`
+	Channels        []Channel `json:"channels"`
+	CooldownSeconds int       `json:"cooldown_seconds,omitempty"`
+}
+
+type Alert struct {
+	Rule    string    `json:"rule"`
+	Type    string    `json:"type"`
+	Target  string    `json:"target"`
+	Time    time.Time `json:"time"`
+	Title   string    `json:"title"`
+	Message string    `json:"message"`
+	Test    bool      `json:"test,omitempty"`
+}
+
+type targetState struct {
+	failures  int
+	version   string
+	motdHash  string
+	latencies []time.Duration
+}
+
+type Engine struct {
+	mu        sync.Mutex
+	config    Config
+	senders   map[string]Sender
+	states    map[string]*targetState
+	active    map[string]bool
+	lastFired map[string]time.Time
+}
+
+func (c Config) Validate() error {
+	channels := make(map[string]struct{}, len(c.Channels))
+	for _, channel := range c.Channels {
+		if strings.TrimSpace(channel.Name) == "" {
+			return fmt.Errorf("alert channel without a name")
+		}
+		if _, ok := channels[channel.Name]; ok {
+			return fmt.Errorf("duplicate alert channel %q", channel.Name)
+		}
+		if _, err := NewSender(channel); err != nil {
+			return fmt.Errorf("alert channel %q: %w", channel.Name, err)
+		}
+		channels[channel.Name] = struct{}{}
+	}
+	if len(c.Rules) > 0 && len(c.Channels) == 0 {
+		return fmt.Errorf("alert rules need at least one channel")
+	}
+	rules := make(map[string]struct{}, len(c.Rules))
+	for _, rule := range c.Rules {
+		if strings.TrimSpace(rule.Name) == "" {
+			return fmt.Errorf("alert rule without a name")
+		}
+		if _, ok := rules[rule.Name]; ok {
+			return fmt.Errorf("duplicate alert rule %q", rule.Name)
+		}
+		rules[rule.Name] = struct{}{}
+		switch rule.Type {
+		case RuleDown, RuleRecovered, RuleVersionChanged, RuleMOTDChanged:
+		case RuleLatencyP95, RulePlayersAbove:
+			if rule.Threshold <= 0 {
+				return fmt.Errorf("alert rule %q needs a positive threshold", rule.Name)
+			}
+		default:
+			return fmt.Errorf("alert rule %q has unknown type %q", rule.Name, rule.Type)
+		}
+		for _, name := range rule.Channels {
+			if _, ok := channels[name]; !ok {
+				return fmt.Errorf("alert rule %q uses unknown channel %q", rule.Name, name)
+			}
+		}
+	}
+	return nil
+}
+
+func NewEngine(config Config) (*Engine, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	senders := make(map[string]Sender, len(config.Channels))
+	for _, channel := range config.Channels {
+		sender, err := NewSender(channel)
+		if err != nil {
+			return nil, err
+		}
+		senders[channel.Name] = sender
+	}
+	return &Engine{
+		config:    config,
+		senders:   senders,
+		states:    make(map[string]*targetState),
+		active:    make(map[string]bool),
+		lastFired: make(map[string]time.Time),
+	}, nil
+}
+
+func (e *Engine) Observe(obs Observation) []Alert {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	state, ok := e.states[obs.Target]
+	if !ok {
+		state = &targetState{}
+		e.states[obs.Target] = state
+	}
+	previous := *state
+	if obs.Up {
+		state.failures = 0
+		state.latencies = append(state.latencies, obs.Latency)
+		if len(state.latencies) > maxLatencyWindowLimit {
+			state.latencies = state.latencies[len(state.latencies)-maxLatencyWindowLimit:]
+		}
+		if obs.Version != "" {
+			state.version = obs.Version
+		}
+		if obs.MOTDHash != "" {
+			state.motdHash = obs.MOTDHash
+		}
+	} else {
+		state.failures++
+	}
+
+	alerts := make([]Alert, 0)
+	for _, rule := range e.config.Rules {
+		if !rule.appliesTo(obs.Target) {
+			continue
+		}
+		condition, event, message := rule.evaluate(obs, previous, *state)
+		key := rule.Name + "\x00" + obs.Target
+		if condition {
+			if !event {
+				e.active[key] = false
+				continue
+			}
+			if e.active[key] {
+				continue
+			}
+		} else if !event {
+			continue
+		}
+		if last, ok := e.lastFired[key]; ok && obs.Time.Sub(last) < e.cooldown(rule) {
+			continue
+		}
+		if condition {
+			e.active[key] = true
+		}
+		e.lastFired[key] = obs.Time
+		alerts = append(alerts, Alert{
+			Rule:    rule.Name,
+			Type:    rule.Type,
+			Target:  obs.Target,
+			Time:    obs.Time,
+			Title:   fmt.Sprintf("[%s] %s", obs.Target, rule.title()),
+			Message: message,
+		})
+	}
+	return alerts
+}
+
+func (e *Engine) Dispatch(ctx context.Context, alert Alert) error {
+	names := e.channelsFor(alert.Rule)
+	var errs []error
+	for _, name := range names {
+		sender, ok := e.senders[name]
+		if !ok {
+			continue
+		}
+		if err := sender.Send(ctx, alert); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (e *Engine) TestFire(ctx context.Context, channel string) error {
+	sender, ok := e.senders[channel]
+	if !ok {
+		return fmt.Errorf("unknown alert channel %q", channel)
+	}
+	return sender.Send(ctx, Alert{
+		Rule:    "test",
+		Type:    "test",
+		Target:  "mcquery",
+		Time:    time.Now(),
+		Title:   "[mcquery] Test alert",
+		Message: fmt.Sprintf("Test alert for channel %s. If you can read this, the channel works.", channel),
+		Test:    true,
+	})
+}
+
+func (e *Engine) Channels() []string {
+	names := make([]string, 0, len(e.senders))
+	for name := range e.senders {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (e *Engine) channelsFor(ruleName string) []string {
+	for _, rule := range e.config.Rules {
+		if rule.Name == ruleName && len(rule.Channels) > 0 {
+			return rule.Channels
+		}
+	}
+	return e.Channels()
+}
+
+func (e *Engine) cooldown(rule Rule) time.Duration {
+	if rule.CooldownSeconds > 0 {
+		return time.Duration(rule.CooldownSeconds) * time.Second
+	}
+	if e.config.CooldownSeconds > 0 {
+		return time.Duration(e.config.CooldownSeconds) * time.Second
+	}
+	return defaultCooldown
+}
+
+func (r Rule) appliesTo(target string) bool {
+	if len(r.Targets) == 0 {
+		return true
+	}
+	for _, name := range r.Targets {
+		if strings.EqualFold(name, target) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r Rule) checks(fallback int) int {
+	if r.Checks > 0 {
+		return r.Checks
+	}
+	return fallback
+}
+
+func (r Rule) title() string {
+	switch r.Type {
+	case RuleDown:
+		return "Server down"
+	case RuleRecovered:
+		return "Server recovered"
+	case RuleLatencyP95:
+		return "High latency"
+	case RuleVersionChanged:
+		return "Version changed"
+	case RulePlayersAbove:
+		return "Player threshold exceeded"
+	case RuleMOTDChanged:
+		return "MOTD changed"
+	default:
+		return r.Name
+	}
+}
+
+func (r Rule) evaluate(obs Observation, previous, current targetState) (bool, bool, string) {
+	switch r.Type {
+	case RuleDown:
+		checks := r.checks(defaultDownChecks)
+		return true, current.failures >= checks, fmt.Sprintf("No response for %d consecutive checks (last failure: %s).", current.failures, obs.Failure)
+	case RuleRecovered:
+		checks := r.checks(defaultDownChecks)
+		return false, obs.Up && previous.failures >= checks, fmt.Sprintf("Responding again after %d failed checks.", previous.failures)
+	case RuleLatencyP95:
+		window := r.checks(defaultLatencyWindow)
+		if len(current.latencies) < window {
+			return true, false, ""
+		}
+		p95 := percentile(current.latencies[len(current.latencies)-window:], 0.95)
+		threshold := time.Duration(r.Threshold * float64(time.Millisecond))
+		return true, p95 > threshold, fmt.Sprintf("Latency p95 over the last %d checks is %s (threshold %s).", window, p95.Round(time.Millisecond), threshold)
+	case RuleVersionChanged:
+		changed := obs.Up && obs.Version != "" && previous.version != "" && obs.Version != previous.version
+		return false, changed, fmt.Sprintf("Version changed from %s to %s.", previous.version, obs.Version)
+	case RulePlayersAbove:
+		return true, obs.Up && float64(obs.Players) > r.Threshold, fmt.Sprintf("%d/%d players online (threshold %.0f).", obs.Players, obs.MaxPlayers, r.Threshold)
+	case RuleMOTDChanged:
+		changed := obs.Up && obs.MOTDHash != "" && previous.motdHash != "" && obs.MOTDHash != previous.motdHash
+		return false, changed, "The server MOTD changed."
+	default:
+		return false, false, ""
+	}
+}
+
+func percentile(values []time.Duration, p float64) time.Duration {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := append([]time.Duration(nil), values...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	index := int(math.Ceil(p*float64(len(sorted)))) - 1
+	if index < 0 {
+		index = 0
+	}
+	return sorted[index]
+}
@@ -0,0 +1,209 @@
+package alert
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestEngine(t *testing.T, rules ...Rule) *Engine {
+	t.Helper()
+	engine, err := NewEngine(Config{
+		Rules:    rules,
+		Channels: []Channel{{Name: "hook", Type: ChannelWebhook, URL: "http://127.0.0.1:1"}},
+	})
+	if err != nil {
+		t.Fatalf("NewEngine: %v", err)
+	}
+	return engine
+}
+
+func TestDownFiresOnceAfterConsecutiveFailures(t *testing.T) {
+	engine := newTestEngine(t, Rule{Name: "down", Type: RuleDown, Checks: 3})
+	start := time.Unix(1_700_000_000, 0)
+	fired := 0
+	for i := 0; i < 6; i++ {
+		fired += len(engine.Observe(Observation{Target: "lobby", Time: start.Add(time.Duration(i) * time.Minute)}))
+		if i == 1 && fired != 0 {
+			t.Fatalf("alert fired after %d failures", i+1)
+		}
+	}
+	if fired != 1 {
+		t.Fatalf("expected a single down alert, got %d", fired)
+	}
+}
+
+func TestRecoveredAndCooldown(t *testing.T) {
+	engine := newTestEngine(t, Rule{Name: "back", Type: RuleRecovered, Checks: 1, CooldownSeconds: 600})
+	start := time.Unix(1_700_000_000, 0)
+	engine.Observe(Observation{Target: "lobby", Time: start})
+	alerts := engine.Observe(Observation{Target: "lobby", Time: start.Add(time.Minute), Up: true})
+	if len(alerts) != 1 || alerts[0].Type != RuleRecovered {
+		t.Fatalf("expected recovered alert, got %+v", alerts)
+	}
+	engine.Observe(Observation{Target: "lobby", Time: start.Add(2 * time.Minute)})
+	if alerts := engine.Observe(Observation{Target: "lobby", Time: start.Add(3 * time.Minute), Up: true}); len(alerts) != 0 {
+		t.Fatalf("expected cooldown to suppress alert, got %+v", alerts)
+	}
+	engine.Observe(Observation{Target: "lobby", Time: start.Add(20 * time.Minute)})
+	if alerts := engine.Observe(Observation{Target: "lobby", Time: start.Add(21 * time.Minute), Up: true}); len(alerts) != 1 {
+		t.Fatalf("expected alert after cooldown, got %+v", alerts)
+	}
+}
+
+func TestRecoveredWaitsForDownThreshold(t *testing.T) {
+	engine := newTestEngine(t, Rule{Name: "down", Type: RuleDown}, Rule{Name: "back", Type: RuleRecovered})
+	start := time.Unix(1_700_000_000, 0)
+	engine.Observe(Observation{Target: "lobby", Time: start})
+	if alerts := engine.Observe(Observation{Target: "lobby", Time: start.Add(time.Minute), Up: true}); len(alerts) != 0 {
+		t.Fatalf("expected no alert after a single failure, got %+v", alerts)
+	}
+	for i := 2; i <= 4; i++ {
+		engine.Observe(Observation{Target: "lobby", Time: start.Add(time.Duration(i) * time.Minute)})
+	}
+	alerts := engine.Observe(Observation{Target: "lobby", Time: start.Add(5 * time.Minute), Up: true})
+	if len(alerts) != 1 || alerts[0].Rule != "back" {
+		t.Fatalf("expected recovered alert after an outage, got %+v", alerts)
+	}
+}
+
+func TestDownFiresAfterCooldownWhenStillDown(t *testing.T) {
+	engine := newTestEngine(t, Rule{Name: "down", Type: RuleDown, Checks: 1, CooldownSeconds: 300})
+	start := time.Unix(1_700_000_000, 0)
+	if alerts := engine.Observe(Observation{Target: "lobby", Time: start}); len(alerts) != 1 {
+		t.Fatalf("expected first down alert, got %+v", alerts)
+	}
+	engine.Observe(Observation{Target: "lobby", Time: start.Add(time.Minute), Up: true})
+	if alerts := engine.Observe(Observation{Target: "lobby", Time: start.Add(2 * time.Minute)}); len(alerts) != 0 {
+		t.Fatalf("expected cooldown to suppress alert, got %+v", alerts)
+	}
+	if alerts := engine.Observe(Observation{Target: "lobby", Time: start.Add(6 * time.Minute)}); len(alerts) != 1 {
+		t.Fatalf("expected down alert after cooldown, got %+v", alerts)
+	}
+	if alerts := engine.Observe(Observation{Target: "lobby", Time: start.Add(12 * time.Minute)}); len(alerts) != 0 {
+		t.Fatalf("expected a single alert per episode, got %+v", alerts)
+	}
+}
+
+func TestLatencyP95AndVersionChange(t *testing.T) {
+	engine := newTestEngine(t,
+		Rule{Name: "slow", Type: RuleLatencyP95, Threshold: 200, Checks: 4},
+		Rule{Name: "version", Type: RuleVersionChanged},
+	)
+	start := time.Unix(1_700_000_000, 0)
+	latencies := []time.Duration{50, 60, 70, 400}
+	var alerts []Alert
+	for i, latency := range latencies {
+		alerts = engine.Observe(Observation{Target: "lobby", Time: start.Add(time.Duration(i) * time.Minute), Up: true, Latency: latency * time.Millisecond, Version: "1.21"})
+	}
+	if len(alerts) != 1 || alerts[0].Rule != "slow" {
+		t.Fatalf("expected latency alert, got %+v", alerts)
+	}
+	alerts = engine.Observe(Observation{Target: "lobby", Time: start.Add(10 * time.Minute), Up: true, Latency: 50 * time.Millisecond, Version: "1.21.1"})
+	if len(alerts) != 1 || alerts[0].Rule != "version" || !strings.Contains(alerts[0].Message, "1.21 to 1.21.1") {
+		t.Fatalf("expected version alert, got %+v", alerts)
+	}
+}
+
+func TestWebhookPayloads(t *testing.T) {
+	bodies := make(chan map[string]any, 2)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("decode: %v", err)
+		}
+		bodies <- body
+	}))
+	defer server.Close()
+
+	engine, err := NewEngine(Config{
+		Rules: []Rule{{Name: "down", Type: RuleDown, Checks: 1}},
+		Channels: []Channel{
+			{Name: "discord", Type: ChannelDiscord, URL: server.URL},
+			{Name: "json", Type: ChannelWebhook, URL: server.URL},
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewEngine: %v", err)
+	}
+	alerts := engine.Observe(Observation{Target: "lobby", Time: time.Now(), Failure: "timeout"})
+	if len(alerts) != 1 {
+		t.Fatalf("expected one alert, got %d", len(alerts))
+	}
+	if err := engine.Dispatch(context.Background(), alerts[0]); err != nil {
+		t.Fatalf("Dispatch: %v", err)
+	}
+	discord := <-bodies
+	if content, _ := discord["content"].(string); !strings.HasPrefix(content, "**[lobby] Server down**") {
+		t.Fatalf("unexpected discord payload: %v", discord)
+	}
+	generic := <-bodies
+	if generic["rule"] != "down" || generic["target"] != "lobby" {
+		t.Fatalf("unexpected webhook payload: %v", generic)
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	config := Config{
+		Rules:    []Rule{{Name: "down", Type: RuleDown, Channels: []string{"missing"}}},
+		Channels: []Channel{{Name: "hook", Type: ChannelWebhook, URL: "http://example.invalid"}},
+	}
+	if err := config.Validate(); err == nil {
+		t.Fatalf("expected unknown channel error")
+	}
+	config.Rules[0].Channels = nil
+	config.Rules = append(config.Rules, Rule{Name: "players", Type: RulePlayersAbove})
+	if err := config.Validate(); err == nil {
+		t.Fatalf("expected missing threshold error")
+	}
+	config.Rules = []Rule{{Name: "down", Type: RuleDown}, {Name: "down", Type: RuleRecovered}}
+	if err := config.Validate(); err == nil || !strings.Contains(err.Error(), "duplicate") {
+		t.Fatalf("expected duplicate rule error, got %v", err)
+	}
+	config.Rules = []Rule{{Name: " ", Type: RuleDown}}
+	if err := config.Validate(); err == nil {
+		t.Fatalf("expected unnamed rule error")
+	}
+	config.Rules = []Rule{{Name: "down", Type: RuleDown}}
+	config.Channels = nil
+	if err := config.Validate(); err == nil {
+		t.Fatalf("expected missing channel error")
+	}
+}
+
+func TestSMTPSendHonoursContext(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer listener.Close()
+	go func() {
+		conn, err := listener.Accept()
+		if err == nil {
+			defer conn.Close()
+			_, _ = io.Copy(io.Discard, conn)
+		}
+	}()
+	host, port, _ := net.SplitHostPort(listener.Addr().String())
+	portNumber, _ := strconv.Atoi(port)
+	sender, err := NewSender(Channel{Name: "mail", Type: ChannelSMTP, Host: host, Port: portNumber, From: "a@example.com", To: []string{"b@example.com"}})
+	if err != nil {
+		t.Fatalf("NewSender: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	started := time.Now()
+	if err := sender.Send(ctx, Alert{Title: "t", Message: "m", Time: started}); err == nil {
+		t.Fatalf("expected error from silent SMTP server")
+	}
+	if elapsed := time.Since(started); elapsed > 2*time.Second {
+		t.Fatalf("send ignored the context, took %s", elapsed)
+	}
+}
@@ -0,0 +1,246 @@
+package alert
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/smtp"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	ChannelWebhook = "webhook"
+	ChannelDiscord = "discord"
+	ChannelSlack   = "slack"
+	ChannelSMTP    = "smtp"
+	ChannelExec    = "exec"
+)
+
+const sendTimeout = 10 * time.Second
+
+type Channel struct {
+	Name     string            `json:"name"`
+	Type     string            `json:"type"`
+	URL      string            `json:"url,omitempty"`
+	Headers  map[string]string `json:"headers,omitempty"`
+	Host     string            `json:"host,omitempty"`
+	Port     int               `json:"port,omitempty"`
+	Username string            `json:"username,omitempty"`
+	Password string            `json:"password,omitempty"`
+	From     string            `json:"from,omitempty"`
+	To       []string          `json:"to,omitempty"`
+	Command  []string          `json:"command,omitempty"`
+}
+
+type Sender interface {
+	Send(ctx context.Context, alert Alert) error
+}
+
+type webhookSender struct {
+	url     string
+	headers map[string]string
+	payload func(alert Alert) any
+	client  *http.Client
+}
+
+type smtpSender struct {
+	addr string
+	auth smtp.Auth
+	from string
+	to   []string
+}
+
+type execSender struct {
+	command []string
+}
+
+func NewSender(channel Channel) (Sender, error) {
+	switch channel.Type {
+	case ChannelWebhook, ChannelDiscord, ChannelSlack:
+		if strings.TrimSpace(channel.URL) == "" {
+			return nil, fmt.Errorf("url is required")
+		}
+		payload := genericPayload
+		switch channel.Type {
+		case ChannelDiscord:
+			payload = discordPayload
+		case ChannelSlack:
+			payload = slackPayload
+		}
+		return &webhookSender{
+			url:     channel.URL,
+			headers: channel.Headers,
+			payload: payload,
+			client:  &http.Client{Timeout: sendTimeout},
+		}, nil
+	case ChannelSMTP:
+		if channel.Host == "" || channel.From == "" || len(channel.To) == 0 {
+			return nil, fmt.Errorf("host, from and to are required")
+		}
+		port := channel.Port
+		if port == 0 {
+			port = 587
+		}
+		var auth smtp.Auth
+		if channel.Username != "" {
+			auth = smtp.PlainAuth("", channel.Username, channel.Password, channel.Host)
+		}
+		return &smtpSender{
+			addr: net.JoinHostPort(channel.Host, strconv.Itoa(port)),
+			auth: auth,
+			from: channel.From,
+			to:   channel.To,
+		}, nil
+	case ChannelExec:
+		if len(channel.Command) == 0 || strings.TrimSpace(channel.Command[0]) == "" {
+			return nil, fmt.Errorf("command is required")
+		}
+		return &execSender{command: channel.Command}, nil
+	default:
+		return nil, fmt.Errorf("unknown channel type %q", channel.Type)
+	}
+}
+
+func genericPayload(alert Alert) any {
+	return alert
+}
+
+func discordPayload(alert Alert) any {
+	return map[string]any{
+		"username": "MCQuery",
+		"content":  fmt.Sprintf("**%s**\n%s", alert.Title, alert.Message),
+	}
+}
+
+func slackPayload(alert Alert) any {
+	return map[string]any{
+		"text": fmt.Sprintf("*%s*\n%s", alert.Title, alert.Message),
+	}
+}
+
+func (s *webhookSender) Send(ctx context.Context, alert Alert) error {
+	body, err := json.Marshal(s.payload(alert))
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range s.headers {
+		req.Header.Set(key, value)
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
+
+func (s *smtpSender) Send(ctx context.Context, alert Alert) error {
+	ctx, cancel := context.WithTimeout(ctx, sendTimeout)
+	defer cancel()
+	var message strings.Builder
+	message.WriteString(fmt.Sprintf("From: %s\r\n", s.from))
+	message.WriteString(fmt.Sprintf("To: %s\r\n", strings.Join(s.to, ", ")))
+	message.WriteString(fmt.Sprintf("Subject: %s\r\n", alert.Title))
+	message.WriteString(fmt.Sprintf("Date: %s\r\n", alert.Time.Format(time.RFC1123Z)))
+	message.WriteString("MIME-Version: 1.0\r\n")
+	message.WriteString("Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	message.WriteString(alert.Message)
+	message.WriteString("\r\n")
+
+	dialer := &net.Dialer{}
+	conn, err := dialer.DialContext(ctx, "tcp", s.addr)
+	if err != nil {
+		return err
+	}
+	deadline, _ := ctx.Deadline()
+	if err := conn.SetDeadline(deadline); err != nil {
+		_ = conn.Close()
+		return err
+	}
+	stop := context.AfterFunc(ctx, func() { _ = conn.SetDeadline(time.Now()) })
+	defer stop()
+	host, _, _ := net.SplitHostPort(s.addr)
+	client, err := smtp.NewClient(conn, host)
+	if err != nil {
+		_ = conn.Close()
+		return err
+	}
+	defer client.Close()
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: host}); err != nil {
+			return err
+		}
+	}
+	if s.auth != nil {
+		if ok, _ := client.Extension("AUTH"); ok {
+			if err := client.Auth(s.auth); err != nil {
+				return err
+			}
+		}
+	}
+	if err := client.Mail(s.from); err != nil {
+		return err
+	}
+	for _, to := range s.to {
+		if err := client.Rcpt(to); err != nil {
+			return err
+		}
+	}
+	writer, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := writer.Write([]byte(message.String())); err != nil {
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+func (s *execSender) Send(ctx context.Context, alert Alert) error {
+	ctx, cancel := context.WithTimeout(ctx, sendTimeout)
+	defer cancel()
+	payload, err := json.Marshal(alert)
+	if err != nil {
+		return err
+	}
+	cmd := exec.CommandContext(ctx, s.command[0], s.command[1:]...)
+	cmd.Stdin = bytes.NewReader(payload)
+	cmd.Env = append(os.Environ(),
+		"MCQUERY_ALERT_RULE="+alert.Rule,
+		"MCQUERY_ALERT_TYPE="+alert.Type,
+		"MCQUERY_ALERT_TARGET="+alert.Target,
+		"MCQUERY_ALERT_TITLE="+alert.Title,
+		"MCQUERY_ALERT_MESSAGE="+alert.Message,
+		"MCQUERY_ALERT_TIME="+alert.Time.Format(time.RFC3339),
+	)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		text := strings.TrimSpace(string(output))
+		if text != "" {
+			return fmt.Errorf("%w: %s", err, text)
+		}
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"UWP-TCP-Con/internal/alert"
+	"UWP-TCP-Con/internal/history"
+	"UWP-TCP-Con/internal/ping"
+)
+
+const alertTestTimeout = 15 * time.Second
+
+func alertConfigPath() (string, error) {
+	return configFile("alerts.json")
+}
+
+func loadAlertConfig(path string) (alert.Config, bool, error) {
+	if path == "" {
+		defaultPath, err := alertConfigPath()
+		if err != nil {
+			return alert.Config{}, false, err
+		}
+		path = defaultPath
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return alert.Config{}, false, nil
+		}
+		return alert.Config{}, false, err
+	}
+	var config alert.Config
+	if err := json.Unmarshal(data, &config); err != nil {
+		return alert.Config{}, false, fmt.Errorf("%s: %w", path, err)
+	}
+	return config, true, nil
+}
+
+func loadAlertEngine(path string) (*alert.Engine, error) {
+	config, ok, err := loadAlertConfig(path)
+	if err != nil || !ok {
+		return nil, err
+	}
+	if len(config.Rules) == 0 {
+		return nil, config.Validate()
+	}
+	return alert.NewEngine(config)
+}
+
+func alertObservation(target monitorTarget, sample watchSample) alert.Observation {
+	obs := alert.Observation{
+		Target: target.Name,
+		Time:   sample.At,
+		Up:     sample.Up,
+	}
+	if !sample.Up {
+		obs.Failure = ping.ClassifyError(sample.Err).Label()
+		return obs
+	}
+	obs.Latency = sample.Latency
+	obs.Players = sample.Players
+	obs.MaxPlayers = sample.Max
+	obs.Version = sample.Version
+	obs.MOTDHash = history.HashMOTD(sample.MOTD)
+	return obs
+}
+
+func (a *App) testFireAlerts() error {
+	config, ok, err := loadAlertConfig("")
+	if err != nil {
+		return renderTextPageAndWait("Alerts", fmt.Sprintf("Could not read alerts.json: %v", err))
+	}
+	path, _ := alertConfigPath()
+	if !ok || len(config.Channels) == 0 {
+		return renderTextPageAndWait("Alerts", fmt.Sprintf("No alert channels configured.\nAdd channels and rules to %s and run `uwp-tcp-con monitor`.", path))
+	}
+	engine, err := alert.NewEngine(config)
+	if err != nil {
+		return renderTextPageAndWait("Alerts", fmt.Sprintf("Invalid alert config: %v", err))
+	}
+	channels := engine.Channels()
+	options := make([]string, 0, len(channels)+2)
+	options = append(options, "All channels")
+	for _, channel := range config.Channels {
+		options = append(options, fmt.Sprintf("%s: %s", channel.Name, channel.Type))
+	}
+	options = append(options, "Back")
+	index, err := selectOption("Test-fire alerts", options)
+	if err != nil {
+		return err
+	}
+	if index > len(config.Channels) {
+		return nil
+	}
+	if index > 0 {
+		channels = []string{config.Channels[index-1].Name}
+	}
+
+	resultText, err := withSpinner("Test-fire alerts", func(frame int) string {
+		_ = frame
+		return fmt.Sprintf("Sending to %d channel(s)", len(channels))
+	}, 120*time.Millisecond, func() (string, error) {
+		ctx, cancel := context.WithTimeout(context.Background(), alertTestTimeout)
+		defer cancel()
+		var builder strings.Builder
+		builder.WriteString("Results\n")
+		for _, name := range channels {
+			if err := engine.TestFire(ctx, name); err != nil {
+				builder.WriteString(fmt.Sprintf("- %s: failed: %v\n", name, err))
+				continue
+			}
+			builder.WriteString(fmt.Sprintf("- %s: sent\n", name))
+		}
+		return strings.TrimRight(builder.String(), "\n"), nil
+	})
+	if err != nil {
+		return err
+	}
+	return renderTextPageAndWait("Alerts", resultText)
+}
@@ -14,23 +14,37 @@ import (
 )
 
 type batchEntry struct {
-	Name    string
-	Edition ping.Edition
-	Host    string
-	Port    int
+	Name      string
+	Edition   ping.Edition
+	Host      string
+	Port      int
+	Tags      []string
+	Notes     string
+	Overrides probeOverrides
+}
+
+func (e batchEntry) displayName() string {
+	if e.Name == e.Host || e.Name == fmt.Sprintf("%s:%d", e.Host, e.Port) {
+		return ""
+	}
+	return e.Name
 }
 
 type batchRunResult struct {
 	Entry   batchEntry
+	Config  ping.ExecuteConfig
 	Result  ping.Result
 	Details ping.ExecuteDetails
 	Err     error
+	Retried int
 }
 
 type batchProgress struct {
-	total     int
-	completed atomic.Int64
-	current   atomic.Value
+	total      int
+	completed  atomic.Int64
+	current    atomic.Value
+	workers    atomic.Int64
+	adjustment atomic.Value
 }
 
 func (a *App) executeBatch() error {
@@ -50,35 +64,83 @@ func (a *App) executeBatch() error {
 		return renderTextPageAndWait("Batch check", "No valid entries found.")
 	}
 
+	return a.runBatchSession(entries, parseErrors)
+}
+
+func (a *App) runBatchSession(entries []batchEntry, parseErrors []string) error {
+	runResults, canceled, err := a.runBatchWithProgress("Batch check", entries)
+	if err != nil {
+		return err
+	}
+	savedPath := ""
+	for {
+		resultText := a.finishBatch(runResults, parseErrors, canceled, &savedPath)
+		if err := renderTextPageAndWait("Batch check", resultText); err != nil {
+			return err
+		}
+		failed := failedBatchIndexes(runResults)
+		if len(failed) == 0 {
+			return nil
+		}
+		index, err := selectOption("Batch check", []string{
+			fmt.Sprintf("Retry failed: Re-check %d failed entries", len(failed)),
+			"Done: Back to the main menu",
+		})
+		if err != nil {
+			return err
+		}
+		if index != 0 {
+			return nil
+		}
+		retried, retryCanceled, err := a.runBatchWithProgress("Retry failed", batchSubset(runResults, failed))
+		if err != nil {
+			return err
+		}
+		runResults = mergeBatchRetry(runResults, failed, retried)
+		canceled = retryCanceled
+	}
+}
+
+func (a *App) runBatchWithProgress(title string, entries []batchEntry) ([]batchRunResult, bool, error) {
 	var runResults []batchRunResult
+	canceled := false
 	progress := &batchProgress{total: len(entries)}
-	resultText, err := withControlledSpinner("Batch check", func(frame int, control *spinnerControl) string {
+	_, err := withControlledSpinner(title, func(frame int, control *spinnerControl) string {
 		return progress.Render(frame, control)
 	}, 120*time.Millisecond, func(control *spinnerControl) (string, error) {
 		runResults = a.runBatchEntries(control, entries, progress)
-		return formatBatchResults("Batch check", runResults, parseErrors, control.IsCancelled()), nil
+		canceled = control.IsCancelled()
+		return "", nil
 	})
-	if err != nil {
-		return err
-	}
+	return runResults, canceled, err
+}
 
-	exportText := formatBatchResults("Batch check", runResults, parseErrors, false)
+func (a *App) finishBatch(runResults []batchRunResult, parseErrors []string, canceled bool, savedPath *string) string {
+	resultText := formatBatchResults("Batch check", runResults, parseErrors, canceled, a.settings.ReportUnresponsive)
+	exportText := formatBatchResults("Batch check", runResults, parseErrors, false, a.settings.ReportUnresponsive)
 	records := batchExportRecords("batch", runResults)
+	resultText, exportText = a.templateResultText(newResultTemplateData("Batch check", "batch", records, nil), resultText, exportText)
 	if a.settings.SaveResults {
-		path, err := a.saveExport("Batch check", exportText, records)
+		var err error
+		if *savedPath == "" {
+			*savedPath, err = a.saveExport("Batch check", exportText, records)
+		} else {
+			err = writeExport(*savedPath, normalizeExportFormat(a.settings.ExportFormat), "Batch check", exportText, records)
+		}
 		if err != nil {
 			resultText = appendWarningText(resultText, "Result export failed", err)
 		} else {
-			resultText += fmt.Sprintf("\nSaved result: %s", path)
+			resultText += fmt.Sprintf("\nSaved result: %s", *savedPath)
 		}
 	}
-	return renderTextPageAndWait("Batch check", resultText)
+	a.rememberResults("Batch check", records)
+	return a.appendDashboardText(resultText, "Batch check", records)
 }
 
 func askBatchPath() (string, error) {
 	var errMsg string
 	for {
-		path, err := promptInput("Batch file", "Path to a text file. Lines: edition,host,port or host:port.", errMsg)
+		path, err := promptInput("Batch file", "Path to a target list: text lines (edition,host,port or host:port), CSV with a header row, JSON or YAML.", errMsg)
 		if err != nil {
 			return "", err
 		}
@@ -101,25 +163,12 @@ func askBatchPath() (string, error) {
 }
 
 func loadBatchEntries(path string, defaultEdition ping.Edition) ([]batchEntry, []string, error) {
-	data, err := os.ReadFile(strings.TrimSpace(path))
+	path = strings.TrimSpace(path)
+	data, err := os.ReadFile(path)
 	if err != nil {
 		return nil, nil, err
 	}
-	lines := strings.Split(string(data), "\n")
-	entries := make([]batchEntry, 0, len(lines))
-	parseErrors := make([]string, 0)
-	for i, line := range lines {
-		entry, ok, err := parseBatchLine(line, defaultEdition)
-		if err != nil {
-			parseErrors = append(parseErrors, fmt.Sprintf("line %d: %v", i+1, err))
-			continue
-		}
-		if !ok {
-			continue
-		}
-		entries = append(entries, entry)
-	}
-	return entries, parseErrors, nil
+	return parseBatchData(path, data, defaultEdition)
 }
 
 func parseBatchLine(line string, defaultEdition ping.Edition) (batchEntry, bool, error) {
@@ -142,45 +191,36 @@ func parseBatchLine(line string, defaultEdition ping.Edition) (batchEntry, bool,
 		return batchEntry{}, false, nil
 	}
 
-	edition := defaultEdition
-	if parsed, ok := parseEdition(parts[0]); ok {
-		edition = parsed
-		parts = parts[1:]
+	var target batchTarget
+	parts, err := splitOverrideTokens(&target, parts)
+	if err != nil {
+		return batchEntry{}, false, err
+	}
+	if len(parts) > 0 {
+		if _, ok := parseEdition(parts[0]); ok {
+			target.Edition = parts[0]
+			parts = parts[1:]
+		}
 	}
 	if len(parts) == 0 {
 		return batchEntry{}, false, fmt.Errorf("missing host")
 	}
-
-	host := parts[0]
-	port := ping.DefaultPort(edition)
-	hostOnly, hostPort, hasPort := splitHostPortLoose(host)
-	if hasPort {
-		host = hostOnly
-		port = hostPort
-	}
+	target.Host = parts[0]
 	if len(parts) > 1 {
-		parsed, err := ping.ParsePort(parts[1])
-		if err != nil {
-			return batchEntry{}, false, err
-		}
-		if parsed != 0 {
-			port = parsed
-		}
+		target.Port = parts[1]
 	}
-	if strings.TrimSpace(host) == "" {
-		return batchEntry{}, false, fmt.Errorf("missing host")
+	entry, err := target.entry(defaultEdition)
+	if err != nil {
+		return batchEntry{}, false, err
 	}
-	return batchEntry{
-		Name:    host,
-		Edition: edition,
-		Host:    host,
-		Port:    port,
-	}, true, nil
+	return entry, true, nil
 }
 
 func stripInlineComment(line string) string {
-	if index := strings.Index(line, "#"); index >= 0 {
-		return line[:index]
+	for i, r := range line {
+		if r == '#' && (i == 0 || line[i-1] == ' ' || line[i-1] == '\t') {
+			return line[:i]
+		}
 	}
 	return line
 }
@@ -215,9 +255,9 @@ func splitHostPortLoose(value string) (string, int, bool) {
 
 func (a *App) runBatchEntries(control *spinnerControl, entries []batchEntry, progress *batchProgress) []batchRunResult {
 	results := make([]batchRunResult, len(entries))
-	concurrency := resolveLookupConcurrency(a.settings.LookupConcurrency, len(entries))
-	if concurrency <= 0 {
-		concurrency = 1
+	controller := ping.NewLookupConcurrency(a.settings.LookupConcurrency, len(entries), a.settings.AdaptiveConcurrency)
+	if progress != nil {
+		progress.observeController(controller)
 	}
 
 	jobs := make(chan int)
@@ -225,41 +265,47 @@ func (a *App) runBatchEntries(control *spinnerControl, entries []batchEntry, pro
 
 	worker := func() {
 		defer wg.Done()
-		for index := range jobs {
+		for {
+			if controller.Acquire(control.Context()) != nil {
+				return
+			}
+			index, ok := <-jobs
+			if !ok {
+				controller.Release()
+				return
+			}
 			entry := entries[index]
 			if progress != nil {
 				progress.current.Store(fmt.Sprintf("%s:%d", entry.Host, entry.Port))
 			}
 			if waitWhilePaused(control) != nil {
+				controller.Release()
 				results[index] = batchRunResult{Entry: entry, Err: context.Canceled}
 				if progress != nil {
 					progress.completed.Add(1)
 				}
 				continue
 			}
-			result, details, err := ping.Execute(control.Context(), ping.ExecuteConfig{
-				Edition:    entry.Edition,
-				Host:       entry.Host,
-				Port:       entry.Port,
-				Timeout:    a.settings.RequestTimeout(),
-				RetryCount: a.settings.RetryCount,
-				RetryDelay: a.settings.RetryDelay(),
-				EnableSRV:  a.settings.EnableSRV,
-				IPMode:     a.settings.IPMode,
-			})
+			startedAt := time.Now()
+			config := a.batchExecuteConfig(entry)
+			result, details, err := ping.Execute(control.Context(), config)
+			controller.Observe(time.Since(startedAt), err)
+			controller.Release()
 			results[index] = batchRunResult{
 				Entry:   entry,
+				Config:  config,
 				Result:  result,
 				Details: details,
 				Err:     err,
 			}
 			if progress != nil {
+				progress.observeController(controller)
 				progress.completed.Add(1)
 			}
 		}
 	}
 
-	for i := 0; i < concurrency; i++ {
+	for i := 0; i < controller.Max(); i++ {
 		wg.Add(1)
 		go worker()
 	}
@@ -293,18 +339,35 @@ func waitWhilePaused(control *spinnerControl) error {
 	return nil
 }
 
-func formatBatchResults(title string, results []batchRunResult, parseErrors []string, canceled bool) string {
+func formatBatchResults(title string, results []batchRunResult, parseErrors []string, canceled bool, listUnresponsive bool) string {
 	var builder strings.Builder
-	success := 0
+	success, retried, recovered := 0, 0, 0
+	failures := make(map[ping.FailureClass]int)
+	unresponsive := make([]string, 0)
 	for _, result := range results {
+		if result.Retried > 0 {
+			retried++
+		}
 		if result.Err == nil {
 			success++
+			if result.Retried > 0 {
+				recovered++
+			}
+			continue
+		}
+		class := ping.ClassifyError(result.Err)
+		failures[class]++
+		if listUnresponsive && class != ping.FailureCanceled && result.Details.SelectedIP != "" {
+			unresponsive = append(unresponsive, fmt.Sprintf("%s %s:%d (%s) %s", result.Entry.Edition, result.Entry.Host, result.Entry.Port, result.Details.SelectedIP, class.Label()))
 		}
 	}
 	builder.WriteString("Summary\n")
 	builder.WriteString(fmt.Sprintf("- Targets: %d\n", len(results)))
 	builder.WriteString(fmt.Sprintf("- Online: %d\n", success))
 	builder.WriteString(fmt.Sprintf("- Failed: %d\n", len(results)-success))
+	if retried > 0 {
+		builder.WriteString(fmt.Sprintf("- Retried: %d (%d recovered)\n", retried, recovered))
+	}
 	if len(parseErrors) > 0 {
 		builder.WriteString(fmt.Sprintf("- Skipped lines: %d\n", len(parseErrors)))
 	}
@@ -312,15 +375,21 @@ func formatBatchResults(title string, results []batchRunResult, parseErrors []st
 		builder.WriteString("- Status: canceled\n")
 	}
 	builder.WriteString("\n")
+	builder.WriteString(formatFailureSection(failures, unresponsive))
 
 	builder.WriteString("Results\n")
 	for _, result := range results {
 		entry := result.Entry
+		target := batchEntryLabel(entry)
 		if result.Err != nil {
-			builder.WriteString(fmt.Sprintf("[ERR] %s %s:%d - %s\n", entry.Edition, entry.Host, entry.Port, result.Err))
+			builder.WriteString(fmt.Sprintf("[ERR] %s %s - %s: %s\n", entry.Edition, target, ping.ClassifyError(result.Err).Label(), result.Err))
 			continue
 		}
-		builder.WriteString(fmt.Sprintf("[OK]  %s %s:%d - %s\n", entry.Edition, entry.Host, entry.Port, compactResultStatus(result.Result)))
+		status := compactResultStatus(result.Result)
+		if result.Retried > 0 {
+			status += " (recovered on retry)"
+		}
+		builder.WriteString(fmt.Sprintf("[OK]  %s %s - %s\n", entry.Edition, target, status))
 	}
 	if len(parseErrors) > 0 {
 		builder.WriteString("\nSkipped\n")
@@ -332,6 +401,17 @@ func formatBatchResults(title string, results []batchRunResult, parseErrors []st
 	return strings.TrimRight(builder.String(), "\n")
 }
 
+func batchEntryLabel(entry batchEntry) string {
+	label := fmt.Sprintf("%s:%d", entry.Host, entry.Port)
+	if name := entry.displayName(); name != "" {
+		label = fmt.Sprintf("%s (%s)", name, label)
+	}
+	if len(entry.Tags) > 0 {
+		label += " [" + strings.Join(entry.Tags, ", ") + "]"
+	}
+	return label
+}
+
 func compactResultStatus(result ping.Result) string {
 	switch value := result.(type) {
 	case ping.BedrockPong:
@@ -348,7 +428,16 @@ func compactResultStatus(result ping.Result) string {
 func batchExportRecords(mode string, results []batchRunResult) []exportRecord {
 	records := make([]exportRecord, 0, len(results))
 	for _, result := range results {
-		records = append(records, newExportRecord(mode, result.Entry.Edition, result.Entry.Host, result.Entry.Port, result.Result, result.Details, nil, result.Err))
+		entry := result.Entry
+		record := newExportRecord(mode, entry.Edition, entry.Host, entry.Port, result.Result, result.Details, nil, result.Err)
+		record.Name = entry.displayName()
+		record.Tags = entry.Tags
+		record.Notes = entry.Notes
+		record.Retried = result.Retried
+		if result.Config.Edition != "" {
+			record.Probe = newExportProbe(result.Config, entry.Overrides, result.Details)
+		}
+		records = append(records, record)
 	}
 	return records
 }
@@ -451,5 +540,17 @@ func (p *batchProgress) Render(frame int, control *spinnerControl) string {
 	if terminalWidth() < 54 {
 		progressLine = fmt.Sprintf("Progress: %s %.1f%%", renderProgressBar(completed, total, frame, progressBarWidth()), percent)
 	}
-	return fmt.Sprintf("Status: %s\n%s\nTarget: %s", status, progressLine, current)
+	text := fmt.Sprintf("Status: %s\n%s\nTarget: %s", status, progressLine, current)
+	if p != nil && p.workers.Load() > 0 {
+		text += fmt.Sprintf("\nPipeline: %d workers", p.workers.Load())
+		if adjustment, ok := p.adjustment.Load().(string); ok && adjustment != "" && adjustment != "fixed" {
+			text += fmt.Sprintf("\nTuning: %s", adjustment)
+		}
+	}
+	return text
+}
+
+func (p *batchProgress) observeController(controller *ping.ConcurrencyController) {
+	p.workers.Store(int64(controller.Limit()))
+	p.adjustment.Store(controller.Reason())
 }
@@ -0,0 +1,655 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"UWP-TCP-Con/internal/ping"
+)
+
+const (
+	batchFormatLines = "lines"
+	batchFormatCSV   = "csv"
+	batchFormatJSON  = "json"
+	batchFormatYAML  = "yaml"
+)
+
+type batchTarget struct {
+	Line      int
+	Err       error
+	Name      string
+	Edition   string
+	Host      string
+	Port      string
+	Tags      []string
+	Notes     string
+	Overrides map[string]string
+}
+
+func (t *batchTarget) setOverride(field, value string) {
+	if t.Overrides == nil {
+		t.Overrides = make(map[string]string)
+	}
+	t.Overrides[field] = value
+}
+
+func (t batchTarget) entry(defaultEdition ping.Edition) (batchEntry, error) {
+	if t.Err != nil {
+		return batchEntry{}, t.Err
+	}
+	edition := defaultEdition
+	if strings.TrimSpace(t.Edition) != "" {
+		parsed, ok := parseEdition(t.Edition)
+		if !ok {
+			return batchEntry{}, fmt.Errorf("unknown edition %q", t.Edition)
+		}
+		edition = parsed
+	}
+	host := strings.TrimSpace(t.Host)
+	port := ping.DefaultPort(edition)
+	if hostOnly, hostPort, ok := splitHostPortLoose(host); ok {
+		host, port = hostOnly, hostPort
+	}
+	if strings.TrimSpace(t.Port) != "" {
+		parsed, err := ping.ParsePort(t.Port)
+		if err != nil {
+			return batchEntry{}, err
+		}
+		if parsed != 0 {
+			port = parsed
+		}
+	}
+	if host == "" {
+		return batchEntry{}, fmt.Errorf("missing host")
+	}
+	var overrides probeOverrides
+	for _, field := range probeOverrideFields {
+		if err := overrides.set(field, t.Overrides[field]); err != nil {
+			return batchEntry{}, err
+		}
+	}
+	if err := overrides.validate(edition); err != nil {
+		return batchEntry{}, err
+	}
+	name := strings.TrimSpace(t.Name)
+	if name == "" {
+		name = host
+	}
+	return batchEntry{
+		Name:      name,
+		Edition:   edition,
+		Host:      host,
+		Port:      port,
+		Tags:      t.Tags,
+		Notes:     strings.TrimSpace(t.Notes),
+		Overrides: overrides,
+	}, nil
+}
+
+func detectBatchFormat(path string, data []byte) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return batchFormatJSON
+	case ".yaml", ".yml":
+		return batchFormatYAML
+	case ".csv":
+		if csvHeaderColumns(firstContentLine(data)) != nil {
+			return batchFormatCSV
+		}
+		return batchFormatLines
+	}
+	first := firstContentLine(data)
+	switch {
+	case strings.HasPrefix(first, "[") || strings.HasPrefix(first, "{"):
+		return batchFormatJSON
+	case first == "---" || strings.HasPrefix(first, "- ") || isYAMLListKey(first):
+		return batchFormatYAML
+	case csvHeaderColumns(first) != nil:
+		return batchFormatCSV
+	default:
+		return batchFormatLines
+	}
+}
+
+func firstContentLine(data []byte) string {
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" && !strings.HasPrefix(line, "#") {
+			return line
+		}
+	}
+	return ""
+}
+
+func parseBatchData(path string, data []byte, defaultEdition ping.Edition) ([]batchEntry, []string, error) {
+	var targets []batchTarget
+	var err error
+	switch detectBatchFormat(path, data) {
+	case batchFormatCSV:
+		targets, err = parseBatchCSV(data)
+	case batchFormatJSON:
+		targets, err = parseBatchJSON(data)
+	case batchFormatYAML:
+		targets, err = parseBatchYAML(data)
+	default:
+		entries, parseErrors := parseBatchLines(data, defaultEdition)
+		return entries, parseErrors, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	entries := make([]batchEntry, 0, len(targets))
+	parseErrors := make([]string, 0)
+	for _, target := range targets {
+		entry, err := target.entry(defaultEdition)
+		if err != nil {
+			parseErrors = append(parseErrors, fmt.Sprintf("line %d: %v", target.Line, err))
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	return entries, parseErrors, nil
+}
+
+func parseBatchLines(data []byte, defaultEdition ping.Edition) ([]batchEntry, []string) {
+	lines := strings.Split(string(data), "\n")
+	entries := make([]batchEntry, 0, len(lines))
+	parseErrors := make([]string, 0)
+	for i, line := range lines {
+		entry, ok, err := parseBatchLine(line, defaultEdition)
+		if err != nil {
+			parseErrors = append(parseErrors, fmt.Sprintf("line %d: %v", i+1, err))
+			continue
+		}
+		if !ok {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	return entries, parseErrors
+}
+
+func csvHeaderColumns(line string) map[string]int {
+	if !strings.Contains(line, ",") {
+		return nil
+	}
+	record, err := csv.NewReader(strings.NewReader(line)).Read()
+	if err != nil {
+		return nil
+	}
+	return csvColumns(record)
+}
+
+func csvColumns(header []string) map[string]int {
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		key := batchFieldName(name)
+		if key == "" {
+			continue
+		}
+		if _, exists := columns[key]; !exists {
+			columns[key] = i
+		}
+	}
+	if _, ok := columns["host"]; !ok {
+		return nil
+	}
+	return columns
+}
+
+func batchFieldName(value string) string {
+	switch strings.NewReplacer("-", "_", " ", "_").Replace(strings.ToLower(strings.TrimSpace(value))) {
+	case "name", "label":
+		return "name"
+	case "edition", "type":
+		return "edition"
+	case "host", "address", "ip", "server":
+		return "host"
+	case "port":
+		return "port"
+	case "tags", "tag":
+		return "tags"
+	case "notes", "note", "comment":
+		return "notes"
+	case "timeout":
+		return "timeout"
+	case "retries", "retry", "retry_count":
+		return "retries"
+	case "srv", "enable_srv":
+		return "srv"
+	case "ip_mode", "ipmode":
+		return "ip_mode"
+	case "handshake_host", "handshake", "virtual_host":
+		return "handshake_host"
+	case "probe", "probe_kind":
+		return "probe"
+	default:
+		return ""
+	}
+}
+
+func parseBatchCSV(data []byte) ([]batchTarget, error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.Comment = '#'
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("read CSV header: %w", err)
+	}
+	columns := csvColumns(header)
+	if columns == nil {
+		return nil, fmt.Errorf("CSV header needs a host column")
+	}
+	field := func(record []string, name string) string {
+		index, ok := columns[name]
+		if !ok || index >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[index])
+	}
+
+	var targets []batchTarget
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) {
+				targets = append(targets, batchTarget{Line: parseErr.Line, Err: parseErr.Err})
+				continue
+			}
+			return nil, err
+		}
+		line, _ := reader.FieldPos(0)
+		if len(record) == 1 && strings.TrimSpace(record[0]) == "" {
+			continue
+		}
+		target := batchTarget{
+			Line:    line,
+			Name:    field(record, "name"),
+			Edition: field(record, "edition"),
+			Host:    field(record, "host"),
+			Port:    field(record, "port"),
+			Tags:    splitBatchTags(field(record, "tags")),
+			Notes:   field(record, "notes"),
+		}
+		for _, name := range probeOverrideFields {
+			if value := field(record, name); value != "" {
+				target.setOverride(name, value)
+			}
+		}
+		targets = append(targets, target)
+	}
+	return targets, nil
+}
+
+func splitBatchTags(value string) []string {
+	var tags []string
+	for _, tag := range strings.FieldsFunc(value, func(r rune) bool { return r == ';' || r == '|' || r == ',' }) {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+func parseBatchJSON(data []byte) ([]batchTarget, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := seekJSONTargetList(decoder); err != nil {
+		return nil, jsonLineError(data, decoder.InputOffset(), err)
+	}
+	var targets []batchTarget
+	for decoder.More() {
+		line := lineAtOffset(data, decoder.InputOffset())
+		var raw any
+		if err := decoder.Decode(&raw); err != nil {
+			return nil, jsonLineError(data, decoder.InputOffset(), err)
+		}
+		target, err := batchTargetFromValue(raw)
+		target.Line, target.Err = line, err
+		targets = append(targets, target)
+	}
+	return targets, nil
+}
+
+func seekJSONTargetList(decoder *json.Decoder) error {
+	token, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+	if token == json.Delim('[') {
+		return nil
+	}
+	if token != json.Delim('{') {
+		return fmt.Errorf("expected a list of targets")
+	}
+	for decoder.More() {
+		key, err := decoder.Token()
+		if err != nil {
+			return err
+		}
+		if name, ok := key.(string); ok && isTargetListKey(name) {
+			token, err := decoder.Token()
+			if err != nil {
+				return err
+			}
+			if token != json.Delim('[') {
+				return fmt.Errorf("%v must be a list", key)
+			}
+			return nil
+		}
+		var skip json.RawMessage
+		if err := decoder.Decode(&skip); err != nil {
+			return err
+		}
+	}
+	return fmt.Errorf("missing targets list")
+}
+
+func jsonLineError(data []byte, offset int64, err error) error {
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) && syntaxErr.Offset <= int64(len(data)) {
+		return fmt.Errorf("line %d: %w", bytes.Count(data[:syntaxErr.Offset], []byte("\n"))+1, err)
+	}
+	return fmt.Errorf("line %d: %w", lineAtOffset(data, offset), err)
+}
+
+func lineAtOffset(data []byte, offset int64) int {
+	if offset > int64(len(data)) {
+		offset = int64(len(data))
+	}
+	for offset < int64(len(data)) && strings.ContainsRune(" \t\r\n,", rune(data[offset])) {
+		offset++
+	}
+	return bytes.Count(data[:offset], []byte("\n")) + 1
+}
+
+func batchTargetFromValue(value any) (batchTarget, error) {
+	switch typed := value.(type) {
+	case string:
+		return batchTargetFromLine(typed)
+	case map[string]any:
+		var target batchTarget
+		keys := make([]string, 0, len(typed))
+		for key := range typed {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		seen := make(map[string]string, len(keys))
+		for _, key := range keys {
+			raw := typed[key]
+			text := scalarText(raw)
+			field := batchFieldName(key)
+			if other, ok := seen[field]; ok && field != "" {
+				return batchTarget{}, fmt.Errorf("%q and %q both set the %s", other, key, field)
+			}
+			seen[field] = key
+			switch field {
+			case "name":
+				target.Name = text
+			case "edition":
+				target.Edition = text
+			case "host":
+				target.Host = text
+			case "port":
+				target.Port = text
+			case "notes":
+				target.Notes = text
+			case "tags":
+				if list, ok := raw.([]any); ok {
+					for _, item := range list {
+						if tag := strings.TrimSpace(scalarText(item)); tag != "" {
+							target.Tags = append(target.Tags, tag)
+						}
+					}
+				} else {
+					target.Tags = splitBatchTags(text)
+				}
+			default:
+				if isProbeOverrideField(field) {
+					target.setOverride(field, text)
+				}
+			}
+		}
+		return target, nil
+	default:
+		return batchTarget{}, fmt.Errorf("target must be an object or a string")
+	}
+}
+
+func batchTargetFromLine(value string) (batchTarget, error) {
+	var target batchTarget
+	fields, err := splitOverrideTokens(&target, strings.Fields(strings.ReplaceAll(value, ",", " ")))
+	if err != nil {
+		return batchTarget{}, err
+	}
+	if len(fields) == 0 {
+		return batchTarget{}, fmt.Errorf("missing host")
+	}
+	if _, ok := parseEdition(fields[0]); ok {
+		target.Edition = fields[0]
+		fields = fields[1:]
+	}
+	if len(fields) == 0 {
+		return batchTarget{}, fmt.Errorf("missing host")
+	}
+	target.Host = fields[0]
+	if len(fields) > 1 {
+		target.Port = fields[1]
+	}
+	return target, nil
+}
+
+func splitOverrideTokens(target *batchTarget, tokens []string) ([]string, error) {
+	rest := tokens[:0:0]
+	for _, token := range tokens {
+		key, value, ok := strings.Cut(token, "=")
+		if !ok {
+			rest = append(rest, token)
+			continue
+		}
+		name := batchFieldName(key)
+		if !isProbeOverrideField(name) {
+			return nil, fmt.Errorf("unknown override %q", key)
+		}
+		target.setOverride(name, value)
+	}
+	return rest, nil
+}
+
+func scalarText(value any) string {
+	switch typed := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return typed
+	case json.Number:
+		return typed.String()
+	default:
+		return fmt.Sprint(typed)
+	}
+}
+
+func isTargetListKey(name string) bool {
+	switch name {
+	case "targets", "servers", "entries":
+		return true
+	default:
+		return false
+	}
+}
+
+func isYAMLListKey(line string) bool {
+	line = strings.TrimSpace(line)
+	return strings.HasSuffix(line, ":") && isTargetListKey(strings.TrimSuffix(line, ":"))
+}
+
+type yamlLine struct {
+	number int
+	indent int
+	text   string
+}
+
+func parseBatchYAML(data []byte) ([]batchTarget, error) {
+	var lines []yamlLine
+	for i, raw := range strings.Split(string(data), "\n") {
+		text := strings.TrimRight(stripYAMLComment(raw), " \t\r")
+		if strings.TrimSpace(text) == "" || strings.TrimSpace(text) == "---" {
+			continue
+		}
+		trimmed := strings.TrimLeft(text, " \t")
+		if strings.Contains(text[:len(text)-len(trimmed)], "\t") {
+			return nil, fmt.Errorf("line %d: tabs are not allowed for indentation", i+1)
+		}
+		indent := len(text) - len(trimmed)
+		lines = append(lines, yamlLine{number: i + 1, indent: indent, text: strings.TrimSpace(text)})
+	}
+
+	var targets []batchTarget
+	var current map[string]any
+	var currentLine int
+	itemIndent := -1
+	var listKey string
+	flush := func() {
+		if current == nil {
+			return
+		}
+		target, err := batchTargetFromValue(current)
+		target.Line, target.Err = currentLine, err
+		targets = append(targets, target)
+		current = nil
+	}
+
+	for _, line := range lines {
+		switch {
+		case line.indent == 0 && isYAMLListKey(line.text):
+			flush()
+			continue
+		case listKey != "" && line.indent > itemIndent && strings.HasPrefix(line.text, "- "):
+			list, _ := current[listKey].([]any)
+			current[listKey] = append(list, yamlScalar(strings.TrimSpace(line.text[2:])))
+			continue
+		case strings.HasPrefix(line.text, "- ") || line.text == "-":
+			if itemIndent < 0 {
+				itemIndent = line.indent
+			}
+			if line.indent != itemIndent {
+				return nil, fmt.Errorf("line %d: unexpected indentation", line.number)
+			}
+			flush()
+			listKey = ""
+			rest := strings.TrimSpace(strings.TrimPrefix(line.text, "-"))
+			key, value, isMapping := splitYAMLPair(rest)
+			if !isMapping {
+				target, err := batchTargetFromLine(yamlScalar(rest).(string))
+				target.Line, target.Err = line.number, err
+				targets = append(targets, target)
+				continue
+			}
+			current = map[string]any{}
+			currentLine = line.number
+			if value == "" {
+				listKey = key
+				current[key] = []any{}
+			} else {
+				current[key] = yamlValue(value)
+			}
+		case current != nil && line.indent > itemIndent:
+			key, value, ok := splitYAMLPair(line.text)
+			if !ok {
+				return nil, fmt.Errorf("line %d: expected key: value", line.number)
+			}
+			listKey = ""
+			if value == "" {
+				listKey = key
+				current[key] = []any{}
+				continue
+			}
+			current[key] = yamlValue(value)
+		default:
+			return nil, fmt.Errorf("line %d: expected a list item", line.number)
+		}
+	}
+	flush()
+	return targets, nil
+}
+
+func splitYAMLPair(text string) (string, string, bool) {
+	if strings.HasPrefix(text, `"`) || strings.HasPrefix(text, "'") {
+		return "", "", false
+	}
+	index := strings.Index(text, ": ")
+	if index < 0 {
+		if strings.HasSuffix(text, ":") {
+			index = len(text) - 1
+		} else {
+			return "", "", false
+		}
+	}
+	key := strings.TrimSpace(text[:index])
+	if key == "" || strings.ContainsAny(key, " []{}") {
+		return "", "", false
+	}
+	return key, strings.TrimSpace(text[index+1:]), true
+}
+
+func yamlValue(value string) any {
+	if strings.HasPrefix(value, "[") && strings.HasSuffix(value, "]") {
+		items := []any{}
+		for _, item := range strings.Split(value[1:len(value)-1], ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, yamlScalar(item))
+			}
+		}
+		return items
+	}
+	return yamlScalar(value)
+}
+
+func yamlScalar(value string) any {
+	if len(value) >= 2 {
+		switch {
+		case value[0] == '"' && value[len(value)-1] == '"':
+			if unquoted, err := strconv.Unquote(value); err == nil {
+				return unquoted
+			}
+		case value[0] == '\'' && value[len(value)-1] == '\'':
+			return strings.ReplaceAll(value[1:len(value)-1], "''", "'")
+		}
+	}
+	return value
+}
+
+func stripYAMLComment(line string) string {
+	quote := rune(0)
+	previous := ' '
+	for i, r := range line {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			if previous == ' ' || previous == '\t' || previous == ':' || previous == '[' || previous == ',' || previous == '-' {
+				quote = r
+			}
+		case r == '#' && (previous == ' ' || previous == '\t'):
+			return line[:i]
+		}
+		previous = r
+	}
+	return line
+}
@@ -0,0 +1,178 @@
+package cli
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"UWP-TCP-Con/internal/ping"
+)
+
+func TestParseBatchCSVWithHeader(t *testing.T) {
+	data := []byte("# lobby servers\nport,notes,host,edition,name,tags\n25566,\"Hub #1, main\",play.example.com,java,Hub,pvp;eu\n,,geo.example.com:19133,bedrock,,\n\"unterminated,x\n")
+	entries, parseErrors, err := parseBatchData("targets.csv", data, ping.EditionBedrock)
+	if err != nil {
+		t.Fatalf("parseBatchData: %v", err)
+	}
+	want := []batchEntry{
+		{Name: "Hub", Edition: ping.EditionJava, Host: "play.example.com", Port: 25566, Tags: []string{"pvp", "eu"}, Notes: "Hub #1, main"},
+		{Name: "geo.example.com", Edition: ping.EditionBedrock, Host: "geo.example.com", Port: 19133},
+	}
+	if !reflect.DeepEqual(entries, want) {
+		t.Fatalf("unexpected entries %#v", entries)
+	}
+	if len(parseErrors) != 1 || !strings.HasPrefix(parseErrors[0], "line 5:") {
+		t.Fatalf("expected a line 5 error, got %q", parseErrors)
+	}
+}
+
+func TestParseBatchJSON(t *testing.T) {
+	data := []byte(`{
+  "version": 1,
+  "targets": [
+    {"name": "Hub", "host": "play.example.com", "port": 25566, "edition": "java", "tags": ["pvp", "eu"], "notes": "main"},
+    "bedrock geo.example.com:19133",
+    {"name": "Broken", "edition": "java"},
+    42,
+    {"host": "a.example.com", "address": "b.example.com"}
+  ]
+}`)
+	entries, parseErrors, err := parseBatchData("targets.txt", data, ping.EditionBedrock)
+	if err != nil {
+		t.Fatalf("parseBatchData: %v", err)
+	}
+	if len(entries) != 2 || entries[0].Port != 25566 || !reflect.DeepEqual(entries[0].Tags, []string{"pvp", "eu"}) || entries[1].Port != 19133 {
+		t.Fatalf("unexpected entries %#v", entries)
+	}
+	if !reflect.DeepEqual(parseErrors, []string{"line 6: missing host", "line 7: target must be an object or a string", `line 8: "address" and "host" both set the host`}) {
+		t.Fatalf("unexpected parse errors %q", parseErrors)
+	}
+
+	_, _, err = parseBatchData("targets.json", []byte("[\n  {\"host\": \"a\"},\n  {\"host\": }\n]"), ping.EditionJava)
+	if err == nil || !strings.HasPrefix(err.Error(), "line 3:") {
+		t.Fatalf("expected a line 3 syntax error, got %v", err)
+	}
+}
+
+func TestParseBatchYAML(t *testing.T) {
+	data := []byte(`# weekly check
+targets:
+  - name: "Hub #1"
+    host: play.example.com
+    port: 25566 # custom port
+    edition: java
+    tags: [pvp, eu]
+  - host: geo.example.com
+    tags:
+      - bedrock
+      - 'asia'
+  - host: a.example.com
+    address: b.example.com
+  - java mc.example.net
+`)
+	entries, parseErrors, err := parseBatchData("targets.yml", data, ping.EditionBedrock)
+	if err != nil {
+		t.Fatalf("parseBatchData: %v", err)
+	}
+	if !reflect.DeepEqual(parseErrors, []string{`line 12: "address" and "host" both set the host`}) {
+		t.Fatalf("unexpected parse errors %q", parseErrors)
+	}
+	want := []batchEntry{
+		{Name: "Hub #1", Edition: ping.EditionJava, Host: "play.example.com", Port: 25566, Tags: []string{"pvp", "eu"}},
+		{Name: "geo.example.com", Edition: ping.EditionBedrock, Host: "geo.example.com", Port: 19132, Tags: []string{"bedrock", "asia"}},
+		{Name: "mc.example.net", Edition: ping.EditionJava, Host: "mc.example.net", Port: 25565},
+	}
+	if !reflect.DeepEqual(entries, want) {
+		t.Fatalf("unexpected entries %#v", entries)
+	}
+
+	_, _, err = parseBatchData("targets.yaml", []byte("- host: a\n\thost: b\n"), ping.EditionJava)
+	if err == nil || !strings.HasPrefix(err.Error(), "line 2:") {
+		t.Fatalf("expected a line 2 error, got %v", err)
+	}
+}
+
+func TestDetectBatchFormat(t *testing.T) {
+	for _, tc := range []struct {
+		path string
+		data string
+		want string
+	}{
+		{"list.txt", "java play.example.com\n", batchFormatLines},
+		{"list.csv", "java,play.example.com,25565\n", batchFormatLines},
+		{"list.csv", "host,port\nplay.example.com,25565\n", batchFormatCSV},
+		{"list", "# header\nHost,Edition\n", batchFormatCSV},
+		{"list", "  [\"play.example.com\"]", batchFormatJSON},
+		{"list", "servers:\n  - a\n", batchFormatYAML},
+		{"list", "- a\n", batchFormatYAML},
+		{"list.yaml", "", batchFormatYAML},
+	} {
+		if got := detectBatchFormat(tc.path, []byte(tc.data)); got != tc.want {
+			t.Fatalf("%s %q: got %s, want %s", tc.path, tc.data, got, tc.want)
+		}
+	}
+}
+
+func TestParseBatchLineKeepsHashInsideValue(t *testing.T) {
+	entry, ok, err := parseBatchLine("java play#1.example.com 25566 # lobby", ping.EditionBedrock)
+	if err != nil || !ok {
+		t.Fatalf("parseBatchLine: ok=%v err=%v", ok, err)
+	}
+	if entry.Host != "play#1.example.com" || entry.Port != 25566 {
+		t.Fatalf("unexpected entry %#v", entry)
+	}
+	if _, ok, _ := parseBatchLine("# java play.example.com", ping.EditionBedrock); ok {
+		t.Fatalf("expected comment line to be skipped")
+	}
+}
+
+func TestBatchProbeOverrides(t *testing.T) {
+	data := []byte("host,edition,timeout,retries,srv,ip_mode,handshake_host,probe\n" +
+		"far.example.com,bedrock,12s,3,,ipv4,,\n" +
+		"local.example.com,java,1500ms,0,off,,lobby.example.com,legacy\n" +
+		"geo.example.com,bedrock,,,,,,legacy\n" +
+		"bad.example.com,java,soon,,,,,\n")
+	entries, parseErrors, err := parseBatchData("targets.csv", data, ping.EditionJava)
+	if err != nil {
+		t.Fatalf("parseBatchData: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %#v", entries)
+	}
+	if !reflect.DeepEqual(parseErrors, []string{
+		"line 4: legacy probe is only available for Java",
+		`line 5: timeout: invalid duration "soon"`,
+	}) {
+		t.Fatalf("unexpected parse errors %q", parseErrors)
+	}
+
+	app := &App{settings: defaultSettings()}
+	far := app.batchExecuteConfig(entries[0])
+	if far.Timeout != 12*time.Second || far.RetryCount != 3 || far.EnableSRV != app.settings.EnableSRV || far.IPMode != ping.IPModeIPv4 || far.Probe != ping.ProbeStatus {
+		t.Fatalf("unexpected config %#v", far)
+	}
+	local := app.batchExecuteConfig(entries[1])
+	if local.Timeout != 1500*time.Millisecond || local.RetryCount != 0 || local.EnableSRV || local.HandshakeHost != "lobby.example.com" || local.Probe != ping.ProbeLegacy {
+		t.Fatalf("unexpected config %#v", local)
+	}
+
+	record := batchExportRecords("batch", []batchRunResult{{Entry: entries[1], Config: local}})[0]
+	if record.Probe == nil || record.Probe.TimeoutMillis != 1500 || record.Probe.Kind != "legacy" ||
+		!reflect.DeepEqual(record.Probe.Overridden, []string{"timeout", "retries", "srv", "handshake_host", "probe"}) {
+		t.Fatalf("unexpected export probe %#v", record.Probe)
+	}
+}
+
+func TestParseBatchLineOverrides(t *testing.T) {
+	entry, ok, err := parseBatchLine("java play.example.com 25566 timeout=10 srv=no", ping.EditionBedrock)
+	if err != nil || !ok {
+		t.Fatalf("parseBatchLine: ok=%v err=%v", ok, err)
+	}
+	if entry.Port != 25566 || entry.Overrides.Timeout != 10*time.Second || entry.Overrides.SRV == nil || *entry.Overrides.SRV {
+		t.Fatalf("unexpected entry %#v", entry)
+	}
+	if _, _, err := parseBatchLine("java play.example.com colour=red", ping.EditionBedrock); err == nil {
+		t.Fatalf("expected unknown override error")
+	}
+}
@@ -0,0 +1,207 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"UWP-TCP-Con/internal/ping"
+)
+
+var probeOverrideFields = []string{"timeout", "retries", "srv", "ip_mode", "handshake_host", "probe"}
+
+type probeOverrides struct {
+	Timeout       time.Duration
+	Retries       *int
+	SRV           *bool
+	IPMode        ping.IPMode
+	HandshakeHost string
+	Probe         ping.ProbeKind
+}
+
+type exportProbe struct {
+	TimeoutMillis int64    `json:"timeout_ms"`
+	Retries       int      `json:"retries"`
+	SRV           bool     `json:"srv"`
+	IPMode        string   `json:"ip_mode"`
+	HandshakeHost string   `json:"handshake_host,omitempty"`
+	Kind          string   `json:"kind"`
+	Attempts      int      `json:"attempts,omitempty"`
+	Overridden    []string `json:"overridden,omitempty"`
+}
+
+func isProbeOverrideField(name string) bool {
+	for _, field := range probeOverrideFields {
+		if field == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (o *probeOverrides) set(field, value string) error {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil
+	}
+	switch field {
+	case "timeout":
+		timeout, err := parseOverrideDuration(value)
+		if err != nil {
+			return fmt.Errorf("timeout: %w", err)
+		}
+		o.Timeout = timeout
+	case "retries":
+		retries, err := strconv.Atoi(value)
+		if err != nil || retries < 0 {
+			return fmt.Errorf("retries: expected a number of 0 or more")
+		}
+		o.Retries = &retries
+	case "srv":
+		enabled, ok := parseOverrideBool(value)
+		if !ok {
+			return fmt.Errorf("srv: expected on or off")
+		}
+		o.SRV = &enabled
+	case "ip_mode":
+		mode, ok := parseIPModeValue(value)
+		if !ok {
+			return fmt.Errorf("ip_mode: expected auto, ipv4 or ipv6")
+		}
+		o.IPMode = mode
+	case "handshake_host":
+		o.HandshakeHost = value
+	case "probe":
+		kind, ok := ping.ParseProbeKind(value)
+		if !ok {
+			return fmt.Errorf("probe: expected status or legacy")
+		}
+		o.Probe = kind
+	default:
+		return fmt.Errorf("unknown override %q", field)
+	}
+	return nil
+}
+
+func (o probeOverrides) validate(edition ping.Edition) error {
+	if edition == ping.EditionJava {
+		return nil
+	}
+	if o.HandshakeHost != "" {
+		return fmt.Errorf("handshake_host is only used by Java")
+	}
+	if o.Probe == ping.ProbeLegacy {
+		return fmt.Errorf("legacy probe is only available for Java")
+	}
+	return nil
+}
+
+func (o probeOverrides) fields() []string {
+	var fields []string
+	if o.Timeout > 0 {
+		fields = append(fields, "timeout")
+	}
+	if o.Retries != nil {
+		fields = append(fields, "retries")
+	}
+	if o.SRV != nil {
+		fields = append(fields, "srv")
+	}
+	if o.IPMode != "" {
+		fields = append(fields, "ip_mode")
+	}
+	if o.HandshakeHost != "" {
+		fields = append(fields, "handshake_host")
+	}
+	if o.Probe != "" {
+		fields = append(fields, "probe")
+	}
+	return fields
+}
+
+func parseOverrideDuration(value string) (time.Duration, error) {
+	if seconds, err := strconv.ParseFloat(value, 64); err == nil {
+		if seconds <= 0 {
+			return 0, fmt.Errorf("must be positive")
+		}
+		return time.Duration(seconds * float64(time.Second)), nil
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %q", value)
+	}
+	if duration <= 0 {
+		return 0, fmt.Errorf("must be positive")
+	}
+	return duration, nil
+}
+
+func parseOverrideBool(value string) (bool, bool) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "true", "on", "yes", "1", "enabled":
+		return true, true
+	case "false", "off", "no", "0", "disabled":
+		return false, true
+	default:
+		return false, false
+	}
+}
+
+func parseIPModeValue(value string) (ping.IPMode, bool) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "auto":
+		return ping.IPModeAuto, true
+	case "ipv4", "ip4", "4":
+		return ping.IPModeIPv4, true
+	case "ipv6", "ip6", "6":
+		return ping.IPModeIPv6, true
+	default:
+		return "", false
+	}
+}
+
+func (a *App) batchExecuteConfig(entry batchEntry) ping.ExecuteConfig {
+	config := ping.ExecuteConfig{
+		Edition:       entry.Edition,
+		Host:          entry.Host,
+		Port:          entry.Port,
+		Timeout:       a.settings.RequestTimeout(),
+		RetryCount:    a.settings.RetryCount,
+		RetryDelay:    a.settings.RetryDelay(),
+		EnableSRV:     a.settings.EnableSRV,
+		IPMode:        a.settings.IPMode,
+		HandshakeHost: entry.Overrides.HandshakeHost,
+		Probe:         ping.ProbeStatus,
+	}
+	overrides := entry.Overrides
+	if overrides.Timeout > 0 {
+		config.Timeout = overrides.Timeout
+	}
+	if overrides.Retries != nil {
+		config.RetryCount = *overrides.Retries
+	}
+	if overrides.SRV != nil {
+		config.EnableSRV = *overrides.SRV
+	}
+	if overrides.IPMode != "" {
+		config.IPMode = overrides.IPMode
+	}
+	if overrides.Probe != "" {
+		config.Probe = overrides.Probe
+	}
+	return config
+}
+
+func newExportProbe(config ping.ExecuteConfig, overrides probeOverrides, details ping.ExecuteDetails) *exportProbe {
+	return &exportProbe{
+		TimeoutMillis: config.Timeout.Milliseconds(),
+		Retries:       config.RetryCount,
+		SRV:           config.EnableSRV,
+		IPMode:        string(config.IPMode),
+		HandshakeHost: config.HandshakeHost,
+		Kind:          string(config.Probe),
+		Attempts:      details.Attempts,
+		Overridden:    overrides.fields(),
+	}
+}
@@ -0,0 +1,284 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"UWP-TCP-Con/internal/ping"
+)
+
+func failedBatchIndexes(results []batchRunResult) []int {
+	var indexes []int
+	for i, result := range results {
+		if result.Err != nil {
+			indexes = append(indexes, i)
+		}
+	}
+	return indexes
+}
+
+func batchSubset(results []batchRunResult, indexes []int) []batchEntry {
+	entries := make([]batchEntry, 0, len(indexes))
+	for _, index := range indexes {
+		entries = append(entries, results[index].Entry)
+	}
+	return entries
+}
+
+func mergeBatchRetry(results []batchRunResult, indexes []int, retried []batchRunResult) []batchRunResult {
+	merged := append([]batchRunResult(nil), results...)
+	for i, index := range indexes {
+		if i >= len(retried) {
+			break
+		}
+		next := retried[i]
+		next.Retried = results[index].Retried + 1
+		merged[index] = next
+	}
+	return merged
+}
+
+func loadExportPayload(path string) (exportPayload, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return exportPayload{}, err
+	}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		records, err := readCSVExport(bytes.NewReader(data))
+		if err != nil {
+			return exportPayload{}, fmt.Errorf("%s: %w", path, err)
+		}
+		return exportPayload{Records: records}, nil
+	case ".ndjson":
+		payload, err := readNDJSONExport(data)
+		if err != nil {
+			return exportPayload{}, fmt.Errorf("%s: %w", path, err)
+		}
+		return payload, nil
+	}
+	var payload exportPayload
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return exportPayload{}, fmt.Errorf("%s is not a JSON export: %w", path, err)
+	}
+	return payload, nil
+}
+
+func failedRecordEntries(records []exportRecord) ([]batchEntry, []int, error) {
+	var entries []batchEntry
+	var indexes []int
+	for i, record := range records {
+		if record.Success {
+			continue
+		}
+		entry, err := recordBatchEntry(record)
+		if err != nil {
+			return nil, nil, fmt.Errorf("record %d: %w", i+1, err)
+		}
+		entries = append(entries, entry)
+		indexes = append(indexes, i)
+	}
+	return entries, indexes, nil
+}
+
+func recordBatchEntry(record exportRecord) (batchEntry, error) {
+	target := batchTarget{
+		Name:    record.Name,
+		Edition: record.Edition,
+		Host:    record.Host,
+		Tags:    record.Tags,
+		Notes:   record.Notes,
+	}
+	if record.Port > 0 {
+		target.Port = fmt.Sprint(record.Port)
+	}
+	if probe := record.Probe; probe != nil {
+		for _, field := range probe.Overridden {
+			switch field {
+			case "timeout":
+				target.setOverride(field, (time.Duration(probe.TimeoutMillis) * time.Millisecond).String())
+			case "retries":
+				target.setOverride(field, fmt.Sprint(probe.Retries))
+			case "srv":
+				target.setOverride(field, fmt.Sprint(probe.SRV))
+			case "ip_mode":
+				target.setOverride(field, probe.IPMode)
+			case "handshake_host":
+				target.setOverride(field, probe.HandshakeHost)
+			case "probe":
+				target.setOverride(field, probe.Kind)
+			}
+		}
+	}
+	edition, ok := parseEdition(record.Edition)
+	if !ok {
+		return batchEntry{}, fmt.Errorf("unknown edition %q", record.Edition)
+	}
+	return target.entry(edition)
+}
+
+func mergeRetryRecords(records []exportRecord, indexes []int, retried []exportRecord) []exportRecord {
+	merged := append([]exportRecord(nil), records...)
+	for i, index := range indexes {
+		if i >= len(retried) {
+			break
+		}
+		next := retried[i]
+		next.Mode = records[index].Mode
+		next.Source = records[index].Source
+		next.Retried = records[index].Retried + 1
+		merged[index] = next
+	}
+	return merged
+}
+
+func (a *App) runBatchCommand(args []string) error {
+	flags := newFlagSet("batch")
+	editionName := flags.String("edition", string(ping.EditionBedrock), "edition for entries that do not name one (bedrock or java)")
+	retryFailed := flags.String("retry-failed", "", "previous JSON export; re-probe only the records with success:false and merge the outcome")
+	output := flags.String("o", "", "write the report to this file; the extension picks the format (default JSON, or the results directory when saving is enabled)")
+	where := flags.String("where", "", `only report servers matching this filter expression, e.g. 'players > 0 && version ~ "1.20"'`)
+	sortKeys := flags.String("sort", "", "sort the report by these keys, e.g. 'players desc, host'")
+	if err := parseCommandFlags(flags, args); err != nil {
+		return err
+	}
+	filter, err := parseResultFilter(*where)
+	if err != nil {
+		return fmt.Errorf("-where: %w", err)
+	}
+	order, err := parseResultSort(*sortKeys)
+	if err != nil {
+		return fmt.Errorf("-sort: %w", err)
+	}
+	defaultEdition, ok := parseEdition(*editionName)
+	if !ok {
+		return fmt.Errorf("edition must be java or bedrock")
+	}
+
+	title := "Batch check"
+	var previous []exportRecord
+	var entries []batchEntry
+	var indexes []int
+	var parseErrors []string
+	switch {
+	case *retryFailed != "":
+		if flags.NArg() > 0 {
+			return fmt.Errorf("use either a batch file or -retry-failed")
+		}
+		payload, err := loadExportPayload(*retryFailed)
+		if err != nil {
+			return err
+		}
+		if payload.Title != "" {
+			title = payload.Title
+		}
+		previous = payload.Records
+		entries, indexes, err = failedRecordEntries(previous)
+		if err != nil {
+			return err
+		}
+		if len(entries) == 0 {
+			fmt.Printf("No failed records in %s\n", *retryFailed)
+			return nil
+		}
+		fmt.Fprintf(os.Stderr, "Retrying %d of %d records from %s\n", len(entries), len(previous), *retryFailed)
+	case flags.NArg() == 1:
+		var err error
+		entries, parseErrors, err = loadBatchEntries(flags.Arg(0), defaultEdition)
+		if err != nil {
+			return err
+		}
+		if len(entries) == 0 {
+			return fmt.Errorf("no valid entries found in %s", flags.Arg(0))
+		}
+	default:
+		return fmt.Errorf("usage: batch [flags] <file> or batch -retry-failed <export.json>")
+	}
+
+	ctx, cancel := commandContext()
+	defer cancel()
+	control := newSpinnerControl(ctx)
+	defer control.Cancel()
+	runResults := a.runBatchEntries(control, entries, nil)
+	canceled := ctx.Err() != nil
+
+	records := batchExportRecords("batch", runResults)
+	shown, shownRecords := viewBatchResults(runResults, records, filter, order)
+	text := formatBatchResults(title, shown, parseErrors, canceled, a.settings.ReportUnresponsive)
+	if previous != nil {
+		records = mergeRetryRecords(previous, indexes, records)
+		text = formatRetrySummary(records, indexes) + "\n\n" + text
+		records = filter.Apply(records)
+		order.Apply(records)
+	} else {
+		records = shownRecords
+	}
+	fmt.Println(text)
+
+	path := strings.TrimSpace(*output)
+	switch {
+	case path != "":
+		format := savedResultFormat(path)
+		if format == "" {
+			format = exportFormatJSON
+		}
+		if err := writeExport(path, format, title, text, records); err != nil {
+			return err
+		}
+	case a.settings.SaveResults:
+		saved, err := a.saveExport(title, text, records)
+		if err != nil {
+			return err
+		}
+		path = saved
+	}
+	if path != "" {
+		fmt.Printf("\nSaved result: %s\n", path)
+	}
+	return nil
+}
+
+func viewBatchResults(results []batchRunResult, records []exportRecord, filter *resultFilter, order resultSort) ([]batchRunResult, []exportRecord) {
+	if filter == nil && len(order) == 0 {
+		return results, records
+	}
+	type pair struct {
+		result batchRunResult
+		record exportRecord
+	}
+	pairs := make([]pair, 0, len(results))
+	for i, result := range results {
+		if filter.Match(records[i]) {
+			pairs = append(pairs, pair{result, records[i]})
+		}
+	}
+	sort.SliceStable(pairs, func(i, j int) bool { return order.Less(pairs[i].record, pairs[j].record) })
+	results = make([]batchRunResult, len(pairs))
+	records = make([]exportRecord, len(pairs))
+	for i, item := range pairs {
+		results[i], records[i] = item.result, item.record
+	}
+	return results, records
+}
+
+func formatRetrySummary(records []exportRecord, indexes []int) string {
+	recovered := 0
+	for _, index := range indexes {
+		if records[index].Success {
+			recovered++
+		}
+	}
+	online := 0
+	for _, record := range records {
+		if record.Success {
+			online++
+		}
+	}
+	return fmt.Sprintf("Merged report\n- Records: %d\n- Retried: %d (%d recovered)\n- Online: %d\n- Failed: %d", len(records), len(indexes), recovered, online, len(records)-online)
+}
@@ -0,0 +1,93 @@
+package cli
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"UWP-TCP-Con/internal/ping"
+)
+
+func TestFormatBatchResultsCountsFailureClasses(t *testing.T) {
+	timeout := &ping.ProbeError{Class: ping.FailureReadTimeout, Err: errors.New("timeout while pinging a:19132")}
+	refused := &ping.ProbeError{Class: ping.FailureConnRefused, Err: errors.New("connection refused")}
+	results := []batchRunResult{
+		{Entry: batchEntry{Edition: ping.EditionBedrock, Host: "a", Port: 19132}, Err: timeout, Details: ping.ExecuteDetails{SelectedIP: "192.0.2.1"}},
+		{Entry: batchEntry{Edition: ping.EditionJava, Host: "b", Port: 25565}, Err: refused},
+		{Entry: batchEntry{Edition: ping.EditionJava, Host: "c", Port: 25565}, Err: refused},
+	}
+
+	text := formatBatchResults("Batch check", results, nil, false, true)
+	for _, want := range []string{
+		"- Read timeout: 1",
+		"- Connection refused: 2",
+		"Unresponsive\n- bedrock a:19132 (192.0.2.1) read timeout",
+	} {
+		if !strings.Contains(text, want) {
+			t.Fatalf("expected %q in %q", want, text)
+		}
+	}
+
+	text = formatBatchResults("Batch check", results, nil, false, false)
+	if strings.Contains(text, "Unresponsive") {
+		t.Fatalf("did not expect unresponsive list in %q", text)
+	}
+}
+
+func TestMergeBatchRetry(t *testing.T) {
+	refused := &ping.ProbeError{Class: ping.FailureConnRefused, Err: errors.New("connection refused")}
+	results := []batchRunResult{
+		{Entry: batchEntry{Edition: ping.EditionJava, Host: "a", Port: 25565}, Err: refused},
+		{Entry: batchEntry{Edition: ping.EditionJava, Host: "b", Port: 25565}, Result: ping.JavaStatus{VersionName: "1.21"}},
+		{Entry: batchEntry{Edition: ping.EditionJava, Host: "c", Port: 25565}, Err: refused},
+	}
+	failed := failedBatchIndexes(results)
+	if len(failed) != 2 || failed[0] != 0 || failed[1] != 2 {
+		t.Fatalf("unexpected failed indexes %v", failed)
+	}
+	subset := batchSubset(results, failed)
+	retried := []batchRunResult{
+		{Entry: subset[0], Result: ping.JavaStatus{VersionName: "1.20"}},
+		{Entry: subset[1], Err: refused},
+	}
+	merged := mergeBatchRetry(results, failed, retried)
+	if merged[0].Err != nil || merged[0].Retried != 1 || merged[1].Retried != 0 || merged[2].Err == nil || merged[2].Retried != 1 {
+		t.Fatalf("unexpected merge %#v", merged)
+	}
+	text := formatBatchResults("Batch check", merged, nil, false, false)
+	for _, want := range []string{"- Online: 2", "- Retried: 2 (1 recovered)", "[OK]  java a:25565 - 1.20 players 0/0 latency 0ms (recovered on retry)"} {
+		if !strings.Contains(text, want) {
+			t.Fatalf("expected %q in %q", want, text)
+		}
+	}
+}
+
+func TestFailedRecordEntries(t *testing.T) {
+	records := []exportRecord{
+		{Mode: "batch", Edition: "java", Host: "up.example.com", Port: 25565, Success: true},
+		{Mode: "batch", Name: "Far", Edition: "bedrock", Host: "far.example.com", Port: 19133, Tags: []string{"asia"},
+			Probe: &exportProbe{TimeoutMillis: 12000, Retries: 3, SRV: true, IPMode: "ipv4", Kind: "status", Overridden: []string{"timeout", "ip_mode"}}},
+	}
+	entries, indexes, err := failedRecordEntries(records)
+	if err != nil {
+		t.Fatalf("failedRecordEntries: %v", err)
+	}
+	if len(entries) != 1 || len(indexes) != 1 || indexes[0] != 1 {
+		t.Fatalf("unexpected entries %#v %v", entries, indexes)
+	}
+	entry := entries[0]
+	if entry.Name != "Far" || entry.Port != 19133 || entry.Edition != ping.EditionBedrock || entry.Overrides.Timeout != 12*time.Second ||
+		entry.Overrides.IPMode != ping.IPModeIPv4 || entry.Overrides.Retries != nil || len(entry.Tags) != 1 {
+		t.Fatalf("unexpected entry %#v", entry)
+	}
+
+	retried := batchExportRecords("batch", []batchRunResult{{Entry: entry, Result: ping.BedrockPong{GameVersion: "1.21"}}})
+	merged := mergeRetryRecords(records, indexes, retried)
+	if !merged[1].Success || merged[1].Retried != 1 || merged[1].Name != "Far" || !merged[0].Success {
+		t.Fatalf("unexpected merged records %#v", merged)
+	}
+	if summary := formatRetrySummary(merged, indexes); !strings.Contains(summary, "- Retried: 1 (1 recovered)") {
+		t.Fatalf("unexpected summary %q", summary)
+	}
+}
@@ -15,6 +15,7 @@ import (
 type App struct {
 	settings        Settings
 	linkServer      *web.LinkServer
+	recent          recentResults
 	startupWarnings []error
 }
 
@@ -100,7 +101,7 @@ func (a *App) collectConfig() (Config, error) {
 		}
 		return Config{Mode: mode, Lookup: lookup}, nil
 	}
-	if mode == ModeSettings || mode == ModeFavorites || mode == ModeBatch || mode == ModePortScan || mode == ModeUpdate || mode == ModeExit {
+	if mode == ModeSettings || mode == ModeFavorites || mode == ModeBatch || mode == ModePortScan || mode == ModeNetworkScan || mode == ModeWatch || mode == ModeMonitoring || mode == ModeHistory || mode == ModeCompare || mode == ModeUpdate || mode == ModeExit {
 		return Config{Mode: mode}, nil
 	}
 
@@ -115,14 +116,19 @@ func (a *App) collectConfig() (Config, error) {
 type Mode string
 
 const (
-	ModeDirect    Mode = "direct"
-	ModeLookup    Mode = "lookup"
-	ModeFavorites Mode = "favorites"
-	ModeBatch     Mode = "batch"
-	ModePortScan  Mode = "port_scan"
-	ModeSettings  Mode = "settings"
-	ModeUpdate    Mode = "update"
-	ModeExit      Mode = "exit"
+	ModeDirect      Mode = "direct"
+	ModeLookup      Mode = "lookup"
+	ModeFavorites   Mode = "favorites"
+	ModeBatch       Mode = "batch"
+	ModePortScan    Mode = "port_scan"
+	ModeNetworkScan Mode = "network_scan"
+	ModeWatch       Mode = "watch"
+	ModeMonitoring  Mode = "monitoring"
+	ModeHistory     Mode = "history"
+	ModeCompare     Mode = "compare"
+	ModeSettings    Mode = "settings"
+	ModeUpdate      Mode = "update"
+	ModeExit        Mode = "exit"
 )
 
 type DirectConfig struct {
@@ -138,8 +144,11 @@ type LookupConfig struct {
 	Ports      []int
 	Subdomains []string
 	Endings    []string
-	Sort       lookupSort
-	Filter     lookupFilter
+	Source     lookupSource
+	Imported   []ping.LookupTarget
+	Imports    []candidateImport
+	Sort       resultSort
+	Filter     *resultFilter
 }
 
 func (a *App) collectDirectConfig() (DirectConfig, error) {
@@ -167,19 +176,42 @@ func (a *App) collectLookupConfig() (LookupConfig, error) {
 		return LookupConfig{}, err
 	}
 
-	subdomains, err := a.askSubdomainChoice()
+	source, err := a.askLookupSource()
 	if err != nil {
 		return LookupConfig{}, err
 	}
 
+	var subdomains []string
+	if source != lookupSourceImported {
+		subdomains, err = a.askSubdomainChoice()
+		if err != nil {
+			return LookupConfig{}, err
+		}
+	}
+
 	baseHost, err := a.askBaseHost()
 	if err != nil {
 		return LookupConfig{}, err
 	}
 
-	endings, err := a.askDomainEndings()
-	if err != nil {
-		return LookupConfig{}, err
+	var imported []ping.LookupTarget
+	var imports []candidateImport
+	if source != lookupSourceGenerated {
+		imported, imports, err = a.askCandidateFiles(baseHost)
+		if err != nil {
+			return LookupConfig{}, err
+		}
+		if source == lookupSourceImported && len(imported) == 0 {
+			return LookupConfig{}, fmt.Errorf("no names under %s found in the imported files", baseHost)
+		}
+	}
+
+	var endings []string
+	if source != lookupSourceImported {
+		endings, err = a.askDomainEndings()
+		if err != nil {
+			return LookupConfig{}, err
+		}
 	}
 
 	port, ports, err := a.askLookupPorts(edition)
@@ -202,6 +234,9 @@ func (a *App) collectLookupConfig() (LookupConfig, error) {
 		Ports:      ports,
 		Subdomains: subdomains,
 		Endings:    endings,
+		Source:     source,
+		Imported:   imported,
+		Imports:    imports,
 		Sort:       sortMode,
 		Filter:     filterMode,
 	}, nil
@@ -213,7 +248,12 @@ func (a *App) askMode() (Mode, error) {
 		"Favorites: Saved server profiles",
 		"Batch check: Run a target file",
 		"Port scan: Probe common ports",
+		"Network scan: Probe CIDRs and IP ranges",
 		"IP/domain lookup: Sweep domains and subdomains",
+		"Watch: Live status of a server or favorites",
+		"Monitoring: Uptime history and targets",
+		"History: Browse saved results",
+		"Compare results: Diff two saved exports",
 		"Settings: Network, output and presets",
 		"Update check: Compare with GitHub",
 		"Exit",
@@ -229,12 +269,22 @@ func (a *App) askMode() (Mode, error) {
 	case 3:
 		return ModePortScan, nil
 	case 4:
-		return ModeLookup, nil
+		return ModeNetworkScan, nil
 	case 5:
-		return ModeSettings, nil
+		return ModeLookup, nil
 	case 6:
-		return ModeUpdate, nil
+		return ModeWatch, nil
 	case 7:
+		return ModeMonitoring, nil
+	case 8:
+		return ModeHistory, nil
+	case 9:
+		return ModeCompare, nil
+	case 10:
+		return ModeSettings, nil
+	case 11:
+		return ModeUpdate, nil
+	case 12:
 		return ModeExit, nil
 	default:
 		return ModeDirect, nil
@@ -569,6 +619,16 @@ func (a *App) execute(config Config) error {
 		return a.executeBatch()
 	case ModePortScan:
 		return a.executePortScan()
+	case ModeNetworkScan:
+		return a.executeNetworkScan()
+	case ModeWatch:
+		return a.executeWatch()
+	case ModeMonitoring:
+		return a.manageMonitoring()
+	case ModeHistory:
+		return a.browseSavedResults()
+	case ModeCompare:
+		return a.executeCompare()
 	case ModeSettings:
 		return a.manageSettings()
 	case ModeUpdate:
@@ -581,6 +641,7 @@ func (a *App) execute(config Config) error {
 }
 
 func (a *App) executeDirect(config DirectConfig) error {
+	var icon []byte
 	resultText, err := withSpinner("Query", func(frame int) string {
 		_ = frame
 		return "Querying server"
@@ -625,6 +686,10 @@ func (a *App) executeDirect(config DirectConfig) error {
 		}
 
 		record := newExportRecord("direct", config.Edition, config.Host, config.Port, result, details, link, nil)
+		displayText, exportText = a.templateResultText(newResultTemplateData("Direct query", "direct", []exportRecord{record}, nil), displayText, exportText)
+		if status, ok := result.(ping.JavaStatus); ok {
+			icon = status.IconPNG
+		}
 		if a.settings.SaveResults && a.settings.SaveJavaIcons {
 			if status, ok := result.(ping.JavaStatus); ok && len(status.IconPNG) > 0 {
 				path, err := a.saveJavaIcon(config.Host, status)
@@ -651,12 +716,21 @@ func (a *App) executeDirect(config DirectConfig) error {
 		return err
 	}
 
-	return renderTextPageAndWait("Result", resultText)
+	return a.renderResultPageAndWait("Result", resultText, icon)
 }
 
 func (a *App) executeLookup(config LookupConfig) error {
+	config, ordering := a.applyLearnedLookupOrder(config)
 	progressView := newLookupProgressView(a.settings, config)
 	startedAt := time.Now()
+	acceptFilter := lookupMatchFilter("lookup", config.Edition, config.Filter)
+	var (
+		hits        []ping.LookupMatch
+		view        ping.LookupResult
+		links       []web.LookupLinkURLs
+		metrics     lookupMetrics
+		viewOptions resultFormatOptions
+	)
 
 	resultText, err := withControlledSpinner("IP lookup", func(frame int, control *spinnerControl) string {
 		status := progressView.Render(frame)
@@ -672,10 +746,12 @@ func (a *App) executeLookup(config LookupConfig) error {
 			Edition:       config.Edition,
 			Port:          config.Port,
 			Ports:         config.Ports,
-			BaseHost:      config.BaseHost,
+			BaseHost:      config.generatedBaseHost(),
+			Targets:       config.Imported,
 			Subdomains:    config.Subdomains,
 			DomainEndings: config.Endings,
 			Concurrency:   a.settings.LookupConcurrency,
+			Adaptive:      a.settings.AdaptiveConcurrency,
 			RateLimit:     a.settings.LookupRateLimit,
 			Options: ping.ExecuteOptions{
 				Timeout:    a.settings.RequestTimeout(),
@@ -687,30 +763,22 @@ func (a *App) executeLookup(config LookupConfig) error {
 			Progress: func(progress ping.LookupProgress) {
 				progressView.Observe(progress)
 			},
-			Paused: control.IsPaused,
+			Accept: func(match ping.LookupMatch) bool {
+				hits = append(hits, match)
+				return acceptFilter == nil || acceptFilter(match)
+			},
+			Paused:       control.IsPaused,
+			Unresponsive: a.settings.ReportUnresponsive,
 		})
 		if lookupErr != nil && !errors.Is(lookupErr, context.Canceled) {
 			return "", lookupErr
 		}
-		result.Matches = applyLookupView(result.Matches, config.Sort, config.Filter)
-		var links []web.LookupLinkURLs
+		statsErr := a.recordLookupStats(hits)
+		sortLookupMatches(result.Matches, "lookup", config.Edition, config.Sort)
 		var linkErr error
-		if config.Edition == ping.EditionBedrock && len(result.Matches) > 0 {
-			entries := make([]web.LookupLink, 0, len(result.Matches))
-			for _, match := range result.Matches {
-				entries = append(entries, web.LookupLink{
-					Name: match.Host,
-					Host: match.Host,
-					Port: match.Port,
-				})
-			}
-			var err error
-			links, err = a.startBedrockLinks(entries)
-			if err != nil {
-				linkErr = err
-			}
-		}
-		metrics := lookupMetrics{
+		links, linkErr = a.startLookupMatchLinks(config.Edition, result.Matches)
+		view = result
+		metrics = lookupMetrics{
 			BaseHost:      config.BaseHost,
 			Subdomains:    countLookupSubdomains(config.Subdomains),
 			Endings:       countLookupEndings(config.Endings),
@@ -718,44 +786,34 @@ func (a *App) executeLookup(config LookupConfig) error {
 			Duration:      time.Since(startedAt),
 			AverageRate:   calculateLookupAverageRate(result.Completed, startedAt),
 			Concurrency:   resolveLookupConcurrency(a.settings.LookupConcurrency, result.Attempts),
+			Adaptive:      a.settings.AdaptiveConcurrency,
+			FinalWorkers:  result.Workers,
+			PeakWorkers:   result.PeakWorkers,
 			RateLimit:     a.settings.LookupRateLimit,
 			CompletionPct: calculateLookupCompletion(result.Completed, result.Attempts),
 			Sort:          lookupSortLabel(config.Sort),
 			Filter:        lookupFilterLabel(config.Filter),
+			Ordering:      ordering,
+			Source:        lookupSourceLabel(config.Source),
+			Imports:       config.Imports,
 			Canceled:      errors.Is(lookupErr, context.Canceled) || control.IsCancelled(),
 		}
 		displayOptions := resultFormatOptions{Verbose: a.settings.Verbose, ColorMOTD: a.settings.ColorMOTD}
 		exportOptions := resultFormatOptions{Verbose: a.settings.Verbose, ColorMOTD: false}
+		viewOptions = displayOptions
 		displayText := formatLookupResult(result, links, metrics, displayOptions)
 		exportText := formatLookupResult(result, links, metrics, exportOptions)
-		records := make([]exportRecord, 0, len(result.Matches))
-		var iconErr error
-		for i, match := range result.Matches {
-			var link *web.LookupLinkURLs
-			if i < len(links) {
-				link = &links[i]
-			}
-			record := newExportRecord("lookup", config.Edition, match.Host, match.Port, match.Result, match.Detail, link, nil)
-			if a.settings.SaveResults && a.settings.SaveJavaIcons {
-				if status, ok := match.Result.(ping.JavaStatus); ok && len(status.IconPNG) > 0 {
-					path, err := a.saveJavaIcon(match.Host, status)
-					if err != nil {
-						if iconErr == nil {
-							iconErr = err
-						}
-					} else {
-						record.JavaIconSavedTo = path
-					}
-				}
-			}
-			records = append(records, record)
-		}
+		records, iconErr := a.lookupExportRecords("lookup", config.Edition, result, links)
+		displayText, exportText = a.templateResultText(newResultTemplateData("Lookup", "lookup", records, &metrics), displayText, exportText)
 		if linkErr != nil {
 			displayText = appendWarningText(displayText, "Bedrock browser links unavailable", linkErr)
 		}
 		if iconErr != nil {
 			displayText = appendWarningText(displayText, "One or more server icons could not be saved", iconErr)
 		}
+		if statsErr != nil {
+			displayText = appendWarningText(displayText, "Lookup statistics could not be saved", statsErr)
+		}
 		if a.settings.SaveResults {
 			path, err := a.saveExport("Lookup", exportText, records)
 			if err != nil {
@@ -764,13 +822,46 @@ func (a *App) executeLookup(config LookupConfig) error {
 				displayText += fmt.Sprintf("\nSaved result: %s", path)
 			}
 		}
+		a.rememberResults("Lookup", records)
+		displayText = a.appendDashboardText(displayText, "Lookup", records)
 		return displayText, nil
 	})
 	if err != nil {
 		return err
 	}
 
-	return renderTextPageAndWait("Result", resultText)
+	for {
+		if err := renderTextPageAndWait("Result", resultText); err != nil {
+			return err
+		}
+		if len(view.Matches) == 0 {
+			return nil
+		}
+		index, err := selectOption("Lookup result", []string{
+			"Filter: Narrow these results",
+			"Done: Back to the main menu",
+		})
+		if err != nil || index != 0 {
+			return err
+		}
+		filter, err := a.askLookupFilter()
+		if err != nil {
+			return err
+		}
+		order, err := a.askLookupSort()
+		if err != nil {
+			return err
+		}
+		filtered, filteredLinks := viewLookupMatches(view, links, "lookup", config.Edition, filter, order)
+		filteredMetrics := metrics
+		if filter != nil {
+			filteredMetrics.Filter = metrics.Filter + ", then " + filter.String()
+		}
+		if len(order) > 0 {
+			filteredMetrics.Sort = order.String()
+		}
+		resultText = formatLookupResult(filtered, filteredLinks, filteredMetrics, viewOptions)
+	}
 }
 
 func (a *App) askAgain() (bool, error) {
@@ -789,13 +880,26 @@ type lookupMetrics struct {
 	Duration      time.Duration
 	AverageRate   float64
 	Concurrency   int
+	Adaptive      bool
+	FinalWorkers  int
+	PeakWorkers   int
 	RateLimit     int
 	CompletionPct float64
 	Sort          string
 	Filter        string
+	Ordering      string
+	Source        string
+	Imports       []candidateImport
 	Canceled      bool
 }
 
+func (c LookupConfig) generatedBaseHost() string {
+	if c.Source == lookupSourceImported {
+		return ""
+	}
+	return c.BaseHost
+}
+
 func countLookupSubdomains(values []string) int {
 	seen := make(map[string]struct{}, len(values))
 	for _, value := range values {
@@ -838,26 +942,44 @@ func formatLookupPortCount(value int) string {
 	return fmt.Sprintf("%d", value)
 }
 
+func formatLookupPipeline(metrics lookupMetrics) string {
+	if !metrics.Adaptive || metrics.FinalWorkers <= 0 {
+		return fmt.Sprintf("%d workers", metrics.Concurrency)
+	}
+	return fmt.Sprintf("%d workers adaptive (started %d, peak %d)", metrics.FinalWorkers, metrics.Concurrency, metrics.PeakWorkers)
+}
+
 func formatLookupResult(result ping.LookupResult, links []web.LookupLinkURLs, metrics lookupMetrics, options resultFormatOptions) string {
 	var builder strings.Builder
 	builder.WriteString("Summary\n")
 	builder.WriteString(fmt.Sprintf("- Base host: %s\n", metrics.BaseHost))
+	builder.WriteString(fmt.Sprintf("- Candidate source: %s\n", metrics.Source))
+	for _, item := range metrics.Imports {
+		builder.WriteString(fmt.Sprintf("- Imported: %s (%s, %d of %d names under base host)\n", item.Path, item.Format, item.Kept, item.Names))
+	}
 	builder.WriteString(fmt.Sprintf("- Subdomains: %d\n", metrics.Subdomains))
 	builder.WriteString(fmt.Sprintf("- Domain endings: %d\n", metrics.Endings))
 	builder.WriteString(fmt.Sprintf("- Ports: %s\n", formatLookupPortCount(metrics.Ports)))
 	builder.WriteString(fmt.Sprintf("- Checked combinations: %d/%d\n", result.Completed, result.Attempts))
 	builder.WriteString(fmt.Sprintf("- Completion: %.1f%%\n", metrics.CompletionPct))
 	builder.WriteString(fmt.Sprintf("- Matches after filter: %d\n", len(result.Matches)))
+	if result.Rejected > 0 {
+		builder.WriteString(fmt.Sprintf("- Not matching: %d\n", result.Rejected))
+	}
 	builder.WriteString(fmt.Sprintf("- Sort: %s\n", metrics.Sort))
 	builder.WriteString(fmt.Sprintf("- Filter: %s\n", metrics.Filter))
+	if metrics.Ordering != "" {
+		builder.WriteString(fmt.Sprintf("- Probe order: %s\n", metrics.Ordering))
+	}
 	builder.WriteString(fmt.Sprintf("- Elapsed: %s\n", formatLookupDuration(metrics.Duration)))
 	builder.WriteString(fmt.Sprintf("- Average throughput: %s\n", formatLookupRate(metrics.AverageRate)))
-	builder.WriteString(fmt.Sprintf("- Pipeline: %d workers\n", metrics.Concurrency))
+	builder.WriteString(fmt.Sprintf("- Pipeline: %s\n", formatLookupPipeline(metrics)))
 	builder.WriteString(fmt.Sprintf("- Rate cap: %s\n", formatLookupRateCap(metrics.RateLimit)))
 	if metrics.Canceled {
 		builder.WriteString("- Status: canceled\n")
 	}
 	builder.WriteString("\n")
+	builder.WriteString(formatFailureSection(result.Failures, lookupUnresponsiveLines(result.Unresponsive)))
 
 	if len(result.Matches) == 0 {
 		builder.WriteString("No matching servers found.")
@@ -872,6 +994,9 @@ func formatLookupResult(result ping.LookupResult, links []web.LookupLinkURLs, me
 		builder.WriteString(fmt.Sprintf("Match %d\n", i+1))
 		builder.WriteString(fmt.Sprintf("Host: %s\n", match.Host))
 		builder.WriteString(fmt.Sprintf("Port: %d\n", match.Port))
+		if match.Source != "" {
+			builder.WriteString(fmt.Sprintf("Source: %s\n", match.Source))
+		}
 		if i < len(links) {
 			builder.WriteString(fmt.Sprintf("Add link (browser): %s\n", links[i].AddURL))
 			builder.WriteString(fmt.Sprintf("Join link (browser): %s\n", links[i].ConnectURL))
@@ -897,6 +1022,53 @@ func (a *App) startBedrockLinks(entries []web.LookupLink) ([]web.LookupLinkURLs,
 	return server.Links(), nil
 }
 
+func (a *App) startLookupMatchLinks(edition ping.Edition, matches []ping.LookupMatch) ([]web.LookupLinkURLs, error) {
+	if edition != ping.EditionBedrock || len(matches) == 0 {
+		return nil, nil
+	}
+	entries := make([]web.LookupLink, 0, len(matches))
+	for _, match := range matches {
+		entries = append(entries, web.LookupLink{
+			Name: match.Host,
+			Host: match.Host,
+			Port: match.Port,
+		})
+	}
+	return a.startBedrockLinks(entries)
+}
+
+func (a *App) lookupExportRecords(mode string, edition ping.Edition, result ping.LookupResult, links []web.LookupLinkURLs) ([]exportRecord, error) {
+	records := make([]exportRecord, 0, len(result.Matches)+len(result.Unresponsive))
+	var iconErr error
+	for i, match := range result.Matches {
+		var link *web.LookupLinkURLs
+		if i < len(links) {
+			link = &links[i]
+		}
+		record := newExportRecord(mode, edition, match.Host, match.Port, match.Result, match.Detail, link, nil)
+		record.Source = match.Source
+		if a.settings.SaveResults && a.settings.SaveJavaIcons {
+			if status, ok := match.Result.(ping.JavaStatus); ok && len(status.IconPNG) > 0 {
+				path, err := a.saveJavaIcon(match.Host, status)
+				if err != nil {
+					if iconErr == nil {
+						iconErr = err
+					}
+				} else {
+					record.JavaIconSavedTo = path
+				}
+			}
+		}
+		records = append(records, record)
+	}
+	for _, failure := range result.Unresponsive {
+		record := newExportRecord(mode, edition, failure.Host, failure.Port, nil, failure.Detail, nil, failure.Err)
+		record.Source = failure.Source
+		records = append(records, record)
+	}
+	return records, iconErr
+}
+
 func appendLinkText(text string, link web.LookupLinkURLs) string {
 	return fmt.Sprintf("%s\nAdd link (browser): %s\nJoin link (browser): %s", text, link.AddURL, link.ConnectURL)
 }
@@ -0,0 +1,293 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"UWP-TCP-Con/internal/history"
+	"UWP-TCP-Con/internal/web"
+)
+
+const defaultExporterAddress = "127.0.0.1:9165"
+
+type command struct {
+	name    string
+	summary string
+	run     func(a *App, args []string) error
+}
+
+func commands() []command {
+	return []command{
+		{name: "monitor", summary: "Probe monitor targets on a schedule and record history", run: (*App).runMonitorCommand},
+		{name: "history", summary: "Show uptime, players and version changes from recorded history", run: (*App).runHistoryCommand},
+		{name: "exporter", summary: "Serve Prometheus metrics for monitor targets and on-demand probes", run: (*App).runExporterCommand},
+		{name: "batch", summary: "Check a batch file, or re-check the failures of a previous JSON export", run: (*App).runBatchCommand},
+		{name: "compare", summary: "Show what changed between two JSON result exports", run: (*App).runCompareCommand},
+		{name: "render", summary: "Render a saved result export with a result template", run: (*App).runRenderCommand},
+		{name: "serve", summary: "Serve a JSON API for queries, lookups and batch checks", run: (*App).runServeCommand},
+	}
+}
+
+func (a *App) RunCommand(args []string) error {
+	if len(args) == 0 {
+		return a.Run()
+	}
+	name := args[0]
+	if name == "help" || name == "-h" || name == "--help" {
+		printCommandUsage(os.Stdout)
+		return nil
+	}
+	for _, cmd := range commands() {
+		if cmd.name == name {
+			if err := cmd.run(a, args[1:]); !errors.Is(err, flag.ErrHelp) {
+				return err
+			}
+			return nil
+		}
+	}
+	printCommandUsage(os.Stderr)
+	return fmt.Errorf("unknown command %q", name)
+}
+
+func printCommandUsage(out io.Writer) {
+	fmt.Fprintln(out, "Usage: uwp-tcp-con [command] [flags]")
+	fmt.Fprintln(out, "")
+	fmt.Fprintln(out, "Without a command the interactive UI starts.")
+	fmt.Fprintln(out, "")
+	fmt.Fprintln(out, "Commands:")
+	for _, cmd := range commands() {
+		fmt.Fprintf(out, "  %-10s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprintln(out, "")
+	fmt.Fprintln(out, "Run `uwp-tcp-con <command> -h` for command flags.")
+}
+
+func newFlagSet(name string) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.SetOutput(os.Stderr)
+	return flags
+}
+
+func parseCommandFlags(flags *flag.FlagSet, args []string) error {
+	return flags.Parse(args)
+}
+
+func commandContext() (context.Context, context.CancelFunc) {
+	return signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+}
+
+func (a *App) runMonitorCommand(args []string) error {
+	flags := newFlagSet("monitor")
+	configPath := flags.String("config", "", "monitor config file (default: monitor.json in the config dir)")
+	alertsPath := flags.String("alerts", "", "alert rules file (default: alerts.json in the config dir)")
+	once := flags.Bool("once", false, "probe every target once and exit")
+	if err := parseCommandFlags(flags, args); err != nil {
+		return err
+	}
+	config, err := loadMonitorConfig(*configPath)
+	if err != nil {
+		return err
+	}
+	alerts, err := loadAlertEngine(*alertsPath)
+	if err != nil {
+		return err
+	}
+	store, err := openHistoryStore()
+	if err != nil {
+		return err
+	}
+	ctx, cancel := commandContext()
+	defer cancel()
+	return a.runMonitor(ctx, config, store, alerts, os.Stdout, *once)
+}
+
+func (a *App) runExporterCommand(args []string) error {
+	flags := newFlagSet("exporter")
+	listen := flags.String("listen", defaultExporterAddress, "address to serve /metrics and /probe on")
+	configPath := flags.String("config", "", "monitor config with the /metrics targets (default: monitor.json in the config dir)")
+	if err := parseCommandFlags(flags, args); err != nil {
+		return err
+	}
+	config, err := loadMonitorConfig(*configPath)
+	if err != nil {
+		return err
+	}
+	targets := make([]web.ProbeTarget, 0, len(config.Targets))
+	for _, target := range config.Targets {
+		if err := target.Validate(); err != nil {
+			return err
+		}
+		targets = append(targets, web.ProbeTarget{Name: target.Name, Edition: target.Edition, Host: target.Host, Port: target.Port})
+	}
+	exporter := web.NewExporter(targets, a.settings.ExecuteOptions())
+
+	ctx, cancel := commandContext()
+	defer cancel()
+	fmt.Printf("Serving metrics for %d target(s) on http://%s/metrics\n", len(targets), *listen)
+	return serveHTTP(ctx, *listen, exporter.Handler())
+}
+
+func (a *App) runServeCommand(args []string) error {
+	flags := newFlagSet("serve")
+	listen := flags.String("listen", defaultServeAddress, "address to serve the API on")
+	token := flags.String("token", os.Getenv("MCQUERY_API_TOKEN"), "bearer token required on every request (default: $MCQUERY_API_TOKEN)")
+	if err := parseCommandFlags(flags, args); err != nil {
+		return err
+	}
+	if *token == "" && !isLoopbackAddress(*listen) {
+		return fmt.Errorf("a -token is required when listening on a non-loopback address")
+	}
+	listener, err := net.Listen("tcp", *listen)
+	if err != nil {
+		return err
+	}
+	server := newAPIServer(a, *token, listener.Addr().(*net.TCPAddr).Port)
+
+	ctx, cancel := commandContext()
+	defer cancel()
+	fmt.Printf("Serving the MCQuery API on http://%s\n", listener.Addr())
+	return serveListener(ctx, listener, server.Handler())
+}
+
+func serveHTTP(ctx context.Context, address string, handler http.Handler) error {
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return err
+	}
+	return serveListener(ctx, listener, handler)
+}
+
+func serveListener(ctx context.Context, listener net.Listener, handler http.Handler) error {
+	server := &http.Server{
+		Handler:           handler,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	serverErr := make(chan error, 1)
+	go func() {
+		serverErr <- server.Serve(listener)
+	}()
+	select {
+	case err := <-serverErr:
+		return err
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		return server.Shutdown(shutdownCtx)
+	}
+}
+
+type historyReport struct {
+	Key         string                  `json:"key"`
+	Name        string                  `json:"name"`
+	Since       time.Time               `json:"since"`
+	Samples     int                     `json:"samples"`
+	Checks      int                     `json:"checks"`
+	UptimePct   float64                 `json:"uptime_pct"`
+	AvgLatency  int64                   `json:"avg_latency_ms"`
+	PeakPlayers int                     `json:"peak_players"`
+	LastUp      bool                    `json:"last_up"`
+	LastAt      time.Time               `json:"last_at"`
+	Version     string                  `json:"version,omitempty"`
+	Versions    []history.VersionChange `json:"version_changes,omitempty"`
+	MOTDChanges int                     `json:"motd_changes"`
+	Players     []float64               `json:"players"`
+}
+
+func (a *App) runHistoryCommand(args []string) error {
+	flags := newFlagSet("history")
+	since := flags.Duration("since", 7*24*time.Hour, "time window to report, e.g. 24h or 168h")
+	asJSON := flags.Bool("json", false, "print JSON instead of text")
+	if err := parseCommandFlags(flags, args); err != nil {
+		return err
+	}
+	store, err := openHistoryStore()
+	if err != nil {
+		return err
+	}
+	config, err := loadMonitorConfig("")
+	if err != nil {
+		return err
+	}
+	names := monitorTargetNames(config)
+
+	keys, err := store.Keys()
+	if err != nil {
+		return err
+	}
+	if filter := flags.Args(); len(filter) > 0 {
+		keys = filterHistoryKeys(keys, names, filter)
+		if len(keys) == 0 {
+			return fmt.Errorf("no history found for %v", filter)
+		}
+	}
+
+	now := time.Now()
+	from := now.Add(-*since)
+	reports := make([]historyReport, 0, len(keys))
+	for i, key := range keys {
+		samples, err := store.Read(key, from)
+		if err != nil {
+			return err
+		}
+		name := key
+		if label, ok := names[key]; ok {
+			name = label
+		}
+		if !*asJSON {
+			if i > 0 {
+				fmt.Println()
+			}
+			fmt.Println(formatHistoryReport(name, samples, now))
+			continue
+		}
+		summary := history.Summarize(samples)
+		reports = append(reports, historyReport{
+			Key:         key,
+			Name:        name,
+			Since:       from,
+			Samples:     len(samples),
+			Checks:      summary.Checks,
+			UptimePct:   summary.Uptime,
+			AvgLatency:  summary.AvgLatency.Milliseconds(),
+			PeakPlayers: summary.PeakPlayers,
+			LastUp:      summary.LastUp,
+			LastAt:      summary.LastAt,
+			Version:     summary.Version,
+			Versions:    summary.Versions,
+			MOTDChanges: summary.MOTDChanges,
+			Players:     history.PlayerSeries(samples, from, now, historySparklineWidth),
+		})
+	}
+	if !*asJSON {
+		return nil
+	}
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(reports)
+}
+
+func filterHistoryKeys(keys []string, names map[string]string, filter []string) []string {
+	wanted := make(map[string]struct{}, len(filter))
+	for _, value := range filter {
+		wanted[value] = struct{}{}
+	}
+	matched := make([]string, 0, len(keys))
+	for _, key := range keys {
+		_, byKey := wanted[key]
+		_, byName := wanted[names[key]]
+		if byKey || byName {
+			matched = append(matched, key)
+		}
+	}
+	return matched
+}
@@ -0,0 +1,407 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	compareFormatText     = "text"
+	compareFormatJSON     = "json"
+	compareFormatMarkdown = "markdown"
+)
+
+type exportDiff struct {
+	Old         exportDiffSide  `json:"old"`
+	New         exportDiffSide  `json:"new"`
+	Appeared    []diffServer    `json:"appeared"`
+	Disappeared []diffServer    `json:"disappeared"`
+	Changed     []serverChanges `json:"changed"`
+	Unchanged   int             `json:"unchanged"`
+}
+
+type exportDiffSide struct {
+	Path      string `json:"path"`
+	Title     string `json:"title,omitempty"`
+	CreatedAt string `json:"created_at,omitempty"`
+	Records   int    `json:"records"`
+}
+
+type diffServer struct {
+	Edition    string `json:"edition"`
+	Host       string `json:"host"`
+	Port       int    `json:"port"`
+	Name       string `json:"name,omitempty"`
+	Online     bool   `json:"online"`
+	Version    string `json:"version,omitempty"`
+	Players    int    `json:"players,omitempty"`
+	MaxPlayers int    `json:"max_players,omitempty"`
+}
+
+type serverChanges struct {
+	Server      diffServer    `json:"server"`
+	Changes     []fieldChange `json:"changes"`
+	PlayerDelta int           `json:"player_delta,omitempty"`
+}
+
+type fieldChange struct {
+	Field string `json:"field"`
+	Old   string `json:"old"`
+	New   string `json:"new"`
+}
+
+func isValidCompareFormat(value string) bool {
+	switch value {
+	case compareFormatText, compareFormatJSON, compareFormatMarkdown:
+		return true
+	default:
+		return false
+	}
+}
+
+func diffRecordKey(record exportRecord) string {
+	return record.Edition + "|" + strings.ToLower(record.Host) + "|" + strconv.Itoa(record.Port)
+}
+
+func newDiffServer(record exportRecord) diffServer {
+	server := diffServer{
+		Edition: record.Edition,
+		Host:    record.Host,
+		Port:    record.Port,
+		Name:    record.Name,
+		Online:  record.Success,
+	}
+	if record.Success {
+		server.Version = record.Version
+		server.Players = record.PlayersOnline
+		server.MaxPlayers = record.PlayersMax
+	}
+	return server
+}
+
+func (s diffServer) label() string {
+	label := fmt.Sprintf("%s %s:%d", s.Edition, s.Host, s.Port)
+	if s.Name != "" {
+		label += " (" + s.Name + ")"
+	}
+	return label
+}
+
+func (s diffServer) status() string {
+	if !s.Online {
+		return "offline"
+	}
+	status := fmt.Sprintf("players %d/%d", s.Players, s.MaxPlayers)
+	if s.Version != "" {
+		status = s.Version + " " + status
+	}
+	return status
+}
+
+func diffExports(oldPayload, newPayload exportPayload) exportDiff {
+	diff := exportDiff{
+		Old: exportDiffSide{Title: oldPayload.Title, CreatedAt: oldPayload.CreatedAt, Records: len(oldPayload.Records)},
+		New: exportDiffSide{Title: newPayload.Title, CreatedAt: newPayload.CreatedAt, Records: len(newPayload.Records)},
+	}
+	oldRecords := make(map[string]exportRecord, len(oldPayload.Records))
+	for _, record := range oldPayload.Records {
+		oldRecords[diffRecordKey(record)] = record
+	}
+	seen := make(map[string]bool, len(newPayload.Records))
+	for _, record := range newPayload.Records {
+		key := diffRecordKey(record)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		previous, ok := oldRecords[key]
+		if !ok {
+			diff.Appeared = append(diff.Appeared, newDiffServer(record))
+			continue
+		}
+		changes := diffRecordFields(previous, record)
+		if len(changes) == 0 {
+			diff.Unchanged++
+			continue
+		}
+		change := serverChanges{Server: newDiffServer(record), Changes: changes}
+		if previous.Success && record.Success {
+			change.PlayerDelta = record.PlayersOnline - previous.PlayersOnline
+		}
+		diff.Changed = append(diff.Changed, change)
+	}
+	for _, record := range oldPayload.Records {
+		key := diffRecordKey(record)
+		if !seen[key] {
+			seen[key] = true
+			diff.Disappeared = append(diff.Disappeared, newDiffServer(record))
+		}
+	}
+	return diff
+}
+
+func diffRecordFields(oldRecord, newRecord exportRecord) []fieldChange {
+	var changes []fieldChange
+	add := func(field, oldValue, newValue string) {
+		if oldValue != newValue {
+			changes = append(changes, fieldChange{Field: field, Old: oldValue, New: newValue})
+		}
+	}
+	add("status", onlineText(oldRecord.Success), onlineText(newRecord.Success))
+	if oldRecord.Success && newRecord.Success {
+		add("version", oldRecord.Version, newRecord.Version)
+		add("protocol", oldRecord.Protocol, newRecord.Protocol)
+		add("motd", recordCleanMOTD(oldRecord), recordCleanMOTD(newRecord))
+		add("players", fmt.Sprintf("%d/%d", oldRecord.PlayersOnline, oldRecord.PlayersMax), fmt.Sprintf("%d/%d", newRecord.PlayersOnline, newRecord.PlayersMax))
+	}
+	if oldRecord.SelectedIP != "" && newRecord.SelectedIP != "" {
+		add("ip", oldRecord.SelectedIP, newRecord.SelectedIP)
+		add("resolved_ips", sortedList(oldRecord.ResolvedIPs), sortedList(newRecord.ResolvedIPs))
+		add("srv", srvText(oldRecord), srvText(newRecord))
+	}
+	return changes
+}
+
+func onlineText(online bool) string {
+	if online {
+		return "online"
+	}
+	return "offline"
+}
+
+func recordCleanMOTD(record exportRecord) string {
+	if record.CleanMOTD != "" {
+		return record.CleanMOTD
+	}
+	return record.MOTD
+}
+
+func sortedList(values []string) string {
+	sorted := append([]string(nil), values...)
+	sort.Strings(sorted)
+	return strings.Join(sorted, ", ")
+}
+
+func srvText(record exportRecord) string {
+	if !record.SRVUsed {
+		return "none"
+	}
+	return fmt.Sprintf("%s:%d", record.SRVHost, record.SRVPort)
+}
+
+func compareExportFiles(oldPath, newPath string) (exportDiff, error) {
+	oldPayload, err := loadExportPayload(oldPath)
+	if err != nil {
+		return exportDiff{}, err
+	}
+	newPayload, err := loadExportPayload(newPath)
+	if err != nil {
+		return exportDiff{}, err
+	}
+	diff := diffExports(oldPayload, newPayload)
+	diff.Old.Path = oldPath
+	diff.New.Path = newPath
+	return diff, nil
+}
+
+func formatExportDiff(diff exportDiff, format string) (string, error) {
+	switch format {
+	case compareFormatJSON:
+		data, err := json.MarshalIndent(diff, "", "  ")
+		if err != nil {
+			return "", err
+		}
+		return string(data), nil
+	case compareFormatMarkdown:
+		return formatExportDiffMarkdown(diff), nil
+	default:
+		return formatExportDiffText(diff), nil
+	}
+}
+
+func (s exportDiffSide) label() string {
+	label := filepath.Base(s.Path)
+	if s.Title != "" {
+		label = fmt.Sprintf("%s (%s)", label, s.Title)
+	}
+	if s.CreatedAt != "" {
+		label += ", " + s.CreatedAt
+	}
+	return fmt.Sprintf("%s, %d records", label, s.Records)
+}
+
+func formatExportDiffText(diff exportDiff) string {
+	var builder strings.Builder
+	builder.WriteString("Summary\n")
+	builder.WriteString(fmt.Sprintf("- Old: %s\n", diff.Old.label()))
+	builder.WriteString(fmt.Sprintf("- New: %s\n", diff.New.label()))
+	builder.WriteString(fmt.Sprintf("- Appeared: %d\n", len(diff.Appeared)))
+	builder.WriteString(fmt.Sprintf("- Disappeared: %d\n", len(diff.Disappeared)))
+	builder.WriteString(fmt.Sprintf("- Changed: %d\n", len(diff.Changed)))
+	builder.WriteString(fmt.Sprintf("- Unchanged: %d\n", diff.Unchanged))
+	if len(diff.Appeared) > 0 {
+		builder.WriteString("\nAppeared\n")
+		for _, server := range diff.Appeared {
+			builder.WriteString(fmt.Sprintf("[NEW] %s - %s\n", server.label(), server.status()))
+		}
+	}
+	if len(diff.Disappeared) > 0 {
+		builder.WriteString("\nDisappeared\n")
+		for _, server := range diff.Disappeared {
+			builder.WriteString(fmt.Sprintf("[GONE] %s - was %s\n", server.label(), server.status()))
+		}
+	}
+	if len(diff.Changed) > 0 {
+		builder.WriteString("\nChanged\n")
+		for _, change := range diff.Changed {
+			builder.WriteString(fmt.Sprintf("[CHG] %s\n", change.Server.label()))
+			for _, field := range change.Changes {
+				line := fmt.Sprintf("  %s: %s -> %s", field.Field, orNone(field.Old), orNone(field.New))
+				if field.Field == "players" && change.PlayerDelta != 0 {
+					line += fmt.Sprintf(" (%+d)", change.PlayerDelta)
+				}
+				builder.WriteString(line + "\n")
+			}
+		}
+	}
+	if len(diff.Appeared)+len(diff.Disappeared)+len(diff.Changed) == 0 {
+		builder.WriteString("\nNo changes between the two exports.\n")
+	}
+	return strings.TrimRight(builder.String(), "\n")
+}
+
+func formatExportDiffMarkdown(diff exportDiff) string {
+	var builder strings.Builder
+	builder.WriteString("## Result comparison\n\n")
+	builder.WriteString(fmt.Sprintf("- **Old:** %s\n", markdownText(diff.Old.label())))
+	builder.WriteString(fmt.Sprintf("- **New:** %s\n\n", markdownText(diff.New.label())))
+	builder.WriteString("| Appeared | Disappeared | Changed | Unchanged |\n| ---: | ---: | ---: | ---: |\n")
+	builder.WriteString(fmt.Sprintf("| %d | %d | %d | %d |\n", len(diff.Appeared), len(diff.Disappeared), len(diff.Changed), diff.Unchanged))
+	serverTable := func(title string, servers []diffServer) {
+		if len(servers) == 0 {
+			return
+		}
+		builder.WriteString(fmt.Sprintf("\n### %s\n\n| Server | Edition | Status |\n| --- | --- | --- |\n", title))
+		for _, server := range servers {
+			builder.WriteString(fmt.Sprintf("| %s | %s | %s |\n", markdownCell(markdownServer(server)), server.Edition, markdownCell(server.status())))
+		}
+	}
+	serverTable("Appeared", diff.Appeared)
+	serverTable("Disappeared", diff.Disappeared)
+	if len(diff.Changed) > 0 {
+		builder.WriteString("\n### Changed\n\n| Server | Field | Old | New |\n| --- | --- | --- | --- |\n")
+		for _, change := range diff.Changed {
+			for i, field := range change.Changes {
+				server := ""
+				if i == 0 {
+					server = markdownServer(change.Server)
+				}
+				newValue := orNone(field.New)
+				if field.Field == "players" && change.PlayerDelta != 0 {
+					newValue += fmt.Sprintf(" (%+d)", change.PlayerDelta)
+				}
+				builder.WriteString(fmt.Sprintf("| %s | %s | %s | %s |\n", markdownCell(server), field.Field, markdownCell(orNone(field.Old)), markdownCell(newValue)))
+			}
+		}
+	}
+	return strings.TrimRight(builder.String(), "\n")
+}
+
+func markdownServer(server diffServer) string {
+	label := fmt.Sprintf("`%s:%d`", server.Host, server.Port)
+	if server.Name != "" {
+		label = server.Name + " " + label
+	}
+	return label
+}
+
+func markdownCell(value string) string {
+	value = strings.ReplaceAll(value, "|", `\|`)
+	return strings.ReplaceAll(strings.ReplaceAll(value, "\r", ""), "\n", "<br>")
+}
+
+func markdownText(value string) string {
+	return strings.NewReplacer("*", `\*`, "_", `\_`, "`", "\\`").Replace(value)
+}
+
+func orNone(value string) string {
+	if value == "" {
+		return "(none)"
+	}
+	return value
+}
+
+func (a *App) runCompareCommand(args []string) error {
+	flags := newFlagSet("compare")
+	format := flags.String("format", compareFormatText, "output format: text, json or markdown")
+	if err := parseCommandFlags(flags, args); err != nil {
+		return err
+	}
+	if !isValidCompareFormat(*format) {
+		return fmt.Errorf("format must be text, json or markdown")
+	}
+	if flags.NArg() != 2 {
+		return fmt.Errorf("usage: compare [-format text|json|markdown] <old.json> <new.json>")
+	}
+	diff, err := compareExportFiles(flags.Arg(0), flags.Arg(1))
+	if err != nil {
+		return err
+	}
+	text, err := formatExportDiff(diff, *format)
+	if err != nil {
+		return err
+	}
+	fmt.Println(text)
+	return nil
+}
+
+func (a *App) executeCompare() error {
+	oldPath, err := a.selectExportFile("Older export")
+	if err != nil || oldPath == "" {
+		return err
+	}
+	newPath, err := a.selectExportFile("Newer export")
+	if err != nil || newPath == "" {
+		return err
+	}
+	return a.showComparison(oldPath, newPath)
+}
+
+func (a *App) showComparison(oldPath, newPath string) error {
+	diff, err := compareExportFiles(oldPath, newPath)
+	if err != nil {
+		return err
+	}
+	if err := renderTextPageAndWait("Compare results", formatExportDiffText(diff)); err != nil {
+		return err
+	}
+	index, err := selectOption("Compare results", []string{
+		"Save Markdown: For tickets and chat",
+		"Save JSON: Machine-readable diff",
+		"Done: Back to the main menu",
+	})
+	if err != nil || index == 2 {
+		return err
+	}
+	format, ext := compareFormatMarkdown, "md"
+	if index == 1 {
+		format, ext = compareFormatJSON, "json"
+	}
+	text, err := formatExportDiff(diff, format)
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(a.resultsDir(), fmt.Sprintf("compare-%s.%s", timeStamp(), ext))
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, []byte(text+"\n"), 0o644); err != nil {
+		return err
+	}
+	return renderTextPageAndWait("Compare results", fmt.Sprintf("Saved comparison: %s", path))
+}
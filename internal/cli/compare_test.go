@@ -0,0 +1,99 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"io"
+	"strings"
+	"testing"
+)
+
+func compareFixture() (exportPayload, exportPayload) {
+	oldPayload := exportPayload{Title: "Batch check", CreatedAt: "2026-10-01T10:00:00Z", Records: []exportRecord{
+		{Edition: "java", Host: "hub.example.com", Port: 25565, Success: true, Version: "1.20.4", Protocol: "765", CleanMOTD: "Hub", PlayersOnline: 3, PlayersMax: 20,
+			SelectedIP: "192.0.2.1", ResolvedIPs: []string{"192.0.2.1"}, SRVUsed: true, SRVHost: "mc.example.com", SRVPort: 25565},
+		{Edition: "java", Host: "same.example.com", Port: 25565, Success: true, Version: "1.21", PlayersOnline: 1, PlayersMax: 10},
+		{Edition: "bedrock", Host: "gone.example.com", Port: 19132, Success: true, Version: "1.21.40", PlayersOnline: 2, PlayersMax: 30},
+		{Edition: "java", Host: "down.example.com", Port: 25565, Success: true, Version: "1.8", SelectedIP: "192.0.2.9"},
+	}}
+	newPayload := exportPayload{Title: "Batch check", CreatedAt: "2026-10-08T10:00:00Z", Records: []exportRecord{
+		{Edition: "java", Host: "HUB.example.com", Port: 25565, Success: true, Version: "1.21.1", Protocol: "767", CleanMOTD: "Hub | Season 2", PlayersOnline: 7, PlayersMax: 20,
+			SelectedIP: "192.0.2.2", ResolvedIPs: []string{"192.0.2.2"}, SRVUsed: true, SRVHost: "mc.example.com", SRVPort: 25565},
+		{Edition: "java", Host: "same.example.com", Port: 25565, Success: true, Version: "1.21", PlayersOnline: 1, PlayersMax: 10},
+		{Edition: "java", Host: "down.example.com", Port: 25565, Success: false, FailureClass: "nxdomain"},
+		{Edition: "bedrock", Host: "new.example.com", Port: 19133, Name: "New", Success: true, Version: "1.21.50", PlayersOnline: 0, PlayersMax: 10},
+	}}
+	return oldPayload, newPayload
+}
+
+func TestDiffExports(t *testing.T) {
+	diff := diffExports(compareFixture())
+	if diff.Unchanged != 1 || len(diff.Appeared) != 1 || len(diff.Disappeared) != 1 || len(diff.Changed) != 2 {
+		t.Fatalf("unexpected diff %#v", diff)
+	}
+	if diff.Appeared[0].Host != "new.example.com" || diff.Disappeared[0].Host != "gone.example.com" {
+		t.Fatalf("unexpected appeared/disappeared %#v %#v", diff.Appeared, diff.Disappeared)
+	}
+	hub := diff.Changed[0]
+	var fields []string
+	for _, change := range hub.Changes {
+		fields = append(fields, change.Field)
+	}
+	if strings.Join(fields, ",") != "version,protocol,motd,players,ip,resolved_ips" || hub.PlayerDelta != 4 {
+		t.Fatalf("unexpected hub changes %#v", hub)
+	}
+	down := diff.Changed[1]
+	if len(down.Changes) != 1 || down.Changes[0] != (fieldChange{Field: "status", Old: "online", New: "offline"}) {
+		t.Fatalf("unexpected down changes %#v", down)
+	}
+}
+
+func TestFormatExportDiff(t *testing.T) {
+	diff := diffExports(compareFixture())
+	text, _ := formatExportDiff(diff, compareFormatText)
+	for _, want := range []string{
+		"- Appeared: 1",
+		"[NEW] bedrock new.example.com:19133 (New) - 1.21.50 players 0/10",
+		"[GONE] bedrock gone.example.com:19132 - was 1.21.40 players 2/30",
+		"  players: 3/20 -> 7/20 (+4)",
+		"  status: online -> offline",
+	} {
+		if !strings.Contains(text, want) {
+			t.Fatalf("expected %q in %q", want, text)
+		}
+	}
+
+	markdown, _ := formatExportDiff(diff, compareFormatMarkdown)
+	for _, want := range []string{
+		"| 1 | 1 | 2 | 1 |",
+		"| `HUB.example.com:25565` | version | 1.20.4 | 1.21.1 |",
+		`|  | motd | Hub | Hub \| Season 2 |`,
+		"| New `new.example.com:19133` | bedrock | 1.21.50 players 0/10 |",
+	} {
+		if !strings.Contains(markdown, want) {
+			t.Fatalf("expected %q in %q", want, markdown)
+		}
+	}
+
+	data, err := formatExportDiff(diff, compareFormatJSON)
+	if err != nil {
+		t.Fatalf("json: %v", err)
+	}
+	var decoded exportDiff
+	if err := json.Unmarshal([]byte(data), &decoded); err != nil || decoded.Changed[0].PlayerDelta != 4 {
+		t.Fatalf("unexpected JSON diff %s (%v)", data, err)
+	}
+}
+
+func TestRunCommandHelpStopsCommand(t *testing.T) {
+	flags := newFlagSet("compare")
+	flags.SetOutput(io.Discard)
+	if err := parseCommandFlags(flags, []string{"-h"}); !errors.Is(err, flag.ErrHelp) {
+		t.Fatalf("expected flag.ErrHelp, got %v", err)
+	}
+	app := &App{settings: defaultSettings()}
+	if err := app.RunCommand([]string{"compare", "-h"}); err != nil {
+		t.Fatalf("expected help to exit cleanly, got %v", err)
+	}
+}
@@ -0,0 +1,76 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"UWP-TCP-Con/internal/ping"
+	"UWP-TCP-Con/internal/web"
+)
+
+func (a *App) publishDashboard(title string, records []exportRecord) (string, error) {
+	if !a.linkServer.Active() {
+		server, err := web.StartLookupLinkServer(nil, 15*time.Minute)
+		if err != nil {
+			return "", err
+		}
+		a.linkServer = server
+	}
+	a.linkServer.SetResultsPath(a.resultsDir())
+	a.linkServer.SetDashboard(dashboardFromRecords(title, records, time.Now()))
+	return a.linkServer.URL, nil
+}
+
+func (a *App) appendDashboardText(text, title string, records []exportRecord) string {
+	if !a.settings.WebDashboard {
+		return text
+	}
+	url, err := a.publishDashboard(title, records)
+	if err != nil {
+		return appendWarningText(text, "Web dashboard unavailable", err)
+	}
+	return text + fmt.Sprintf("\nDashboard: %s", url)
+}
+
+func dashboardFromRecords(title string, records []exportRecord, now time.Time) web.Dashboard {
+	rows := make([]web.DashboardRow, 0, len(records))
+	for _, record := range records {
+		row := web.DashboardRow{
+			Name:          record.Name,
+			Edition:       record.Edition,
+			Host:          record.Host,
+			Port:          record.Port,
+			Online:        record.Success,
+			Players:       record.PlayersOnline,
+			MaxPlayers:    record.PlayersMax,
+			Version:       record.Version,
+			MOTD:          record.MOTD,
+			LatencyMillis: record.LatencyMillis,
+			Source:        record.Source,
+			IconPNG:       record.IconPNG,
+		}
+		if !record.Success {
+			row.Error = ping.FailureClass(record.FailureClass).Label()
+		}
+		rows = append(rows, row)
+	}
+	return web.Dashboard{Title: title, GeneratedAt: now, Rows: rows}
+}
+
+func (a *App) resultsDir() string {
+	trimmed := strings.TrimSpace(a.settings.ResultsPath)
+	if trimmed == "" {
+		trimmed = defaultResultsPath()
+	}
+	clean := filepath.Clean(trimmed)
+	if info, err := os.Stat(clean); err == nil && info.IsDir() {
+		return clean
+	}
+	if filepath.Ext(clean) == "" {
+		return clean
+	}
+	return filepath.Dir(clean)
+}
@@ -7,6 +7,8 @@ import (
 	"net"
 	"os"
 	"strings"
+
+	"UWP-TCP-Con/internal/ping"
 )
 
 func (a *App) recoverPanic(errp *error) {
@@ -76,6 +78,13 @@ func friendlyErrorMessage(err error) string {
 		return "Permission denied"
 	}
 
+	var probeErr *ping.ProbeError
+	if errors.As(err, &probeErr) {
+		if message := failureClassMessage(probeErr.Class); message != "" {
+			return message
+		}
+	}
+
 	var dnsErr *net.DNSError
 	if errors.As(err, &dnsErr) {
 		if dnsErr.IsNotFound {
@@ -102,6 +111,31 @@ func friendlyErrorMessage(err error) string {
 	return text
 }
 
+func failureClassMessage(class ping.FailureClass) string {
+	switch class {
+	case ping.FailureNXDomain:
+		return "Host could not be resolved"
+	case ping.FailureDNSTimeout:
+		return "DNS lookup timed out"
+	case ping.FailureDNS:
+		return "DNS lookup failed"
+	case ping.FailureConnRefused:
+		return "Connection refused"
+	case ping.FailureConnectTimeout:
+		return "Connection timed out"
+	case ping.FailureReadTimeout:
+		return "Server did not answer in time"
+	case ping.FailureProtocol:
+		return "Server sent an invalid response"
+	case ping.FailureUnexpectedPacket:
+		return "Server sent an unexpected packet"
+	case ping.FailureCanceled:
+		return "Operation canceled"
+	default:
+		return ""
+	}
+}
+
 func errorDetail(err error) string {
 	if err == nil {
 		return ""
@@ -135,6 +169,16 @@ func actionErrorTitle(mode Mode) string {
 		return "Batch check failed"
 	case ModePortScan:
 		return "Port scan failed"
+	case ModeNetworkScan:
+		return "Network scan failed"
+	case ModeWatch:
+		return "Watch failed"
+	case ModeMonitoring:
+		return "Monitoring failed"
+	case ModeHistory:
+		return "History failed"
+	case ModeCompare:
+		return "Compare failed"
 	case ModeSettings:
 		return "Settings failed"
 	case ModeUpdate:
@@ -1,9 +1,11 @@
 package cli
 
 import (
+	"bytes"
 	"encoding/csv"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strconv"
@@ -15,9 +17,14 @@ import (
 )
 
 const (
-	exportFormatText = "text"
-	exportFormatJSON = "json"
-	exportFormatCSV  = "csv"
+	exportFormatText       = "text"
+	exportFormatJSON       = "json"
+	exportFormatCSV        = "csv"
+	exportFormatMarkdown   = "markdown"
+	exportFormatHTML       = "html"
+	exportFormatNDJSON     = "ndjson"
+	exportFormatPrometheus = "prometheus"
+	exportFormatInflux     = "influx"
 )
 
 type exportPayload struct {
@@ -27,32 +34,43 @@ type exportPayload struct {
 }
 
 type exportRecord struct {
-	Mode            string   `json:"mode"`
-	Edition         string   `json:"edition"`
-	Host            string   `json:"host"`
-	Port            int      `json:"port"`
-	Success         bool     `json:"success"`
-	Error           string   `json:"error,omitempty"`
-	MOTD            string   `json:"motd,omitempty"`
-	CleanMOTD       string   `json:"clean_motd,omitempty"`
-	Version         string   `json:"version,omitempty"`
-	Protocol        string   `json:"protocol,omitempty"`
-	PlayersOnline   int      `json:"players_online,omitempty"`
-	PlayersMax      int      `json:"players_max,omitempty"`
-	LatencyMillis   int64    `json:"latency_ms,omitempty"`
-	SelectedIP      string   `json:"selected_ip,omitempty"`
-	ResolvedIPs     []string `json:"resolved_ips,omitempty"`
-	SRVUsed         bool     `json:"srv_used,omitempty"`
-	SRVHost         string   `json:"srv_host,omitempty"`
-	SRVPort         int      `json:"srv_port,omitempty"`
-	AddURL          string   `json:"add_url,omitempty"`
-	ConnectURL      string   `json:"connect_url,omitempty"`
-	JavaIconSavedTo string   `json:"java_icon_saved_to,omitempty"`
+	Mode            string              `json:"mode"`
+	Name            string              `json:"name,omitempty"`
+	Edition         string              `json:"edition"`
+	Host            string              `json:"host"`
+	Port            int                 `json:"port"`
+	Source          string              `json:"source,omitempty"`
+	Success         bool                `json:"success"`
+	Error           string              `json:"error,omitempty"`
+	FailureClass    string              `json:"failure_class,omitempty"`
+	MOTD            string              `json:"motd,omitempty"`
+	CleanMOTD       string              `json:"clean_motd,omitempty"`
+	Version         string              `json:"version,omitempty"`
+	Protocol        string              `json:"protocol,omitempty"`
+	PlayersOnline   int                 `json:"players_online,omitempty"`
+	PlayersMax      int                 `json:"players_max,omitempty"`
+	LatencyMillis   int64               `json:"latency_ms,omitempty"`
+	SelectedIP      string              `json:"selected_ip,omitempty"`
+	ReverseDNS      []string            `json:"reverse_dns,omitempty"`
+	ResolvedIPs     []string            `json:"resolved_ips,omitempty"`
+	SRVUsed         bool                `json:"srv_used,omitempty"`
+	SRVHost         string              `json:"srv_host,omitempty"`
+	SRVPort         int                 `json:"srv_port,omitempty"`
+	AddURL          string              `json:"add_url,omitempty"`
+	ConnectURL      string              `json:"connect_url,omitempty"`
+	JavaIconSavedTo string              `json:"java_icon_saved_to,omitempty"`
+	Tags            []string            `json:"tags,omitempty"`
+	Notes           string              `json:"notes,omitempty"`
+	Probe           *exportProbe        `json:"probe,omitempty"`
+	Retried         int                 `json:"retried,omitempty"`
+	IconPNG         []byte              `json:"-"`
+	Details         ping.ExecuteDetails `json:"-"`
 }
 
 func isValidExportFormat(value string) bool {
 	switch strings.ToLower(strings.TrimSpace(value)) {
-	case exportFormatText, exportFormatJSON, exportFormatCSV:
+	case exportFormatText, exportFormatJSON, exportFormatCSV, exportFormatMarkdown, exportFormatHTML, exportFormatNDJSON,
+		exportFormatPrometheus, exportFormatInflux:
 		return true
 	default:
 		return false
@@ -73,6 +91,16 @@ func exportExtension(format string) string {
 		return "json"
 	case exportFormatCSV:
 		return "csv"
+	case exportFormatMarkdown:
+		return "md"
+	case exportFormatHTML:
+		return "html"
+	case exportFormatNDJSON:
+		return "ndjson"
+	case exportFormatPrometheus:
+		return "prom"
+	case exportFormatInflux:
+		return "lp"
 	default:
 		return "txt"
 	}
@@ -80,59 +108,88 @@ func exportExtension(format string) string {
 
 func (a *App) saveExport(title, textContent string, records []exportRecord) (string, error) {
 	format := normalizeExportFormat(a.settings.ExportFormat)
-	path, err := a.exportPath(exportExtension(format))
+	name := "result-" + timeStamp()
+	if format == exportFormatPrometheus {
+		name = "mcquery"
+		if mode := recordsMode(records); mode != "" {
+			name = mode
+		}
+	}
+	path, err := a.exportPathNamed(name, exportExtension(format))
 	if err != nil {
 		return "", err
 	}
-	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+	if err := writeExport(path, format, title, textContent, records); err != nil {
 		return "", err
 	}
+	return path, nil
+}
 
+func writeExport(path, format, title, textContent string, records []exportRecord) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	createdAt := time.Now()
+	var buffer bytes.Buffer
+	var err error
 	switch format {
 	case exportFormatJSON:
 		payload := exportPayload{
 			Title:     title,
-			CreatedAt: time.Now().Format(time.RFC3339),
+			CreatedAt: createdAt.Format(time.RFC3339),
 			Records:   records,
 		}
-		data, err := json.MarshalIndent(payload, "", "  ")
-		if err != nil {
-			return "", err
-		}
-		if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
-			return "", err
-		}
+		var data []byte
+		data, err = json.MarshalIndent(payload, "", "  ")
+		buffer.Write(append(data, '\n'))
 	case exportFormatCSV:
-		file, err := os.Create(path)
-		if err != nil {
-			return "", err
-		}
-		writer := csv.NewWriter(file)
-		if err := writeCSVExport(writer, records); err != nil {
-			_ = file.Close()
-			return "", err
-		}
-		writer.Flush()
-		if err := writer.Error(); err != nil {
-			_ = file.Close()
-			return "", err
-		}
-		if err := file.Close(); err != nil {
-			return "", err
+		writer := csv.NewWriter(&buffer)
+		if err = writeCSVExport(writer, records); err == nil {
+			writer.Flush()
+			err = writer.Error()
 		}
+	case exportFormatMarkdown:
+		err = writeMarkdownExport(&buffer, title, createdAt, records)
+	case exportFormatHTML:
+		err = web.WriteReport(&buffer, dashboardFromRecords(title, records, createdAt))
+	case exportFormatNDJSON:
+		err = writeNDJSONExport(&buffer, title, createdAt, records)
+	case exportFormatPrometheus:
+		err = writePrometheusExport(&buffer, createdAt, records)
+	case exportFormatInflux:
+		err = writeInfluxExport(&buffer, createdAt, records)
 	default:
-		var builder strings.Builder
-		builder.WriteString(fmt.Sprintf("%s\n", title))
-		builder.WriteString(fmt.Sprintf("Saved at: %s\n", time.Now().Format(time.RFC3339)))
-		builder.WriteString("\n")
-		builder.WriteString(textContent)
-		builder.WriteString("\n")
-		if err := os.WriteFile(path, []byte(builder.String()), 0o644); err != nil {
-			return "", err
-		}
+		buffer.WriteString(fmt.Sprintf("%s\n", title))
+		buffer.WriteString(fmt.Sprintf("Saved at: %s\n", createdAt.Format(time.RFC3339)))
+		buffer.WriteString("\n")
+		buffer.WriteString(textContent)
+		buffer.WriteString("\n")
+	}
+	if err != nil {
+		return err
 	}
+	return writeFileAtomic(path, buffer.Bytes())
+}
 
-	return path, nil
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(0o644); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
 }
 
 func writeCSVExport(writer *csv.Writer, records []exportRecord) error {
@@ -141,8 +198,10 @@ func writeCSVExport(writer *csv.Writer, records []exportRecord) error {
 		"edition",
 		"host",
 		"port",
+		"source",
 		"success",
 		"error",
+		"failure_class",
 		"motd",
 		"clean_motd",
 		"version",
@@ -151,6 +210,7 @@ func writeCSVExport(writer *csv.Writer, records []exportRecord) error {
 		"players_max",
 		"latency_ms",
 		"selected_ip",
+		"reverse_dns",
 		"resolved_ips",
 		"srv_used",
 		"srv_host",
@@ -158,6 +218,15 @@ func writeCSVExport(writer *csv.Writer, records []exportRecord) error {
 		"add_url",
 		"connect_url",
 		"java_icon_saved_to",
+		"name",
+		"tags",
+		"notes",
+		"timeout_ms",
+		"retries",
+		"srv",
+		"ip_mode",
+		"handshake_host",
+		"probe",
 	}
 	if err := writer.Write(header); err != nil {
 		return err
@@ -168,8 +237,10 @@ func writeCSVExport(writer *csv.Writer, records []exportRecord) error {
 			record.Edition,
 			record.Host,
 			strconv.Itoa(record.Port),
+			record.Source,
 			strconv.FormatBool(record.Success),
 			record.Error,
+			record.FailureClass,
 			record.MOTD,
 			record.CleanMOTD,
 			record.Version,
@@ -178,6 +249,7 @@ func writeCSVExport(writer *csv.Writer, records []exportRecord) error {
 			intString(record.PlayersMax),
 			int64String(record.LatencyMillis),
 			record.SelectedIP,
+			strings.Join(record.ReverseDNS, ";"),
 			strings.Join(record.ResolvedIPs, ";"),
 			strconv.FormatBool(record.SRVUsed),
 			record.SRVHost,
@@ -185,7 +257,11 @@ func writeCSVExport(writer *csv.Writer, records []exportRecord) error {
 			record.AddURL,
 			record.ConnectURL,
 			record.JavaIconSavedTo,
+			record.Name,
+			strings.Join(record.Tags, ";"),
+			record.Notes,
 		}
+		row = append(row, probeCSVColumns(record.Probe)...)
 		if err := writer.Write(row); err != nil {
 			return err
 		}
@@ -193,6 +269,109 @@ func writeCSVExport(writer *csv.Writer, records []exportRecord) error {
 	return nil
 }
 
+func readCSVExport(r io.Reader) ([]exportRecord, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("read CSV header: %w", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.TrimSpace(name)] = i
+	}
+	if _, ok := columns["host"]; !ok {
+		return nil, fmt.Errorf("CSV export has no host column")
+	}
+	var records []exportRecord
+	for {
+		row, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		get := func(name string) string {
+			index, ok := columns[name]
+			if !ok || index >= len(row) {
+				return ""
+			}
+			return row[index]
+		}
+		number := func(name string) int {
+			value, _ := strconv.Atoi(get(name))
+			return value
+		}
+		list := func(name string) []string {
+			if get(name) == "" {
+				return nil
+			}
+			return strings.Split(get(name), ";")
+		}
+		success, _ := strconv.ParseBool(get("success"))
+		srvUsed, _ := strconv.ParseBool(get("srv_used"))
+		latency, _ := strconv.ParseInt(get("latency_ms"), 10, 64)
+		record := exportRecord{
+			Mode:            get("mode"),
+			Name:            get("name"),
+			Edition:         get("edition"),
+			Host:            get("host"),
+			Port:            number("port"),
+			Source:          get("source"),
+			Success:         success,
+			Error:           get("error"),
+			FailureClass:    get("failure_class"),
+			MOTD:            get("motd"),
+			CleanMOTD:       get("clean_motd"),
+			Version:         get("version"),
+			Protocol:        get("protocol"),
+			PlayersOnline:   number("players_online"),
+			PlayersMax:      number("players_max"),
+			LatencyMillis:   latency,
+			SelectedIP:      get("selected_ip"),
+			ReverseDNS:      list("reverse_dns"),
+			ResolvedIPs:     list("resolved_ips"),
+			SRVUsed:         srvUsed,
+			SRVHost:         get("srv_host"),
+			SRVPort:         number("srv_port"),
+			AddURL:          get("add_url"),
+			ConnectURL:      get("connect_url"),
+			JavaIconSavedTo: get("java_icon_saved_to"),
+			Tags:            list("tags"),
+			Notes:           get("notes"),
+		}
+		if get("probe") != "" {
+			srv, _ := strconv.ParseBool(get("srv"))
+			timeout, _ := strconv.ParseInt(get("timeout_ms"), 10, 64)
+			record.Probe = &exportProbe{
+				TimeoutMillis: timeout,
+				Retries:       number("retries"),
+				SRV:           srv,
+				IPMode:        get("ip_mode"),
+				HandshakeHost: get("handshake_host"),
+				Kind:          get("probe"),
+			}
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+func probeCSVColumns(probe *exportProbe) []string {
+	if probe == nil {
+		return []string{"", "", "", "", "", ""}
+	}
+	return []string{
+		strconv.FormatInt(probe.TimeoutMillis, 10),
+		strconv.Itoa(probe.Retries),
+		strconv.FormatBool(probe.SRV),
+		probe.IPMode,
+		probe.HandshakeHost,
+		probe.Kind,
+	}
+}
+
 func intString(value int) string {
 	if value == 0 {
 		return ""
@@ -208,6 +387,10 @@ func int64String(value int64) string {
 }
 
 func (a *App) exportPath(ext string) (string, error) {
+	return a.exportPathNamed("result-"+timeStamp(), ext)
+}
+
+func (a *App) exportPathNamed(name, ext string) (string, error) {
 	trimmed := strings.TrimSpace(a.settings.ResultsPath)
 	if trimmed == "" {
 		trimmed = defaultResultsPath()
@@ -216,10 +399,10 @@ func (a *App) exportPath(ext string) (string, error) {
 	clean := filepath.Clean(trimmed)
 	info, err := os.Stat(clean)
 	if err == nil && info.IsDir() {
-		return filepath.Join(clean, fmt.Sprintf("result-%s.%s", timeStamp(), ext)), nil
+		return filepath.Join(clean, name+"."+ext), nil
 	}
 	if err != nil && os.IsNotExist(err) && (hasSeparator || filepath.Ext(clean) == "") {
-		return filepath.Join(clean, fmt.Sprintf("result-%s.%s", timeStamp(), ext)), nil
+		return filepath.Join(clean, name+"."+ext), nil
 	}
 	if filepath.Ext(clean) == "" {
 		return clean + "." + ext, nil
@@ -239,9 +422,11 @@ func newExportRecord(mode string, edition ping.Edition, host string, port int, r
 		SRVUsed:     details.SRVUsed,
 		SRVHost:     details.SRVHost,
 		SRVPort:     details.SRVPort,
+		Details:     details,
 	}
 	if runErr != nil {
 		record.Error = runErr.Error()
+		record.FailureClass = string(ping.ClassifyError(runErr))
 		return record
 	}
 	if link != nil {
@@ -264,6 +449,7 @@ func newExportRecord(mode string, edition ping.Edition, host string, port int, r
 		record.PlayersOnline = value.CurrentPlayers
 		record.PlayersMax = value.MaxPlayers
 		record.LatencyMillis = value.LatencyMillis
+		record.IconPNG = value.IconPNG
 	}
 	return record
 }
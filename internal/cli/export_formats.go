@@ -0,0 +1,93 @@
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"UWP-TCP-Con/internal/ping"
+)
+
+type ndjsonRecord struct {
+	Title     string `json:"title"`
+	CreatedAt string `json:"created_at"`
+	exportRecord
+}
+
+func writeMarkdownExport(w io.Writer, title string, createdAt time.Time, records []exportRecord) error {
+	online := 0
+	for _, record := range records {
+		if record.Success {
+			online++
+		}
+	}
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("# %s\n\n", markdownText(title)))
+	builder.WriteString(fmt.Sprintf("Saved at: %s\n\n", createdAt.Format(time.RFC3339)))
+	builder.WriteString(fmt.Sprintf("%d servers, %d online, %d failed\n\n", len(records), online, len(records)-online))
+	builder.WriteString("| Status | Server | Edition | Version | Players | Latency | MOTD |\n")
+	builder.WriteString("| --- | --- | --- | --- | ---: | ---: | --- |\n")
+	for _, record := range records {
+		server := fmt.Sprintf("`%s:%d`", record.Host, record.Port)
+		if record.Name != "" {
+			server = markdownText(ping.StripFormatting(record.Name)) + " " + server
+		}
+		if !record.Success {
+			builder.WriteString(fmt.Sprintf("| failed: %s | %s | %s | | | | |\n",
+				markdownCell(savedFailureText(record)), markdownCell(server), record.Edition))
+			continue
+		}
+		latency := ""
+		if record.LatencyMillis > 0 {
+			latency = fmt.Sprintf("%d ms", record.LatencyMillis)
+		}
+		motd := strings.TrimSpace(ping.StripFormatting(recordCleanMOTD(record)))
+		builder.WriteString(fmt.Sprintf("| online | %s | %s | %s | %d/%d | %s | %s |\n",
+			markdownCell(server), record.Edition, markdownCell(markdownText(record.Version)),
+			record.PlayersOnline, record.PlayersMax, latency, markdownCell(markdownText(motd))))
+	}
+	_, err := io.WriteString(w, builder.String())
+	return err
+}
+
+func writeNDJSONExport(w io.Writer, title string, createdAt time.Time, records []exportRecord) error {
+	encoder := json.NewEncoder(w)
+	for _, record := range records {
+		line := ndjsonRecord{Title: title, CreatedAt: createdAt.Format(time.RFC3339), exportRecord: record}
+		if err := encoder.Encode(line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func readNDJSONExport(data []byte) (exportPayload, error) {
+	var payload exportPayload
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var record ndjsonRecord
+		if err := json.Unmarshal(line, &record); err != nil {
+			return exportPayload{}, fmt.Errorf("line %d: %w", lineNumber, err)
+		}
+		if payload.Title == "" {
+			payload.Title = record.Title
+			payload.CreatedAt = record.CreatedAt
+		}
+		payload.Records = append(payload.Records, record.exportRecord)
+	}
+	if err := scanner.Err(); err != nil {
+		return exportPayload{}, err
+	}
+	return payload, nil
+}
@@ -0,0 +1,60 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMarkdownExport(t *testing.T) {
+	records := []exportRecord{
+		{Name: "Hub", Edition: "java", Host: "hub.example.com", Port: 25565, Success: true, Version: "1.21", PlayersOnline: 4, PlayersMax: 20, LatencyMillis: 42, CleanMOTD: "A | B\nline two"},
+		{Edition: "bedrock", Host: "down.example.com", Port: 19132, FailureClass: "connection_refused", Error: "refused"},
+	}
+	var builder strings.Builder
+	if err := writeMarkdownExport(&builder, "Batch check", time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC), records); err != nil {
+		t.Fatalf("writeMarkdownExport: %v", err)
+	}
+	text := builder.String()
+	for _, want := range []string{
+		"# Batch check\n",
+		"Saved at: 2026-01-02T03:04:05Z\n",
+		"2 servers, 1 online, 1 failed",
+		"| online | Hub `hub.example.com:25565` | java | 1.21 | 4/20 | 42 ms | A \\| B<br>line two |",
+		"| failed: connection refused: refused | `down.example.com:19132` | bedrock |",
+	} {
+		if !strings.Contains(text, want) {
+			t.Fatalf("markdown missing %q:\n%s", want, text)
+		}
+	}
+}
+
+func TestNDJSONExportRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "result.ndjson")
+	records := []exportRecord{
+		{Mode: "batch", Edition: "java", Host: "hub.example.com", Port: 25565, Success: true, Version: "1.21", Tags: []string{"eu"}},
+		{Mode: "batch", Edition: "bedrock", Host: "down.example.com", Port: 19132, Error: "refused"},
+	}
+	if err := writeExport(path, exportFormatNDJSON, "Batch check", "", records); err != nil {
+		t.Fatalf("writeExport: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 || !strings.HasPrefix(lines[0], `{"title":"Batch check","created_at":"`) || !strings.Contains(lines[1], `"host":"down.example.com"`) {
+		t.Fatalf("unexpected NDJSON:\n%s", data)
+	}
+	payload, err := loadExportPayload(path)
+	if err != nil {
+		t.Fatalf("loadExportPayload: %v", err)
+	}
+	if payload.Title != "Batch check" || payload.CreatedAt == "" || !reflect.DeepEqual(payload.Records, records) {
+		t.Fatalf("unexpected payload %#v", payload)
+	}
+}
@@ -0,0 +1,116 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type recordMetric struct {
+	name  string
+	help  string
+	value func(record exportRecord) (float64, bool)
+}
+
+var recordMetrics = []recordMetric{
+	{"mcquery_up", "Whether the server answered the status request.", func(r exportRecord) (float64, bool) { return boolMetric(r.Success), true }},
+	{"mcquery_players_online", "Players currently online.", func(r exportRecord) (float64, bool) { return float64(r.PlayersOnline), r.Success }},
+	{"mcquery_players_max", "Maximum player slots.", func(r exportRecord) (float64, bool) { return float64(r.PlayersMax), r.Success }},
+	{"mcquery_latency_seconds", "Status round-trip latency.", func(r exportRecord) (float64, bool) {
+		return float64(r.LatencyMillis) / 1000, r.Success && r.LatencyMillis > 0
+	}},
+	{"mcquery_protocol_version", "Protocol version reported by the server.", func(r exportRecord) (float64, bool) {
+		protocol, err := strconv.Atoi(r.Protocol)
+		return float64(protocol), r.Success && err == nil
+	}},
+}
+
+func writePrometheusExport(w io.Writer, createdAt time.Time, records []exportRecord) error {
+	records = latestRecords(records)
+	var builder strings.Builder
+	for _, metric := range recordMetrics {
+		builder.WriteString(fmt.Sprintf("# HELP %s %s\n# TYPE %s gauge\n", metric.name, metric.help, metric.name))
+		for _, record := range records {
+			value, ok := metric.value(record)
+			if !ok {
+				continue
+			}
+			builder.WriteString(fmt.Sprintf("%s{host=\"%s\",edition=\"%s\",port=\"%d\"} %s\n",
+				metric.name, escapeMetricLabel(record.Host), escapeMetricLabel(record.Edition), record.Port, strconv.FormatFloat(value, 'g', -1, 64)))
+		}
+	}
+	builder.WriteString("# HELP mcquery_export_timestamp_seconds Unix time the results were written.\n")
+	builder.WriteString("# TYPE mcquery_export_timestamp_seconds gauge\n")
+	builder.WriteString(fmt.Sprintf("mcquery_export_timestamp_seconds %d\n", createdAt.Unix()))
+	_, err := io.WriteString(w, builder.String())
+	return err
+}
+
+func writeInfluxExport(w io.Writer, createdAt time.Time, records []exportRecord) error {
+	var builder strings.Builder
+	for _, record := range latestRecords(records) {
+		fields := []string{fmt.Sprintf("up=%di", int(boolMetric(record.Success)))}
+		if record.Success {
+			fields = append(fields, fmt.Sprintf("players=%di", record.PlayersOnline), fmt.Sprintf("max=%di", record.PlayersMax))
+			if record.LatencyMillis > 0 {
+				fields = append(fields, fmt.Sprintf("latency=%di", record.LatencyMillis))
+			}
+			if protocol, err := strconv.Atoi(record.Protocol); err == nil {
+				fields = append(fields, fmt.Sprintf("protocol=%di", protocol))
+			}
+		}
+		builder.WriteString(fmt.Sprintf("mcquery,edition=%s,host=%s,port=%d %s %d\n",
+			escapeInfluxTag(record.Edition), escapeInfluxTag(record.Host), record.Port, strings.Join(fields, ","), createdAt.UnixNano()))
+	}
+	_, err := io.WriteString(w, builder.String())
+	return err
+}
+
+func appendInfluxExport(path string, createdAt time.Time, records []exportRecord) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	if err := writeInfluxExport(file, createdAt, records); err != nil {
+		_ = file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+func latestRecords(records []exportRecord) []exportRecord {
+	positions := make(map[string]int, len(records))
+	latest := make([]exportRecord, 0, len(records))
+	for _, record := range records {
+		key := diffRecordKey(record)
+		if index, ok := positions[key]; ok {
+			latest[index] = record
+			continue
+		}
+		positions[key] = len(latest)
+		latest = append(latest, record)
+	}
+	return latest
+}
+
+func escapeMetricLabel(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
+
+func escapeInfluxTag(value string) string {
+	return strings.NewReplacer(`\`, `\\`, ",", `\,`, "=", `\=`, " ", `\ `).Replace(value)
+}
+
+func boolMetric(value bool) float64 {
+	if value {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,92 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+var metricRecords = []exportRecord{
+	{Edition: "java", Host: "hub.example.com", Port: 25565, Success: true, Protocol: "767", PlayersOnline: 4, PlayersMax: 20, LatencyMillis: 42},
+	{Edition: "bedrock", Host: "down.example.com", Port: 19132, Error: "refused"},
+	{Edition: "bedrock", Host: "down.example.com", Port: 19132, Success: true, Protocol: "748", PlayersOnline: 1, PlayersMax: 10},
+}
+
+func TestPrometheusExport(t *testing.T) {
+	var builder strings.Builder
+	if err := writePrometheusExport(&builder, time.Unix(1700000000, 0), metricRecords); err != nil {
+		t.Fatalf("writePrometheusExport: %v", err)
+	}
+	text := builder.String()
+	for _, want := range []string{
+		"# TYPE mcquery_up gauge\n",
+		`mcquery_up{host="hub.example.com",edition="java",port="25565"} 1`,
+		`mcquery_latency_seconds{host="hub.example.com",edition="java",port="25565"} 0.042`,
+		`mcquery_protocol_version{host="hub.example.com",edition="java",port="25565"} 767`,
+		`mcquery_players_online{host="down.example.com",edition="bedrock",port="19132"} 1`,
+		"mcquery_export_timestamp_seconds 1700000000\n",
+	} {
+		if !strings.Contains(text, want) {
+			t.Fatalf("metrics missing %q:\n%s", want, text)
+		}
+	}
+	if strings.Count(text, `mcquery_up{host="down.example.com"`) != 1 {
+		t.Fatalf("repeated server should be written once:\n%s", text)
+	}
+	if strings.Contains(text, `mcquery_latency_seconds{host="down.example.com"`) {
+		t.Fatalf("unknown latency should be left out:\n%s", text)
+	}
+}
+
+func TestInfluxExport(t *testing.T) {
+	records := append([]exportRecord{{Edition: "java", Host: "odd host,=", Port: 25565, Error: "timeout"}}, metricRecords[:2]...)
+	path := filepath.Join(t.TempDir(), "watch.lp")
+	for round := int64(0); round < 2; round++ {
+		if err := appendInfluxExport(path, time.Unix(1700000000+round, 0), records); err != nil {
+			t.Fatalf("appendInfluxExport: %v", err)
+		}
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	want := []string{
+		`mcquery,edition=java,host=odd\ host\,\=,port=25565 up=0i 1700000000000000000`,
+		`mcquery,edition=java,host=hub.example.com,port=25565 up=1i,players=4i,max=20i,latency=42i,protocol=767i 1700000000000000000`,
+		`mcquery,edition=bedrock,host=down.example.com,port=19132 up=0i 1700000000000000000`,
+	}
+	if len(lines) != 6 {
+		t.Fatalf("expected two rounds of points, got:\n%s", data)
+	}
+	for i, line := range want {
+		if lines[i] != line {
+			t.Fatalf("line %d: got %q, want %q", i+1, lines[i], line)
+		}
+	}
+	if !strings.HasSuffix(lines[5], " 1700000001000000000") {
+		t.Fatalf("second round should carry its own timestamp: %q", lines[5])
+	}
+}
+
+func TestSavePrometheusExportUsesStableFile(t *testing.T) {
+	dir := t.TempDir()
+	app := &App{settings: defaultSettings()}
+	app.settings.ResultsPath = dir
+	app.settings.ExportFormat = exportFormatPrometheus
+	records := []exportRecord{{Mode: "batch", Edition: "java", Host: "hub.example.com", Port: 25565, Success: true}}
+	for i := 0; i < 2; i++ {
+		path, err := app.saveExport("Batch check", "", records)
+		if err != nil {
+			t.Fatalf("saveExport: %v", err)
+		}
+		if path != filepath.Join(dir, "batch.prom") {
+			t.Fatalf("unexpected path %q", path)
+		}
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 1 {
+		t.Fatalf("expected a single metrics file, got %d", len(entries))
+	}
+}
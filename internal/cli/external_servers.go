@@ -0,0 +1,308 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"UWP-TCP-Con/internal/ping"
+)
+
+var externalServersFormat = serverListFormat{
+	Title:       "external_servers.txt",
+	Edition:     ping.EditionBedrock,
+	DefaultPath: defaultExternalServersPath,
+	Read:        readBedrockServerList,
+	Write:       writeBedrockServerList,
+}
+
+type launcherServer struct {
+	Name    string `json:"name"`
+	Address string `json:"address"`
+	Port    int    `json:"port"`
+}
+
+func defaultExternalServersPath() string {
+	switch runtime.GOOS {
+	case "windows":
+		if localAppData := os.Getenv("LOCALAPPDATA"); localAppData != "" {
+			return filepath.Join(localAppData, "Packages", "Microsoft.MinecraftUWP_8wekyb3d8bbwe", "LocalState", "games", "com.mojang", "minecraftpe", "external_servers.txt")
+		}
+	case "linux":
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, ".local", "share", "mcpelauncher", "games", "com.mojang", "minecraftpe", "external_servers.txt")
+		}
+	}
+	return "external_servers.txt"
+}
+
+func isJSONServerList(path string, data []byte) bool {
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		return true
+	}
+	trimmed := bytes.TrimSpace(data)
+	return len(trimmed) > 0 && (trimmed[0] == '[' || trimmed[0] == '{')
+}
+
+func readBedrockServerList(path string) ([]serverListEntry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if isJSONServerList(path, data) {
+		return parseLauncherServers(data)
+	}
+	return parseExternalServers(string(data))
+}
+
+func writeBedrockServerList(path string, entries []serverListEntry, merge bool) (int, error) {
+	var existing []byte
+	if merge {
+		data, err := os.ReadFile(path)
+		if err != nil && !os.IsNotExist(err) {
+			return 0, err
+		}
+		existing = data
+	}
+	var data []byte
+	var added int
+	var err error
+	if isJSONServerList(path, existing) {
+		data, added, err = mergeLauncherServers(existing, entries)
+	} else {
+		data, added, err = mergeExternalServers(string(existing), entries, time.Now())
+	}
+	if err != nil {
+		return 0, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return 0, err
+	}
+	if err := backupServerList(path); err != nil {
+		return 0, err
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return 0, err
+	}
+	return added, nil
+}
+
+func parseExternalServers(text string) ([]serverListEntry, error) {
+	var entries []serverListEntry
+	for lineNumber, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		entry, ok := parseExternalServerLine(line)
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected index:name:host:port:timestamp", lineNumber+1)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+func parseExternalServerLine(line string) (serverListEntry, bool) {
+	parts := strings.Split(line, ":")
+	if len(parts) < 4 {
+		return serverListEntry{}, false
+	}
+	end := len(parts)
+	if len(parts) >= 5 {
+		if _, err := strconv.ParseInt(parts[end-1], 10, 64); err == nil {
+			if port, err := ping.ParsePort(parts[end-2]); err == nil && port > 0 {
+				end--
+			}
+		}
+	}
+	port, err := ping.ParsePort(parts[end-1])
+	if err != nil || port == 0 {
+		return serverListEntry{}, false
+	}
+	host := strings.Trim(strings.Join(parts[2:end-1], ":"), "[] ")
+	if host == "" {
+		return serverListEntry{}, false
+	}
+	name := strings.TrimSpace(parts[1])
+	if name == "" {
+		name = host
+	}
+	return serverListEntry{Name: name, Host: host, Port: port}, true
+}
+
+func mergeExternalServers(existing string, entries []serverListEntry, now time.Time) ([]byte, int, error) {
+	var builder strings.Builder
+	known := make(map[string]bool)
+	nextIndex := 1
+	for _, line := range strings.Split(existing, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if entry, ok := parseExternalServerLine(line); ok {
+			known[strings.ToLower(serverEntryAddress(entry))] = true
+		}
+		if index, err := strconv.Atoi(strings.SplitN(line, ":", 2)[0]); err == nil && index >= nextIndex {
+			nextIndex = index + 1
+		}
+		builder.WriteString(line + "\n")
+	}
+	added := 0
+	for _, entry := range entries {
+		key := strings.ToLower(serverEntryAddress(entry))
+		if known[key] {
+			continue
+		}
+		known[key] = true
+		name := strings.ReplaceAll(ping.StripFormatting(entry.Name), ":", " ")
+		fmt.Fprintf(&builder, "%d:%s:%s:%d:%d\n", nextIndex, name, entry.Host, entry.Port, now.Unix())
+		nextIndex++
+		added++
+	}
+	return []byte(builder.String()), added, nil
+}
+
+func parseLauncherServers(data []byte) ([]serverListEntry, error) {
+	items, _, _, err := launcherServerItems(data)
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]serverListEntry, 0, len(items))
+	for i, raw := range items {
+		var fields map[string]any
+		if err := json.Unmarshal(raw, &fields); err != nil {
+			return nil, fmt.Errorf("server %d: %w", i+1, err)
+		}
+		entry, ok := launcherServerEntry(fields)
+		if !ok {
+			return nil, fmt.Errorf("server %d: missing address", i+1)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+func launcherServerItems(data []byte) ([]json.RawMessage, map[string]json.RawMessage, string, error) {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) == 0 {
+		return nil, nil, "", nil
+	}
+	if trimmed[0] == '[' {
+		var items []json.RawMessage
+		if err := json.Unmarshal(trimmed, &items); err != nil {
+			return nil, nil, "", err
+		}
+		return items, nil, "", nil
+	}
+	var root map[string]json.RawMessage
+	if err := json.Unmarshal(trimmed, &root); err != nil {
+		return nil, nil, "", err
+	}
+	for _, key := range []string{"servers", "externalServers", "serverList"} {
+		if raw, ok := root[key]; ok {
+			var items []json.RawMessage
+			if err := json.Unmarshal(raw, &items); err != nil {
+				return nil, nil, "", fmt.Errorf("%s: %w", key, err)
+			}
+			return items, root, key, nil
+		}
+	}
+	return nil, root, "servers", nil
+}
+
+func launcherServerEntry(fields map[string]any) (serverListEntry, bool) {
+	text := func(keys ...string) string {
+		for _, key := range keys {
+			if value, ok := fields[key].(string); ok && strings.TrimSpace(value) != "" {
+				return strings.TrimSpace(value)
+			}
+		}
+		return ""
+	}
+	address := text("address", "ip", "host", "serverAddress", "url")
+	if address == "" {
+		return serverListEntry{}, false
+	}
+	host, port, ok := splitHostPortLoose(address)
+	if !ok {
+		host, port = strings.Trim(address, "[]"), 0
+	}
+	for _, key := range []string{"port", "serverPort"} {
+		switch value := fields[key].(type) {
+		case float64:
+			port = int(value)
+		case string:
+			if parsed, err := ping.ParsePort(value); err == nil {
+				port = parsed
+			}
+		}
+	}
+	if port <= 0 || port > 65535 {
+		port = ping.DefaultPort(ping.EditionBedrock)
+	}
+	name := text("name", "serverName", "title")
+	if name == "" {
+		name = host
+	}
+	return serverListEntry{Name: name, Host: host, Port: port}, true
+}
+
+func mergeLauncherServers(existing []byte, entries []serverListEntry) ([]byte, int, error) {
+	items, root, key, err := launcherServerItems(existing)
+	if err != nil {
+		return nil, 0, err
+	}
+	known := make(map[string]bool, len(items))
+	for _, raw := range items {
+		var fields map[string]any
+		if json.Unmarshal(raw, &fields) == nil {
+			if entry, ok := launcherServerEntry(fields); ok {
+				known[strings.ToLower(serverEntryAddress(entry))] = true
+			}
+		}
+	}
+	added := 0
+	for _, entry := range entries {
+		address := strings.ToLower(serverEntryAddress(entry))
+		if known[address] {
+			continue
+		}
+		known[address] = true
+		raw, err := json.Marshal(launcherServer{Name: ping.StripFormatting(entry.Name), Address: entry.Host, Port: entry.Port})
+		if err != nil {
+			return nil, 0, err
+		}
+		items = append(items, raw)
+		added++
+	}
+	if items == nil {
+		items = []json.RawMessage{}
+	}
+
+	var output any
+	switch {
+	case root != nil:
+		list, err := json.Marshal(items)
+		if err != nil {
+			return nil, 0, err
+		}
+		root[key] = list
+		output = root
+	case len(bytes.TrimSpace(existing)) > 0:
+		output = items
+	default:
+		output = map[string]any{"servers": items}
+	}
+	data, err := json.MarshalIndent(output, "", "  ")
+	if err != nil {
+		return nil, 0, err
+	}
+	return append(data, '\n'), added, nil
+}
@@ -0,0 +1,91 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseExternalServers(t *testing.T) {
+	entries, err := parseExternalServers("1:Hive:geo.hivebedrock.network:19132:1700000000\n\n2:Lab:2001:db8::1:19133:1700000001\n3:Old:play.example.com:19134\n")
+	if err != nil {
+		t.Fatalf("parseExternalServers: %v", err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("expected 3 entries, got %+v", entries)
+	}
+	if entries[0].Name != "Hive" || entries[0].Host != "geo.hivebedrock.network" || entries[0].Port != 19132 {
+		t.Fatalf("unexpected first entry: %+v", entries[0])
+	}
+	if entries[1].Host != "2001:db8::1" || entries[1].Port != 19133 {
+		t.Fatalf("unexpected IPv6 entry: %+v", entries[1])
+	}
+	if entries[2].Host != "play.example.com" || entries[2].Port != 19134 {
+		t.Fatalf("unexpected entry without timestamp: %+v", entries[2])
+	}
+	if _, err := parseExternalServers("1:broken"); err == nil || !strings.Contains(err.Error(), "line 1") {
+		t.Fatalf("expected line error, got %v", err)
+	}
+}
+
+func TestMergeExternalServers(t *testing.T) {
+	data, added, err := mergeExternalServers("4:Hive:geo.hivebedrock.network:19132:1700000000\n", []serverListEntry{
+		{Name: "Hive again", Host: "GEO.hivebedrock.network", Port: 19132},
+		{Name: "New: server", Host: "play.example.com", Port: 19132},
+	}, time.Unix(1800000000, 0))
+	if err != nil {
+		t.Fatalf("mergeExternalServers: %v", err)
+	}
+	want := "4:Hive:geo.hivebedrock.network:19132:1700000000\n5:New  server:play.example.com:19132:1800000000\n"
+	if added != 1 || string(data) != want {
+		t.Fatalf("unexpected merge (%d):\n%s", added, data)
+	}
+}
+
+func TestLauncherServerLists(t *testing.T) {
+	entries, err := parseLauncherServers([]byte(`{"version":2,"externalServers":[{"serverName":"Hive","serverAddress":"geo.hivebedrock.network","serverPort":"19132"},{"name":"Lab","ip":"lab.example.com:19140"}]}`))
+	if err != nil {
+		t.Fatalf("parseLauncherServers: %v", err)
+	}
+	if len(entries) != 2 || entries[0].Name != "Hive" || entries[0].Port != 19132 || entries[1].Host != "lab.example.com" || entries[1].Port != 19140 {
+		t.Fatalf("unexpected entries: %+v", entries)
+	}
+
+	path := filepath.Join(t.TempDir(), "servers.json")
+	if err := os.WriteFile(path, []byte(`{"version":2,"externalServers":[{"serverName":"Hive","serverAddress":"geo.hivebedrock.network","serverPort":19132}]}`), 0o644); err != nil {
+		t.Fatalf("write fixture: %v", err)
+	}
+	added, err := writeBedrockServerList(path, []serverListEntry{
+		{Name: "Hive", Host: "geo.hivebedrock.network", Port: 19132},
+		{Name: "Lab", Host: "lab.example.com", Port: 19140},
+	}, true)
+	if err != nil || added != 1 {
+		t.Fatalf("writeBedrockServerList: added=%d err=%v", added, err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read merged: %v", err)
+	}
+	var merged struct {
+		Version         int               `json:"version"`
+		ExternalServers []json.RawMessage `json:"externalServers"`
+	}
+	if err := json.Unmarshal(data, &merged); err != nil {
+		t.Fatalf("decode merged: %v", err)
+	}
+	if merged.Version != 2 || len(merged.ExternalServers) != 2 || !strings.Contains(string(merged.ExternalServers[1]), `"lab.example.com"`) {
+		t.Fatalf("unexpected merged file:\n%s", data)
+	}
+
+	fresh := filepath.Join(t.TempDir(), "new.json")
+	if _, err := writeBedrockServerList(fresh, []serverListEntry{{Name: "Lab", Host: "lab.example.com", Port: 19140}}, false); err != nil {
+		t.Fatalf("write new list: %v", err)
+	}
+	entries, err = readBedrockServerList(fresh)
+	if err != nil || len(entries) != 1 || entries[0].Port != 19140 {
+		t.Fatalf("unexpected new list: %+v, %v", entries, err)
+	}
+}
@@ -18,6 +18,7 @@ type favorite struct {
 	Port       int          `json:"port"`
 	CreatedAt  string       `json:"created_at"`
 	LastUsedAt string       `json:"last_used_at,omitempty"`
+	Icon       string       `json:"icon,omitempty"`
 }
 
 func loadFavorites() ([]favorite, error) {
@@ -64,6 +65,10 @@ func (a *App) manageFavorites() error {
 			fmt.Sprintf("Run favorite: %d saved", len(favorites)),
 			"Add favorite: Save a server profile",
 			"Delete favorite: Remove a saved profile",
+			"Import servers.dat: Java client server list",
+			"Export servers.dat: Favorites or recent results",
+			"Import Bedrock list: external_servers.txt or launcher JSON",
+			"Export Bedrock list: Favorites or recent results",
 			"Back",
 		}
 		index, err := selectOption("Favorites", options)
@@ -125,6 +130,22 @@ func (a *App) manageFavorites() error {
 			if err := saveFavorites(favorites); err != nil {
 				return err
 			}
+		case 3:
+			if err := a.importServerListFile(serversDatFormat); err != nil {
+				return err
+			}
+		case 4:
+			if err := a.exportServerListFile(serversDatFormat); err != nil {
+				return err
+			}
+		case 5:
+			if err := a.importServerListFile(externalServersFormat); err != nil {
+				return err
+			}
+		case 6:
+			if err := a.exportServerListFile(externalServersFormat); err != nil {
+				return err
+			}
 		default:
 			return nil
 		}
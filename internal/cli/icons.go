@@ -0,0 +1,250 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"strings"
+)
+
+const (
+	iconColumns    = 16
+	iconRows       = 8
+	iconGutter     = iconColumns + 2
+	sixelCellSize  = 16
+	kittyDeleteAll = "\033_Ga=d,q=2\033\\"
+)
+
+type frameImage struct {
+	row      int
+	column   int
+	sequence string
+	kitty    bool
+}
+
+var (
+	pendingFrameImage *frameImage
+	kittyImageVisible bool
+)
+
+func (a *App) renderResultPageAndWait(title, content string, icon []byte) error {
+	protocol := imageNone
+	if a.settings.TerminalIcons {
+		protocol = terminalImageProtocol()
+	}
+	renderIconPage(title, content, icon, protocol)
+	return waitForEnter()
+}
+
+func renderIconPage(title, content string, icon []byte, protocol imageProtocol) {
+	lines := strings.Split(content, "\n")
+	if protocol == imageNone || len(icon) == 0 || contentWidth() < iconGutter+32 {
+		renderPage(title, lines)
+		return
+	}
+	img, err := png.Decode(bytes.NewReader(icon))
+	if err != nil {
+		renderPage(title, lines)
+		return
+	}
+
+	body := pageBody(lines, contentWidth()-iconGutter)
+	for len(body) < iconRows {
+		body = append(body, "")
+	}
+	var blocks []string
+	if protocol == imageBlocks {
+		blocks = halfBlockIcon(img)
+	}
+	padding := strings.Repeat(" ", iconGutter)
+	for i := range body {
+		if i < len(blocks) {
+			body[i] = blocks[i] + "  " + body[i]
+		} else {
+			body[i] = padding + body[i]
+		}
+	}
+
+	overlay := &frameImage{row: len(buildHeaderLines(title)) + 1, column: 1}
+	switch protocol {
+	case imageKitty:
+		overlay.sequence = kittyIconSequence(icon)
+		overlay.kitty = true
+	case imageITerm:
+		overlay.sequence = itermIconSequence(icon)
+	case imageSixel:
+		overlay.sequence = sixelIconSequence(img)
+	default:
+		overlay = nil
+	}
+	pendingFrameImage = overlay
+	renderFrame(title, body)
+}
+
+func drawFrameImage(overlay frameImage, cursorRow int) {
+	fmt.Print("\0337")
+	moveCursorUp(cursorRow - overlay.row)
+	moveCursorColumn(overlay.column)
+	fmt.Print(overlay.sequence)
+	fmt.Print("\0338")
+	if overlay.kitty {
+		kittyImageVisible = true
+	}
+}
+
+func clearFrameImages() {
+	if kittyImageVisible {
+		fmt.Print(kittyDeleteAll)
+		kittyImageVisible = false
+	}
+}
+
+func kittyIconSequence(data []byte) string {
+	encoded := base64.StdEncoding.EncodeToString(data)
+	var builder strings.Builder
+	for start := 0; start < len(encoded); start += 4096 {
+		end := minInt(start+4096, len(encoded))
+		more := 0
+		if end < len(encoded) {
+			more = 1
+		}
+		if start == 0 {
+			fmt.Fprintf(&builder, "\033_Ga=T,f=100,q=2,C=1,c=%d,r=%d,m=%d;%s\033\\", iconColumns, iconRows, more, encoded[start:end])
+		} else {
+			fmt.Fprintf(&builder, "\033_Gm=%d;%s\033\\", more, encoded[start:end])
+		}
+	}
+	return builder.String()
+}
+
+func itermIconSequence(data []byte) string {
+	return fmt.Sprintf("\033]1337;File=inline=1;size=%d;width=%d;height=%d;preserveAspectRatio=1:%s\a", len(data), iconColumns, iconRows, base64.StdEncoding.EncodeToString(data))
+}
+
+func iconPixel(img image.Image, x, y, size int) color.NRGBA {
+	bounds := img.Bounds()
+	x0 := bounds.Min.X + x*bounds.Dx()/size
+	x1 := maxInt(bounds.Min.X+(x+1)*bounds.Dx()/size, x0+1)
+	y0 := bounds.Min.Y + y*bounds.Dy()/size
+	y1 := maxInt(bounds.Min.Y+(y+1)*bounds.Dy()/size, y0+1)
+	var r, g, b, a, count uint64
+	for sy := y0; sy < y1; sy++ {
+		for sx := x0; sx < x1; sx++ {
+			pr, pg, pb, pa := img.At(sx, sy).RGBA()
+			r += uint64(pr)
+			g += uint64(pg)
+			b += uint64(pb)
+			a += uint64(pa)
+			count++
+		}
+	}
+	if a == 0 {
+		return color.NRGBA{}
+	}
+	return color.NRGBA{
+		R: uint8(r * 0xFF / a),
+		G: uint8(g * 0xFF / a),
+		B: uint8(b * 0xFF / a),
+		A: uint8(a / count >> 8),
+	}
+}
+
+func halfBlockIcon(img image.Image) []string {
+	lines := make([]string, 0, iconRows)
+	for row := 0; row < iconRows; row++ {
+		var builder strings.Builder
+		for x := 0; x < iconColumns; x++ {
+			top := iconPixel(img, x, row*2, iconColumns)
+			bottom := iconPixel(img, x, row*2+1, iconColumns)
+			switch {
+			case top.A < 128 && bottom.A < 128:
+				builder.WriteString(colorReset + " ")
+			case bottom.A < 128:
+				fmt.Fprintf(&builder, "%s\033[38;2;%d;%d;%dm▀", colorReset, top.R, top.G, top.B)
+			case top.A < 128:
+				fmt.Fprintf(&builder, "%s\033[38;2;%d;%d;%dm▄", colorReset, bottom.R, bottom.G, bottom.B)
+			default:
+				fmt.Fprintf(&builder, "\033[38;2;%d;%d;%dm\033[48;2;%d;%d;%dm▀", top.R, top.G, top.B, bottom.R, bottom.G, bottom.B)
+			}
+		}
+		builder.WriteString(colorReset)
+		lines = append(lines, builder.String())
+	}
+	return lines
+}
+
+func sixelIconSequence(img image.Image) string {
+	size := iconRows * sixelCellSize
+	pixels := make([]int, size*size)
+	var used [216]bool
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			pixel := iconPixel(img, x, y, size)
+			if pixel.A < 128 {
+				pixels[y*size+x] = -1
+				continue
+			}
+			index := int(pixel.R)*6/256*36 + int(pixel.G)*6/256*6 + int(pixel.B)*6/256
+			pixels[y*size+x] = index
+			used[index] = true
+		}
+	}
+
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "\033P0;1;0q\"1;1;%d;%d", size, size)
+	for index, ok := range used {
+		if ok {
+			fmt.Fprintf(&builder, "#%d;2;%d;%d;%d", index, index/36*20, index/6%6*20, index%6*20)
+		}
+	}
+	row := make([]byte, size)
+	for band := 0; band < size; band += 6 {
+		first := true
+		for index, ok := range used {
+			if !ok {
+				continue
+			}
+			present := false
+			for x := 0; x < size; x++ {
+				bits := 0
+				for k := 0; k < 6 && band+k < size; k++ {
+					if pixels[(band+k)*size+x] == index {
+						bits |= 1 << k
+					}
+				}
+				row[x] = byte(63 + bits)
+				present = present || bits != 0
+			}
+			if !present {
+				continue
+			}
+			if !first {
+				builder.WriteByte('$')
+			}
+			first = false
+			fmt.Fprintf(&builder, "#%d", index)
+			writeSixelRow(&builder, row)
+		}
+		builder.WriteByte('-')
+	}
+	builder.WriteString("\033\\")
+	return builder.String()
+}
+
+func writeSixelRow(builder *strings.Builder, row []byte) {
+	for i := 0; i < len(row); {
+		j := i
+		for j < len(row) && row[j] == row[i] {
+			j++
+		}
+		if count := j - i; count > 3 {
+			fmt.Fprintf(builder, "!%d%c", count, row[i])
+		} else {
+			builder.Write(row[i:j])
+		}
+		i = j
+	}
+}
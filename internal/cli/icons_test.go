@@ -0,0 +1,55 @@
+package cli
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"strings"
+	"testing"
+)
+
+func testIcon() *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, 64, 64))
+	for y := 0; y < 64; y++ {
+		for x := 0; x < 64; x++ {
+			if y < 32 {
+				img.Set(x, y, color.NRGBA{R: 255, A: 255})
+			}
+		}
+	}
+	return img
+}
+
+func TestHalfBlockIcon(t *testing.T) {
+	lines := halfBlockIcon(testIcon())
+	if len(lines) != iconRows {
+		t.Fatalf("expected %d lines, got %d", iconRows, len(lines))
+	}
+	if !strings.Contains(lines[0], "\033[38;2;255;0;0m\033[48;2;255;0;0m▀") {
+		t.Fatalf("expected opaque red cells, got %q", lines[0])
+	}
+	if strings.Contains(lines[iconRows-1], "38;2") || printableWidth(lines[iconRows-1]) != iconColumns {
+		t.Fatalf("expected transparent bottom row, got %q", lines[iconRows-1])
+	}
+}
+
+func TestKittyIconSequenceChunks(t *testing.T) {
+	data := bytes.Repeat([]byte{1}, 4000)
+	sequence := kittyIconSequence(data)
+	if !strings.HasPrefix(sequence, "\033_Ga=T,f=100,q=2,C=1,c=16,r=8,m=1;") {
+		t.Fatalf("unexpected first chunk: %q", sequence[:48])
+	}
+	if strings.Count(sequence, "\033_G") != 2 || !strings.Contains(sequence, "\033_Gm=0;") {
+		t.Fatalf("expected two chunks ending with m=0")
+	}
+}
+
+func TestSixelIconSequence(t *testing.T) {
+	sequence := sixelIconSequence(testIcon())
+	if !strings.HasPrefix(sequence, "\033P0;1;0q\"1;1;128;128#180;2;100;0;0") || !strings.HasSuffix(sequence, "-\033\\") {
+		t.Fatalf("unexpected sixel framing: %q", sequence[:40])
+	}
+	if strings.Count(sequence, "-") != 22 {
+		t.Fatalf("expected 22 sixel bands, got %d", strings.Count(sequence, "-"))
+	}
+}
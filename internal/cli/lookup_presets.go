@@ -11,6 +11,8 @@ import (
 type lookupPresets struct {
 	Subdomains []string `json:"subdomains"`
 	Endings    []string `json:"endings"`
+	Filters    []string `json:"filters,omitempty"`
+	Sorts      []string `json:"sorts,omitempty"`
 }
 
 func loadLookupPresets() (lookupPresets, error) {
@@ -64,6 +66,12 @@ func (a *App) manageLookupPresets() error {
 			fmt.Sprintf("Add endings: %d saved", len(presets.Endings)),
 			"Remove ending: Delete one saved ending",
 			"Clear endings: Delete all saved endings",
+			fmt.Sprintf("Add filter: %d saved", len(presets.Filters)),
+			"Remove filter: Delete one saved filter",
+			fmt.Sprintf("Add sort: %d saved", len(presets.Sorts)),
+			"Remove sort: Delete one saved sort",
+			"Learned order: View hit statistics",
+			"Reset learned order: Forget recorded hits",
 			"Back",
 		}
 		index, err := selectOption("Lookup presets", options)
@@ -107,6 +115,48 @@ func (a *App) manageLookupPresets() error {
 			} else if ok {
 				presets.Endings = nil
 			}
+		case 6:
+			value, err := promptViewExpression("Add filter", `Expression: e.g. players > 10 && version ~ "1.20"`, validateFilterExpression)
+			if err != nil {
+				return err
+			}
+			presets.Filters = mergeUniqueStrings(presets.Filters, nonEmptyStrings(value))
+		case 7:
+			updated, err := removePresetEntry("Remove filter", presets.Filters)
+			if err != nil {
+				return err
+			}
+			presets.Filters = updated
+		case 8:
+			value, err := promptViewExpression("Add sort", "Sort keys: e.g. players desc, latency, host", validateSortExpression)
+			if err != nil {
+				return err
+			}
+			presets.Sorts = mergeUniqueStrings(presets.Sorts, nonEmptyStrings(value))
+		case 9:
+			updated, err := removePresetEntry("Remove sort", presets.Sorts)
+			if err != nil {
+				return err
+			}
+			presets.Sorts = updated
+		case 10:
+			stats, err := loadLookupStats()
+			if err != nil {
+				return err
+			}
+			if err := renderTextPageAndWait("Learned order", formatLookupStats(stats)); err != nil {
+				return err
+			}
+			continue
+		case 11:
+			if ok, err := askConfirm("Reset learned lookup statistics?"); err != nil {
+				return err
+			} else if ok {
+				if err := resetLookupStats(); err != nil {
+					return err
+				}
+			}
+			continue
 		default:
 			return nil
 		}
@@ -168,6 +218,16 @@ func normalizePresetEndings(values []string) []string {
 	return list
 }
 
+func nonEmptyStrings(values ...string) []string {
+	list := make([]string, 0, len(values))
+	for _, value := range values {
+		if value != "" {
+			list = append(list, value)
+		}
+	}
+	return list
+}
+
 func splitListAllowEmpty(value string) []string {
 	if strings.TrimSpace(value) == "" {
 		return []string{""}
@@ -17,6 +17,9 @@ type lookupProgressView struct {
 	progress      ping.LookupProgress
 	total         int
 	concurrency   int
+	adaptive      bool
+	adjustment    string
+	showPattern   bool
 	rateLimit     int
 	timeout       time.Duration
 	retryCount    int
@@ -28,19 +31,30 @@ type lookupProgressView struct {
 }
 
 func newLookupProgressView(settings Settings, config LookupConfig) *lookupProgressView {
-	total := countLookupSubdomains(config.Subdomains) * countLookupEndings(config.Endings) * maxInt(countLookupPorts(config), 1)
+	generated := 0
+	if config.Source != lookupSourceImported {
+		generated = countLookupSubdomains(config.Subdomains) * countLookupEndings(config.Endings)
+	}
+	total := (generated + len(config.Imported)) * maxInt(countLookupPorts(config), 1)
+	view := newLookupProgressViewForTotal(settings, config.Edition, total)
+	view.showPattern = true
+	return view
+}
+
+func newLookupProgressViewForTotal(settings Settings, edition ping.Edition, total int) *lookupProgressView {
 	concurrency := resolveLookupConcurrency(settings.LookupConcurrency, total)
 	view := &lookupProgressView{
-		edition:     config.Edition,
+		edition:     edition,
 		startedAt:   time.Now(),
 		total:       total,
 		concurrency: concurrency,
+		adaptive:    settings.AdaptiveConcurrency,
 		rateLimit:   settings.LookupRateLimit,
 		timeout:     settings.RequestTimeout(),
 		retryCount:  settings.RetryCount,
 		retryDelay:  settings.RetryDelay(),
 	}
-	view.initialRate = estimateLookupInitialRate(config.Edition, concurrency, settings.LookupRateLimit, view.timeout, view.retryCount, view.retryDelay)
+	view.initialRate = estimateLookupInitialRate(edition, concurrency, settings.LookupRateLimit, view.timeout, view.retryCount, view.retryDelay)
 	return view
 }
 
@@ -54,6 +68,12 @@ func (v *lookupProgressView) Observe(progress ping.LookupProgress) {
 			v.concurrency = progress.Total
 		}
 	}
+	if progress.Workers > 0 {
+		v.concurrency = progress.Workers
+	}
+	if progress.Adjustment != "" {
+		v.adjustment = progress.Adjustment
+	}
 
 	now := time.Now()
 	if progress.Completed > v.lastCompleted {
@@ -91,6 +111,9 @@ func (v *lookupProgressView) Render(frame int) string {
 	progress := v.progress
 	total := v.total
 	concurrency := v.concurrency
+	adaptive := v.adaptive
+	adjustment := v.adjustment
+	showPattern := v.showPattern
 	rateLimit := v.rateLimit
 	timeout := v.timeout
 	retryCount := v.retryCount
@@ -170,7 +193,19 @@ func (v *lookupProgressView) Render(frame int) string {
 		fmt.Sprintf("Pipeline: %d workers | %s remaining | %s cap", concurrency, formatLookupNumber(remaining), formatLookupRateCap(rateLimit)),
 		fmt.Sprintf("Stage: %s | confidence %s", lookupStage(progress.Completed, progress.Total), lookupConfidence(progress.Completed, progress.Total)),
 		fmt.Sprintf("Target: %s:%s", host, port),
-		fmt.Sprintf("Pattern: subdomain %s | ending %s", subdomain, ending),
+	}
+	if showPattern {
+		pattern := fmt.Sprintf("Pattern: subdomain %s | ending %s", subdomain, ending)
+		if progress.Source != "" && progress.Source != ping.LookupSourceGenerated {
+			pattern += fmt.Sprintf(" | source %s", progress.Source)
+		}
+		lines = append(lines, pattern)
+	}
+	if adaptive {
+		if adjustment == "" {
+			adjustment = "calibrating"
+		}
+		lines = append(lines, fmt.Sprintf("Tuning: %s", adjustment))
 	}
 	return strings.Join(lines, "\n")
 }
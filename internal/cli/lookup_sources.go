@@ -0,0 +1,238 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"UWP-TCP-Con/internal/ping"
+)
+
+type lookupSource int
+
+const (
+	lookupSourceGenerated lookupSource = iota
+	lookupSourceImported
+	lookupSourceBoth
+)
+
+type candidateImport struct {
+	Path   string
+	Format string
+	Names  int
+	Kept   int
+}
+
+var candidateNamePattern = regexp.MustCompile(`(?i)(?:\*\.)?(?:[a-z0-9_](?:[a-z0-9_-]{0,61}[a-z0-9])?\.)+[a-z][a-z0-9-]{0,62}`)
+
+var passiveDNSColumns = map[string]struct{}{
+	"name":        {},
+	"rrname":      {},
+	"query":       {},
+	"qname":       {},
+	"domain":      {},
+	"hostname":    {},
+	"host":        {},
+	"fqdn":        {},
+	"common_name": {},
+	"name_value":  {},
+	"subdomain":   {},
+}
+
+func (a *App) askLookupSource() (lookupSource, error) {
+	index, err := selectOption("Candidate source", []string{
+		"Generated: Subdomain and ending combinations",
+		"Imported: Names from CT or passive-DNS files",
+		"Both: Imported names first, then generated",
+	})
+	if err != nil {
+		return lookupSourceGenerated, err
+	}
+	return lookupSource(index), nil
+}
+
+func (a *App) askCandidateFiles(baseHost string) ([]ping.LookupTarget, []candidateImport, error) {
+	var targets []ping.LookupTarget
+	var imports []candidateImport
+	var errMsg string
+	for {
+		path, err := promptInput("Candidate file", "crt.sh JSON, CT log dump, passive-DNS CSV or a plain hostname list", errMsg)
+		if err != nil {
+			return nil, nil, err
+		}
+		path = strings.TrimSpace(path)
+		if path == "" {
+			errMsg = "Path cannot be empty"
+			continue
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			errMsg = friendlyErrorMessage(err)
+			continue
+		}
+		names, format := extractCandidateNames(data)
+		found := candidateTargets(names, baseHost, filepath.Base(path))
+		imports = append(imports, candidateImport{Path: path, Format: format, Names: len(names), Kept: len(found)})
+		targets = append(targets, found...)
+		errMsg = ""
+
+		more, err := askConfirm(fmt.Sprintf("%d names under %s from %s. Add another file?", len(found), baseHost, filepath.Base(path)))
+		if err != nil {
+			return nil, nil, err
+		}
+		if !more {
+			return targets, imports, nil
+		}
+	}
+}
+
+func extractCandidateNames(data []byte) ([]string, string) {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) > 0 && (trimmed[0] == '[' || trimmed[0] == '{') {
+		if names, ok := extractCTJSONNames(trimmed); ok {
+			return names, "crt.sh JSON"
+		}
+	}
+	if names, ok := extractPassiveDNSNames(trimmed); ok {
+		return names, "passive-DNS CSV"
+	}
+	return candidateNamePattern.FindAllString(string(data), -1), "hostname scan"
+}
+
+func extractCTJSONNames(data []byte) ([]string, bool) {
+	var entries []map[string]any
+	if err := json.Unmarshal(data, &entries); err != nil {
+		var single map[string]any
+		if err := json.Unmarshal(data, &single); err != nil {
+			return nil, false
+		}
+		entries = []map[string]any{single}
+	}
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		for _, key := range []string{"name_value", "common_name"} {
+			value, ok := entry[key].(string)
+			if !ok {
+				continue
+			}
+			names = append(names, strings.Fields(value)...)
+		}
+	}
+	return names, len(names) > 0
+}
+
+func extractPassiveDNSNames(data []byte) ([]string, bool) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+	reader.Comment = '#'
+	header, err := reader.Read()
+	if err != nil || len(header) < 2 {
+		return nil, false
+	}
+	columns := make([]int, 0)
+	for i, name := range header {
+		if _, ok := passiveDNSColumns[strings.ToLower(strings.TrimSpace(name))]; ok {
+			columns = append(columns, i)
+		}
+	}
+	if len(columns) == 0 {
+		return nil, false
+	}
+	names := make([]string, 0)
+	for {
+		record, err := reader.Read()
+		if err != nil {
+			break
+		}
+		for _, column := range columns {
+			if column < len(record) {
+				names = append(names, record[column])
+			}
+		}
+	}
+	return names, true
+}
+
+func candidateTargets(names []string, baseHost, source string) []ping.LookupTarget {
+	base := strings.Split(strings.Trim(strings.ToLower(strings.TrimSpace(baseHost)), "."), ".")
+	targets := make([]ping.LookupTarget, 0)
+	seen := make(map[string]struct{})
+	for _, name := range names {
+		host := normalizeCandidateName(name)
+		if host == "" {
+			continue
+		}
+		if _, ok := seen[host]; ok {
+			continue
+		}
+		subdomain, ending, ok := splitCandidateName(host, base)
+		if !ok {
+			continue
+		}
+		seen[host] = struct{}{}
+		targets = append(targets, ping.LookupTarget{
+			Host:      host,
+			Subdomain: subdomain,
+			Ending:    ending,
+			Source:    source,
+		})
+	}
+	return targets
+}
+
+func normalizeCandidateName(value string) string {
+	value = strings.ToLower(strings.TrimSpace(value))
+	value = strings.Trim(value, "\"'")
+	for strings.HasPrefix(value, "*.") {
+		value = strings.TrimPrefix(value, "*.")
+	}
+	value = strings.TrimSuffix(value, ".")
+	if value == "" || strings.ContainsAny(value, "*@/: ") {
+		return ""
+	}
+	if !candidateNamePattern.MatchString(value) || candidateNamePattern.FindString(value) != value {
+		return ""
+	}
+	return value
+}
+
+func splitCandidateName(host string, base []string) (string, string, bool) {
+	labels := strings.Split(host, ".")
+	if len(base) == 0 || len(labels) < len(base) {
+		return "", "", false
+	}
+	for start := len(labels) - len(base); start >= 0; start-- {
+		matched := true
+		for i, label := range base {
+			if labels[start+i] != label {
+				matched = false
+				break
+			}
+		}
+		if !matched {
+			continue
+		}
+		ending := strings.Join(labels[start+len(base):], ".")
+		if ending == "" && len(base) < 2 {
+			continue
+		}
+		return strings.Join(labels[:start], "."), ending, true
+	}
+	return "", "", false
+}
+
+func lookupSourceLabel(source lookupSource) string {
+	switch source {
+	case lookupSourceImported:
+		return "imported files"
+	case lookupSourceBoth:
+		return "imported files, then generated"
+	default:
+		return "generated combinations"
+	}
+}
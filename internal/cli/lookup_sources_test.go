@@ -0,0 +1,59 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestExtractCandidateNamesFromCrtSHJSON(t *testing.T) {
+	data := []byte(`[
+		{"issuer_name": "C=US, O=Let's Encrypt", "common_name": "example.com", "name_value": "example.com\n*.play.example.com"},
+		{"common_name": "mc.example.net", "name_value": "mc.example.net\nshop.other.org"}
+	]`)
+	names, format := extractCandidateNames(data)
+	if format != "crt.sh JSON" {
+		t.Fatalf("unexpected format: %s", format)
+	}
+	targets := candidateTargets(names, "example", "crt.json")
+	var hosts []string
+	for _, target := range targets {
+		hosts = append(hosts, target.Host)
+	}
+	if strings.Join(hosts, ",") != "example.com,play.example.com,mc.example.net" {
+		t.Fatalf("unexpected hosts: %v", hosts)
+	}
+	if targets[1].Subdomain != "play" || targets[1].Ending != "com" || targets[1].Source != "crt.json" {
+		t.Fatalf("unexpected split: %+v", targets[1])
+	}
+}
+
+func TestExtractCandidateNamesFromPassiveDNSCSV(t *testing.T) {
+	data := []byte("rrname,rrtype,rdata,time_first\nhub.example.de.,A,192.0.2.4,1700000000\nexample.de,NS,ns1.host.net,1700000000\n")
+	names, format := extractCandidateNames(data)
+	if format != "passive-DNS CSV" {
+		t.Fatalf("unexpected format: %s", format)
+	}
+	targets := candidateTargets(names, "example", "pdns.csv")
+	if len(targets) != 2 || targets[0].Host != "hub.example.de" || targets[1].Subdomain != "" {
+		t.Fatalf("unexpected targets: %+v", targets)
+	}
+}
+
+func TestExtractCandidateNamesFallsBackToHostnameScan(t *testing.T) {
+	data := []byte("# CT dump\n2024-01-01 serial=01 san=play.example.co.uk,admin@example.org\nEXAMPLE.org\nnotexample.org\n")
+	names, format := extractCandidateNames(data)
+	if format != "hostname scan" {
+		t.Fatalf("unexpected format: %s", format)
+	}
+	targets := candidateTargets(names, "example", "dump.txt")
+	var hosts []string
+	for _, target := range targets {
+		hosts = append(hosts, target.Host)
+	}
+	if strings.Join(hosts, ",") != "play.example.co.uk,example.org" {
+		t.Fatalf("unexpected hosts: %v", hosts)
+	}
+	if targets[0].Ending != "co.uk" {
+		t.Fatalf("unexpected ending: %q", targets[0].Ending)
+	}
+}
@@ -0,0 +1,233 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"UWP-TCP-Con/internal/ping"
+)
+
+const lookupStatsTopEntries = 15
+
+type lookupStats struct {
+	Lookups    int            `json:"lookups"`
+	Matches    int            `json:"matches"`
+	Subdomains map[string]int `json:"subdomains"`
+	Endings    map[string]int `json:"endings"`
+	Ports      map[int]int    `json:"ports"`
+	UpdatedAt  time.Time      `json:"updated_at,omitempty"`
+}
+
+func newLookupStats() lookupStats {
+	return lookupStats{
+		Subdomains: make(map[string]int),
+		Endings:    make(map[string]int),
+		Ports:      make(map[int]int),
+	}
+}
+
+func loadLookupStats() (lookupStats, error) {
+	path, err := configFile("lookup-stats.json")
+	if err != nil {
+		return newLookupStats(), err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return newLookupStats(), nil
+		}
+		return newLookupStats(), err
+	}
+	stats := newLookupStats()
+	if err := json.Unmarshal(data, &stats); err != nil {
+		return newLookupStats(), err
+	}
+	if stats.Subdomains == nil {
+		stats.Subdomains = make(map[string]int)
+	}
+	if stats.Endings == nil {
+		stats.Endings = make(map[string]int)
+	}
+	if stats.Ports == nil {
+		stats.Ports = make(map[int]int)
+	}
+	return stats, nil
+}
+
+func saveLookupStats(stats lookupStats) error {
+	path, err := configFile("lookup-stats.json")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(stats, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+func resetLookupStats() error {
+	path, err := configFile("lookup-stats.json")
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+func (s *lookupStats) Record(matches []ping.LookupMatch) {
+	s.Lookups++
+	s.Matches += len(matches)
+	s.UpdatedAt = time.Now()
+	for _, match := range matches {
+		if match.Ending != "" {
+			s.Subdomains[normalizeStatsSubdomain(match.Subdomain)]++
+			s.Endings[normalizeStatsEnding(match.Ending)]++
+		}
+		s.Ports[match.Port]++
+	}
+}
+
+func (s lookupStats) Empty() bool {
+	return s.Matches == 0
+}
+
+func (s lookupStats) OrderSubdomains(values []string) []string {
+	ordered := append([]string(nil), values...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return s.Subdomains[normalizeStatsSubdomain(ordered[i])] > s.Subdomains[normalizeStatsSubdomain(ordered[j])]
+	})
+	return ordered
+}
+
+func (s lookupStats) OrderEndings(values []string) []string {
+	ordered := append([]string(nil), values...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return s.Endings[normalizeStatsEnding(ordered[i])] > s.Endings[normalizeStatsEnding(ordered[j])]
+	})
+	return ordered
+}
+
+func (s lookupStats) OrderPorts(values []int) []int {
+	ordered := append([]int(nil), values...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return s.Ports[ordered[i]] > s.Ports[ordered[j]]
+	})
+	return ordered
+}
+
+func (s lookupStats) OrderTargets(values []ping.LookupTarget) []ping.LookupTarget {
+	ordered := append([]ping.LookupTarget(nil), values...)
+	score := func(target ping.LookupTarget) int {
+		return s.Subdomains[normalizeStatsSubdomain(target.Subdomain)] + s.Endings[normalizeStatsEnding(target.Ending)]
+	}
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return score(ordered[i]) > score(ordered[j])
+	})
+	return ordered
+}
+
+func normalizeStatsSubdomain(value string) string {
+	return strings.TrimSpace(strings.ToLower(value))
+}
+
+func normalizeStatsEnding(value string) string {
+	return strings.TrimPrefix(strings.TrimSpace(strings.ToLower(value)), ".")
+}
+
+func (a *App) applyLearnedLookupOrder(config LookupConfig) (LookupConfig, string) {
+	if !a.settings.LearnLookupOrder {
+		return config, "declaration order"
+	}
+	stats, err := loadLookupStats()
+	if err != nil || stats.Empty() {
+		return config, "declaration order (no learned hits yet)"
+	}
+	config.Subdomains = stats.OrderSubdomains(config.Subdomains)
+	config.Endings = stats.OrderEndings(config.Endings)
+	config.Ports = stats.OrderPorts(config.Ports)
+	config.Imported = stats.OrderTargets(config.Imported)
+	return config, fmt.Sprintf("learned from %d hits over %d lookups", stats.Matches, stats.Lookups)
+}
+
+func (a *App) recordLookupStats(matches []ping.LookupMatch) error {
+	if !a.settings.LearnLookupOrder {
+		return nil
+	}
+	stats, err := loadLookupStats()
+	if err != nil {
+		return err
+	}
+	stats.Record(matches)
+	return saveLookupStats(stats)
+}
+
+func formatLookupStats(stats lookupStats) string {
+	if stats.Empty() {
+		return "No lookup hits recorded yet. Matches from future lookups are counted here and used to probe likely names first."
+	}
+	var builder strings.Builder
+	builder.WriteString("Summary\n")
+	builder.WriteString(fmt.Sprintf("- Lookups recorded: %d\n", stats.Lookups))
+	builder.WriteString(fmt.Sprintf("- Matches recorded: %d\n", stats.Matches))
+	if !stats.UpdatedAt.IsZero() {
+		builder.WriteString(fmt.Sprintf("- Last update: %s\n", stats.UpdatedAt.Format("2006-01-02 15:04")))
+	}
+	writeSection := func(title string, counts map[string]int) {
+		builder.WriteString("\n")
+		builder.WriteString(title + "\n")
+		for _, entry := range topLookupStats(counts, lookupStatsTopEntries) {
+			builder.WriteString(fmt.Sprintf("- %s: %d\n", entry.name, entry.hits))
+		}
+	}
+	subdomains := make(map[string]int, len(stats.Subdomains))
+	for name, hits := range stats.Subdomains {
+		if name == "" {
+			name = "(root)"
+		}
+		subdomains[name] += hits
+	}
+	ports := make(map[string]int, len(stats.Ports))
+	for port, hits := range stats.Ports {
+		ports[strconv.Itoa(port)] = hits
+	}
+	writeSection("Subdomains", subdomains)
+	writeSection("Endings", stats.Endings)
+	writeSection("Ports", ports)
+	return strings.TrimRight(builder.String(), "\n")
+}
+
+type lookupStatsEntry struct {
+	name string
+	hits int
+}
+
+func topLookupStats(counts map[string]int, limit int) []lookupStatsEntry {
+	entries := make([]lookupStatsEntry, 0, len(counts))
+	for name, hits := range counts {
+		if hits > 0 {
+			entries = append(entries, lookupStatsEntry{name: name, hits: hits})
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].hits != entries[j].hits {
+			return entries[i].hits > entries[j].hits
+		}
+		return entries[i].name < entries[j].name
+	})
+	if len(entries) > limit {
+		entries = entries[:limit]
+	}
+	return entries
+}
@@ -0,0 +1,42 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+
+	"UWP-TCP-Con/internal/ping"
+)
+
+func TestLookupStatsOrdersMostLikelyFirst(t *testing.T) {
+	stats := newLookupStats()
+	stats.Record([]ping.LookupMatch{
+		{Host: "mc.example.net", Port: 19133, Subdomain: "mc", Ending: "net"},
+		{Host: "mc.example.de", Port: 19132, Subdomain: "mc", Ending: "de"},
+		{Host: "example.net", Port: 19133, Subdomain: "", Ending: "net"},
+	})
+
+	subdomains := stats.OrderSubdomains([]string{"play", "", "MC", "hub"})
+	if strings.Join(subdomains, ",") != "MC,,play,hub" {
+		t.Fatalf("unexpected subdomain order: %q", subdomains)
+	}
+	endings := stats.OrderEndings([]string{"com", "de", ".net"})
+	if strings.Join(endings, ",") != ".net,de,com" {
+		t.Fatalf("unexpected ending order: %q", endings)
+	}
+	ports := stats.OrderPorts([]int{19132, 19133, 19134})
+	if ports[0] != 19133 || ports[1] != 19132 || ports[2] != 19134 {
+		t.Fatalf("unexpected port order: %v", ports)
+	}
+}
+
+func TestLookupStatsKeepsDeclarationOrderWithoutHits(t *testing.T) {
+	stats := newLookupStats()
+	values := []string{"play", "mc", "hub"}
+	ordered := stats.OrderSubdomains(values)
+	if strings.Join(ordered, ",") != "play,mc,hub" {
+		t.Fatalf("expected declaration order, got %q", ordered)
+	}
+	if stats.Lookups != 0 || !stats.Empty() {
+		t.Fatal("expected empty statistics")
+	}
+}
@@ -5,220 +5,197 @@ import (
 	"strings"
 
 	"UWP-TCP-Con/internal/ping"
+	"UWP-TCP-Con/internal/web"
 )
 
-type lookupSort string
-type lookupFilter string
-
-const (
-	lookupSortFound       lookupSort = "found"
-	lookupSortHost        lookupSort = "host"
-	lookupSortPlayersDesc lookupSort = "players_desc"
-	lookupSortLatencyAsc  lookupSort = "latency_asc"
-	lookupSortVersion     lookupSort = "version"
-
-	lookupFilterAll         lookupFilter = "all"
-	lookupFilterWithPlayers lookupFilter = "with_players"
-	lookupFilterEmpty       lookupFilter = "empty"
-	lookupFilterWithMOTD    lookupFilter = "with_motd"
-	lookupFilterWithIcon    lookupFilter = "with_icon"
-)
+type viewPreset struct {
+	Label      string
+	Expression string
+}
 
-func (a *App) askLookupSort() (lookupSort, error) {
-	options := []string{
-		"Found order: Keep discovery order",
-		"Host A-Z: Group by hostname",
-		"Players: High to low",
-		"Latency: Low to high",
-		"Version: A-Z",
-	}
-	index, err := selectOption("Lookup sort", options)
+var lookupFilterPresets = []viewPreset{
+	{"All matches: No filter", ""},
+	{"With players: Online count above zero", "players > 0"},
+	{"Empty servers: Online count is zero", "players == 0"},
+	{"With MOTD: Description present", `motd != ""`},
+	{"Java icon: Java servers with icon", "icon"},
+}
+
+var lookupSortPresets = []viewPreset{
+	{"Found order: Keep discovery order", ""},
+	{"Host A-Z: Group by hostname", "host, port"},
+	{"Players: High to low", "players desc, host, port"},
+	{"Latency: Low to high", "latency, host, port"},
+	{"Version: A-Z", "version, host, port"},
+}
+
+func (a *App) askLookupSort() (resultSort, error) {
+	source, err := askViewExpression("Lookup sort", lookupSortPresets, "sort", "Sort keys: e.g. players desc, latency, host", validateSortExpression)
 	if err != nil {
-		return lookupSortFound, err
-	}
-	switch index {
-	case 1:
-		return lookupSortHost, nil
-	case 2:
-		return lookupSortPlayersDesc, nil
-	case 3:
-		return lookupSortLatencyAsc, nil
-	case 4:
-		return lookupSortVersion, nil
-	default:
-		return lookupSortFound, nil
+		return nil, err
 	}
+	return parseResultSort(source)
 }
 
-func (a *App) askLookupFilter() (lookupFilter, error) {
-	options := []string{
-		"All matches: No filter",
-		"With players: Online count above zero",
-		"Empty servers: Online count is zero",
-		"With MOTD: Description present",
-		"Java icon: Java servers with icon",
-	}
-	index, err := selectOption("Lookup filter", options)
+func (a *App) askLookupFilter() (*resultFilter, error) {
+	source, err := askViewExpression("Lookup filter", lookupFilterPresets, "filter", `Expression: e.g. players > 10 && version ~ "1.20"`, validateFilterExpression)
 	if err != nil {
-		return lookupFilterAll, err
-	}
-	switch index {
-	case 1:
-		return lookupFilterWithPlayers, nil
-	case 2:
-		return lookupFilterEmpty, nil
-	case 3:
-		return lookupFilterWithMOTD, nil
-	case 4:
-		return lookupFilterWithIcon, nil
-	default:
-		return lookupFilterAll, nil
+		return nil, err
 	}
+	return parseResultFilter(source)
 }
 
-func lookupSortLabel(value lookupSort) string {
-	switch value {
-	case lookupSortHost:
-		return "Host A-Z"
-	case lookupSortPlayersDesc:
-		return "Players high to low"
-	case lookupSortLatencyAsc:
-		return "Latency low to high"
-	case lookupSortVersion:
-		return "Version A-Z"
-	default:
-		return "Found order"
+func askViewExpression(title string, presets []viewPreset, kind, hint string, validate func(string) error) (string, error) {
+	saved, err := loadLookupPresets()
+	if err != nil {
+		return "", err
+	}
+	savedList := saved.Sorts
+	if kind == "filter" {
+		savedList = saved.Filters
+	}
+	options := make([]string, 0, len(presets)+len(savedList)+1)
+	for _, preset := range presets {
+		options = append(options, preset.Label)
+	}
+	for _, expression := range savedList {
+		options = append(options, "Saved: "+expression)
+	}
+	options = append(options, "Custom: Type a "+kind)
+	index, err := selectOption(title, options)
+	if err != nil {
+		return "", err
+	}
+	switch {
+	case index < len(presets):
+		return presets[index].Expression, nil
+	case index < len(presets)+len(savedList):
+		return savedList[index-len(presets)], nil
 	}
-}
 
-func lookupFilterLabel(value lookupFilter) string {
-	switch value {
-	case lookupFilterWithPlayers:
-		return "With players"
-	case lookupFilterEmpty:
-		return "Empty servers"
-	case lookupFilterWithMOTD:
-		return "With MOTD"
-	case lookupFilterWithIcon:
-		return "Java icon"
-	default:
-		return "All matches"
+	value, err := promptViewExpression(title, hint, validate)
+	if err != nil || value == "" {
+		return value, err
+	}
+	save, err := askConfirm("Save this " + kind + " to the lookup presets?")
+	if err != nil || !save {
+		return value, err
 	}
+	if kind == "filter" {
+		saved.Filters = mergeUniqueStrings(saved.Filters, []string{value})
+	} else {
+		saved.Sorts = mergeUniqueStrings(saved.Sorts, []string{value})
+	}
+	return value, saveLookupPresets(saved)
 }
 
-func applyLookupView(matches []ping.LookupMatch, sortMode lookupSort, filterMode lookupFilter) []ping.LookupMatch {
-	filtered := make([]ping.LookupMatch, 0, len(matches))
-	for _, match := range matches {
-		if lookupMatchPasses(match, filterMode) {
-			filtered = append(filtered, match)
+func promptViewExpression(title, hint string, validate func(string) error) (string, error) {
+	var errMsg string
+	for {
+		value, err := promptInput(title, hint, errMsg)
+		if err != nil {
+			return "", err
+		}
+		value = strings.TrimSpace(value)
+		if err := validate(value); err != nil {
+			errMsg = err.Error()
+			continue
 		}
+		return value, nil
 	}
+}
 
-	switch sortMode {
-	case lookupSortHost:
-		sort.SliceStable(filtered, func(i, j int) bool {
-			iHost := strings.ToLower(filtered[i].Host)
-			jHost := strings.ToLower(filtered[j].Host)
-			if iHost == jHost {
-				return filtered[i].Port < filtered[j].Port
-			}
-			return iHost < jHost
-		})
-	case lookupSortPlayersDesc:
-		sort.SliceStable(filtered, func(i, j int) bool {
-			iOnline, _ := lookupPlayerCounts(filtered[i].Result)
-			jOnline, _ := lookupPlayerCounts(filtered[j].Result)
-			if iOnline == jOnline {
-				return compareLookupHostPort(filtered[i], filtered[j])
-			}
-			return iOnline > jOnline
-		})
-	case lookupSortLatencyAsc:
-		sort.SliceStable(filtered, func(i, j int) bool {
-			iLatency := lookupLatency(filtered[i].Result)
-			jLatency := lookupLatency(filtered[j].Result)
-			if iLatency == jLatency {
-				return compareLookupHostPort(filtered[i], filtered[j])
-			}
-			return iLatency < jLatency
-		})
-	case lookupSortVersion:
-		sort.SliceStable(filtered, func(i, j int) bool {
-			iVersion := strings.ToLower(lookupVersion(filtered[i].Result))
-			jVersion := strings.ToLower(lookupVersion(filtered[j].Result))
-			if iVersion == jVersion {
-				return compareLookupHostPort(filtered[i], filtered[j])
-			}
-			return iVersion < jVersion
-		})
-	}
-	return filtered
+func validateFilterExpression(value string) error {
+	_, err := parseResultFilter(value)
+	return err
 }
 
-func compareLookupHostPort(left, right ping.LookupMatch) bool {
-	leftHost := strings.ToLower(left.Host)
-	rightHost := strings.ToLower(right.Host)
-	if leftHost == rightHost {
-		return left.Port < right.Port
-	}
-	return leftHost < rightHost
+func validateSortExpression(value string) error {
+	_, err := parseResultSort(value)
+	return err
 }
 
-func lookupMatchPasses(match ping.LookupMatch, filterMode lookupFilter) bool {
-	online, _ := lookupPlayerCounts(match.Result)
-	switch filterMode {
-	case lookupFilterWithPlayers:
-		return online > 0
-	case lookupFilterEmpty:
-		return online == 0
-	case lookupFilterWithMOTD:
-		return strings.TrimSpace(lookupMOTD(match.Result)) != ""
-	case lookupFilterWithIcon:
-		status, ok := match.Result.(ping.JavaStatus)
-		return ok && len(status.IconPNG) > 0
-	default:
-		return true
+func lookupSortLabel(value resultSort) string {
+	if len(value) == 0 {
+		return "Found order"
 	}
+	return value.String()
 }
 
-func lookupPlayerCounts(result ping.Result) (int, int) {
-	switch value := result.(type) {
-	case ping.BedrockPong:
-		return parseCount(value.CurrentPlayers), parseCount(value.MaxPlayers)
-	case ping.JavaStatus:
-		return value.CurrentPlayers, value.MaxPlayers
-	default:
-		return 0, 0
+func lookupFilterLabel(value *resultFilter) string {
+	if value == nil {
+		return "All matches"
 	}
+	return value.String()
+}
+
+func lookupMatchRecord(mode string, edition ping.Edition, match ping.LookupMatch) exportRecord {
+	record := newExportRecord(mode, edition, match.Host, match.Port, match.Result, match.Detail, nil, nil)
+	record.Source = match.Source
+	return record
 }
 
-func lookupLatency(result ping.Result) int64 {
-	switch value := result.(type) {
-	case ping.JavaStatus:
-		return value.LatencyMillis
-	default:
-		return 1<<62 - 1
+func lookupMatchFilter(mode string, edition ping.Edition, filter *resultFilter) func(ping.LookupMatch) bool {
+	if filter == nil {
+		return nil
+	}
+	return func(match ping.LookupMatch) bool {
+		return filter.Match(lookupMatchRecord(mode, edition, match))
 	}
 }
 
-func lookupVersion(result ping.Result) string {
-	switch value := result.(type) {
-	case ping.BedrockPong:
-		return value.GameVersion
-	case ping.JavaStatus:
-		return value.VersionName
-	default:
-		return ""
+func sortLookupMatches(matches []ping.LookupMatch, mode string, edition ping.Edition, keys resultSort) {
+	if len(keys) == 0 {
+		return
+	}
+	type sortable struct {
+		match  ping.LookupMatch
+		record exportRecord
+	}
+	items := make([]sortable, len(matches))
+	for i, match := range matches {
+		items[i] = sortable{match, lookupMatchRecord(mode, edition, match)}
+	}
+	sort.SliceStable(items, func(i, j int) bool { return keys.Less(items[i].record, items[j].record) })
+	for i := range items {
+		matches[i] = items[i].match
 	}
 }
 
-func lookupMOTD(result ping.Result) string {
-	switch value := result.(type) {
-	case ping.BedrockPong:
-		return value.CleanMOTD
-	case ping.JavaStatus:
-		return value.CleanMOTD
-	default:
-		return ""
+func viewLookupMatches(result ping.LookupResult, links []web.LookupLinkURLs, mode string, edition ping.Edition, filter *resultFilter, keys resultSort) (ping.LookupResult, []web.LookupLinkURLs) {
+	type viewItem struct {
+		match  ping.LookupMatch
+		link   *web.LookupLinkURLs
+		record exportRecord
+	}
+	items := make([]viewItem, 0, len(result.Matches))
+	for i, match := range result.Matches {
+		record := lookupMatchRecord(mode, edition, match)
+		if !filter.Match(record) {
+			continue
+		}
+		item := viewItem{match: match, record: record}
+		if i < len(links) {
+			item.link = &links[i]
+		}
+		items = append(items, item)
+	}
+	if len(keys) > 0 {
+		sort.SliceStable(items, func(i, j int) bool { return keys.Less(items[i].record, items[j].record) })
+	}
+
+	view := result
+	view.Rejected += len(result.Matches) - len(items)
+	view.Matches = make([]ping.LookupMatch, 0, len(items))
+	viewLinks := make([]web.LookupLinkURLs, 0, len(items))
+	for _, item := range items {
+		view.Matches = append(view.Matches, item.match)
+		if item.link != nil {
+			viewLinks = append(viewLinks, *item.link)
+		}
+	}
+	if len(viewLinks) != len(view.Matches) {
+		viewLinks = nil
 	}
+	return view, viewLinks
 }
@@ -0,0 +1,457 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"UWP-TCP-Con/internal/alert"
+	"UWP-TCP-Con/internal/history"
+	"UWP-TCP-Con/internal/ping"
+)
+
+const (
+	defaultMonitorIntervalSeconds = 60
+	monitorCompactInterval        = time.Hour
+	historySparklineWidth         = 48
+	monitorAlertTimeout           = 30 * time.Second
+)
+
+type monitorTarget struct {
+	Name            string       `json:"name"`
+	Edition         ping.Edition `json:"edition"`
+	Host            string       `json:"host"`
+	Port            int          `json:"port"`
+	IntervalSeconds int          `json:"interval_seconds,omitempty"`
+}
+
+type monitorRetention struct {
+	RawHours      int `json:"raw_hours"`
+	KeepDays      int `json:"keep_days"`
+	BucketMinutes int `json:"bucket_minutes"`
+}
+
+type monitorConfig struct {
+	Targets   []monitorTarget  `json:"targets"`
+	Retention monitorRetention `json:"retention"`
+}
+
+func defaultMonitorConfig() monitorConfig {
+	return monitorConfig{
+		Targets: []monitorTarget{},
+		Retention: monitorRetention{
+			RawHours:      48,
+			KeepDays:      90,
+			BucketMinutes: 15,
+		},
+	}
+}
+
+func monitorConfigPath() (string, error) {
+	return configFile("monitor.json")
+}
+
+func loadMonitorConfig(path string) (monitorConfig, error) {
+	if path == "" {
+		defaultPath, err := monitorConfigPath()
+		if err != nil {
+			return defaultMonitorConfig(), err
+		}
+		path = defaultPath
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return defaultMonitorConfig(), nil
+		}
+		return defaultMonitorConfig(), err
+	}
+	config := defaultMonitorConfig()
+	if err := json.Unmarshal(data, &config); err != nil {
+		return defaultMonitorConfig(), err
+	}
+	return config, nil
+}
+
+func saveMonitorConfig(config monitorConfig) error {
+	path, err := monitorConfigPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+func (r monitorRetention) Policy() history.Retention {
+	policy := history.DefaultRetention()
+	if r.RawHours > 0 {
+		policy.Raw = time.Duration(r.RawHours) * time.Hour
+	}
+	if r.KeepDays > 0 {
+		policy.Keep = time.Duration(r.KeepDays) * 24 * time.Hour
+	}
+	if r.BucketMinutes > 0 {
+		policy.Bucket = time.Duration(r.BucketMinutes) * time.Minute
+	}
+	return policy
+}
+
+func (t monitorTarget) Key() string {
+	return history.Key(string(t.Edition), t.Host, t.Port)
+}
+
+func (t monitorTarget) Interval() time.Duration {
+	if t.IntervalSeconds <= 0 {
+		return defaultMonitorIntervalSeconds * time.Second
+	}
+	return time.Duration(t.IntervalSeconds) * time.Second
+}
+
+func (t monitorTarget) Validate() error {
+	if strings.TrimSpace(t.Host) == "" {
+		return fmt.Errorf("monitor target %q has no host", t.Name)
+	}
+	if t.Edition != ping.EditionBedrock && t.Edition != ping.EditionJava {
+		return fmt.Errorf("monitor target %q has an invalid edition", t.Name)
+	}
+	if t.Port < 1 || t.Port > 65535 {
+		return fmt.Errorf("monitor target %q has an invalid port", t.Name)
+	}
+	return nil
+}
+
+func openHistoryStore() (*history.Store, error) {
+	dir, err := configDir()
+	if err != nil {
+		return nil, err
+	}
+	return history.Open(filepath.Join(dir, "history"))
+}
+
+func historySample(sample watchSample) history.Sample {
+	entry := history.Sample{Time: sample.At, Count: 1}
+	if !sample.Up {
+		entry.Failure = string(ping.ClassifyError(sample.Err))
+		return entry
+	}
+	entry.UpCount = 1
+	entry.Players = sample.Players
+	entry.MaxPlayers = sample.Max
+	entry.Latency = sample.Latency
+	entry.Version = sample.Version
+	entry.MOTDHash = history.HashMOTD(sample.MOTD)
+	return entry
+}
+
+func (a *App) runMonitor(ctx context.Context, config monitorConfig, store *history.Store, alerts *alert.Engine, out io.Writer, once bool) error {
+	if len(config.Targets) == 0 {
+		return fmt.Errorf("no monitor targets configured; add some in the Monitoring menu or monitor.json")
+	}
+	targets := make([]monitorTarget, 0, len(config.Targets))
+	seen := make(map[string]struct{}, len(config.Targets))
+	for _, target := range config.Targets {
+		if err := target.Validate(); err != nil {
+			return err
+		}
+		if _, ok := seen[target.Key()]; ok {
+			continue
+		}
+		seen[target.Key()] = struct{}{}
+		targets = append(targets, target)
+	}
+
+	policy := config.Retention.Policy()
+	compact := func() {
+		for _, target := range targets {
+			if err := store.Compact(target.Key(), policy, time.Now()); err != nil {
+				fmt.Fprintf(out, "%s compact %s: %v\n", time.Now().Format(time.RFC3339), target.Name, err)
+			}
+		}
+	}
+	compact()
+
+	var outMu sync.Mutex
+	var dispatches sync.WaitGroup
+	defer dispatches.Wait()
+	dispatch := func(fired alert.Alert) {
+		defer dispatches.Done()
+		sendCtx, cancel := context.WithTimeout(context.Background(), monitorAlertTimeout)
+		defer cancel()
+		if err := alerts.Dispatch(sendCtx, fired); err != nil {
+			outMu.Lock()
+			fmt.Fprintf(out, "%s alert %s: %v\n", time.Now().Format(time.RFC3339), fired.Rule, err)
+			outMu.Unlock()
+		}
+	}
+	probe := func(target monitorTarget) {
+		sample := a.watchProbe(ctx, watchTarget{Name: target.Name, Edition: target.Edition, Host: target.Host, Port: target.Port})
+		if ctx.Err() != nil {
+			return
+		}
+		entry := historySample(sample)
+		err := store.Append(target.Key(), entry)
+		outMu.Lock()
+		defer outMu.Unlock()
+		fmt.Fprintln(out, formatMonitorLine(target, sample))
+		if err != nil {
+			fmt.Fprintf(out, "%s store %s: %v\n", sample.At.Format(time.RFC3339), target.Name, err)
+		}
+		if alerts == nil {
+			return
+		}
+		for _, fired := range alerts.Observe(alertObservation(target, sample)) {
+			fmt.Fprintf(out, "%s alert %s: %s\n", fired.Time.Format(time.RFC3339), fired.Title, fired.Message)
+			dispatches.Add(1)
+			go dispatch(fired)
+		}
+	}
+
+	if once {
+		var wg sync.WaitGroup
+		for _, target := range targets {
+			wg.Add(1)
+			go func(target monitorTarget) {
+				defer wg.Done()
+				probe(target)
+			}(target)
+		}
+		wg.Wait()
+		return nil
+	}
+
+	var wg sync.WaitGroup
+	for _, target := range targets {
+		wg.Add(1)
+		go func(target monitorTarget) {
+			defer wg.Done()
+			ticker := time.NewTicker(target.Interval())
+			defer ticker.Stop()
+			for {
+				probe(target)
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+				}
+			}
+		}(target)
+	}
+	compactTicker := time.NewTicker(monitorCompactInterval)
+	defer compactTicker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			wg.Wait()
+			return nil
+		case <-compactTicker.C:
+			compact()
+		}
+	}
+}
+
+func formatMonitorLine(target monitorTarget, sample watchSample) string {
+	timestamp := sample.At.Format(time.RFC3339)
+	if !sample.Up {
+		return fmt.Sprintf("%s %s down %s", timestamp, target.Name, ping.ClassifyError(sample.Err).Label())
+	}
+	return fmt.Sprintf("%s %s up %d/%d %dms %s", timestamp, target.Name, sample.Players, sample.Max, sample.Latency.Milliseconds(), sample.Version)
+}
+
+func monitorTargetNames(config monitorConfig) map[string]string {
+	names := make(map[string]string, len(config.Targets))
+	for _, target := range config.Targets {
+		names[target.Key()] = target.Name
+	}
+	return names
+}
+
+func formatHistoryReport(name string, samples []history.Sample, now time.Time) string {
+	var builder strings.Builder
+	if len(samples) == 0 {
+		return fmt.Sprintf("No samples recorded for %s yet.", name)
+	}
+	day := history.Summarize(samplesSince(samples, now.Add(-24*time.Hour)))
+	week := history.Summarize(samplesSince(samples, now.Add(-7*24*time.Hour)))
+	all := history.Summarize(samples)
+
+	state := "down"
+	if all.LastUp {
+		state = "up"
+	}
+	builder.WriteString("Summary\n")
+	builder.WriteString(fmt.Sprintf("- Server: %s\n", name))
+	builder.WriteString(fmt.Sprintf("- Last sample: %s (%s)\n", all.LastAt.Format("2006-01-02 15:04:05"), state))
+	builder.WriteString(fmt.Sprintf("- Recorded since: %s\n", all.FirstAt.Format("2006-01-02 15:04")))
+	builder.WriteString(fmt.Sprintf("- Uptime 24h: %s\n", formatUptime(day)))
+	builder.WriteString(fmt.Sprintf("- Uptime 7d: %s\n", formatUptime(week)))
+	builder.WriteString(fmt.Sprintf("- Uptime all: %s\n", formatUptime(all)))
+	if all.AvgLatency > 0 {
+		builder.WriteString(fmt.Sprintf("- Average latency: %s\n", all.AvgLatency.Round(time.Millisecond)))
+	}
+	if all.Version != "" {
+		builder.WriteString(fmt.Sprintf("- Version: %s\n", all.Version))
+	}
+	builder.WriteString(fmt.Sprintf("- MOTD changes: %d\n", all.MOTDChanges))
+
+	builder.WriteString("\nPlayers\n")
+	builder.WriteString(fmt.Sprintf("- Last 24h: %s\n", sparkline(history.PlayerSeries(samples, now.Add(-24*time.Hour), now, historySparklineWidth))))
+	builder.WriteString(fmt.Sprintf("- Last 7d: %s\n", sparkline(history.PlayerSeries(samples, now.Add(-7*24*time.Hour), now, historySparklineWidth))))
+	if !all.PeakAt.IsZero() {
+		builder.WriteString(fmt.Sprintf("- Peak: %d at %s\n", all.PeakPlayers, all.PeakAt.Format("2006-01-02 15:04")))
+	}
+
+	builder.WriteString("\nVersions\n")
+	if len(all.Versions) == 0 {
+		builder.WriteString("- No version changes recorded\n")
+	}
+	for _, change := range all.Versions {
+		builder.WriteString(fmt.Sprintf("- %s: %s -> %s\n", change.At.Format("2006-01-02 15:04"), change.From, change.To))
+	}
+	return strings.TrimRight(builder.String(), "\n")
+}
+
+func formatUptime(summary history.Summary) string {
+	if summary.Checks == 0 {
+		return "no samples"
+	}
+	return fmt.Sprintf("%.2f%% (%d/%d checks)", summary.Uptime, summary.Up, summary.Checks)
+}
+
+func samplesSince(samples []history.Sample, since time.Time) []history.Sample {
+	for i, sample := range samples {
+		if !sample.Time.Before(since) {
+			return samples[i:]
+		}
+	}
+	return nil
+}
+
+func (a *App) manageMonitoring() error {
+	for {
+		config, err := loadMonitorConfig("")
+		if err != nil {
+			return err
+		}
+		options := []string{
+			"View history: Uptime, players and versions",
+			fmt.Sprintf("Add target: %d monitored", len(config.Targets)),
+			"Add favorites: Monitor every saved profile",
+			"Remove target: Stop monitoring a server",
+			"Back",
+		}
+		index, err := selectOption("Monitoring", options)
+		if err != nil {
+			return err
+		}
+		switch index {
+		case 0:
+			if err := a.showHistory(config); err != nil {
+				return err
+			}
+			continue
+		case 1:
+			direct, err := a.collectDirectConfig()
+			if err != nil {
+				return err
+			}
+			interval, err := askIntValue("Probe interval (seconds)", defaultMonitorIntervalSeconds)
+			if err != nil {
+				return err
+			}
+			config.Targets = append(config.Targets, monitorTarget{
+				Name:            fmt.Sprintf("%s:%d", direct.Host, direct.Port),
+				Edition:         direct.Edition,
+				Host:            direct.Host,
+				Port:            direct.Port,
+				IntervalSeconds: interval,
+			})
+		case 2:
+			favorites, err := loadFavorites()
+			if err != nil {
+				return err
+			}
+			known := monitorTargetNames(config)
+			for _, fav := range favorites {
+				target := monitorTarget{Name: fav.Name, Edition: fav.Edition, Host: fav.Host, Port: fav.Port}
+				if _, ok := known[target.Key()]; ok {
+					continue
+				}
+				config.Targets = append(config.Targets, target)
+			}
+		case 3:
+			if len(config.Targets) == 0 {
+				continue
+			}
+			labels := make([]string, 0, len(config.Targets)+1)
+			for _, target := range config.Targets {
+				labels = append(labels, fmt.Sprintf("%s: %s %s:%d", target.Name, target.Edition, target.Host, target.Port))
+			}
+			labels = append(labels, "Back")
+			choice, err := selectOption("Remove target", labels)
+			if err != nil {
+				return err
+			}
+			if choice >= len(config.Targets) {
+				continue
+			}
+			config.Targets = append(config.Targets[:choice], config.Targets[choice+1:]...)
+		default:
+			return nil
+		}
+		if err := saveMonitorConfig(config); err != nil {
+			return err
+		}
+	}
+}
+
+func (a *App) showHistory(config monitorConfig) error {
+	store, err := openHistoryStore()
+	if err != nil {
+		return err
+	}
+	keys, err := store.Keys()
+	if err != nil {
+		return err
+	}
+	if len(keys) == 0 {
+		return renderTextPageAndWait("History", "No history recorded yet. Run `uwp-tcp-con monitor` to start collecting samples.")
+	}
+	names := monitorTargetNames(config)
+	labels := make([]string, 0, len(keys)+1)
+	for _, key := range keys {
+		label := key
+		if name, ok := names[key]; ok {
+			label = fmt.Sprintf("%s: %s", name, key)
+		}
+		labels = append(labels, label)
+	}
+	labels = append(labels, "Back")
+	index, err := selectOption("History", labels)
+	if err != nil {
+		return err
+	}
+	if index >= len(keys) {
+		return nil
+	}
+	samples, err := store.Read(keys[index], time.Time{})
+	if err != nil {
+		return err
+	}
+	name := keys[index]
+	if label, ok := names[name]; ok {
+		name = label
+	}
+	return renderTextPageAndWait("History", formatHistoryReport(name, samples, time.Now()))
+}
@@ -0,0 +1,522 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"UWP-TCP-Con/internal/ping"
+	"UWP-TCP-Con/internal/web"
+)
+
+const reverseDNSWorkers = 16
+
+type networkScanConfig struct {
+	Edition   ping.Edition
+	Targets   []string
+	Addresses []string
+	Ports     []int
+	Filter    *resultFilter
+}
+
+type networkScanMetrics struct {
+	Targets   []string
+	Addresses int
+	Ports     int
+	Lookup    lookupMetrics
+}
+
+func (a *App) executeNetworkScan() error {
+	edition, err := a.askEdition()
+	if err != nil {
+		return err
+	}
+	targets, addresses, err := a.askNetworkScanTargets()
+	if err != nil {
+		return err
+	}
+	ports, err := a.askNetworkScanPorts(edition)
+	if err != nil {
+		return err
+	}
+	filter, err := a.askLookupFilter()
+	if err != nil {
+		return err
+	}
+	return a.runNetworkScan(networkScanConfig{
+		Edition:   edition,
+		Targets:   targets,
+		Addresses: addresses,
+		Ports:     ports,
+		Filter:    filter,
+	})
+}
+
+func (a *App) askNetworkScanTargets() ([]string, []string, error) {
+	var errMsg string
+	for {
+		value, err := promptInput("Network targets", "CIDRs, ranges or IPs, e.g. 203.0.113.0/24 198.51.100.10-40 2001:db8::/64[::1,::10]. A file path works too.", errMsg)
+		if err != nil {
+			return nil, nil, err
+		}
+		value = strings.TrimSpace(value)
+		if value == "" {
+			errMsg = "Targets cannot be empty"
+			continue
+		}
+		if info, statErr := os.Stat(value); statErr == nil && !info.IsDir() {
+			data, err := os.ReadFile(value)
+			if err != nil {
+				errMsg = friendlyErrorMessage(err)
+				continue
+			}
+			value = string(data)
+		}
+		targets := splitNetworkTargets(value)
+		addresses, err := expandNetworkTargets(targets, a.settings.ScanMaxAddresses)
+		if err != nil {
+			errMsg = err.Error()
+			continue
+		}
+		return targets, addresses, nil
+	}
+}
+
+func (a *App) askNetworkScanPorts(edition ping.Edition) ([]int, error) {
+	common := commonJavaPorts
+	if edition == ping.EditionBedrock {
+		common = commonBedrockPorts
+	}
+	defaultPort := ping.DefaultPort(edition)
+	index, err := selectOption("Port profile", []string{
+		fmt.Sprintf("Common ports: %s", portListText(common)),
+		fmt.Sprintf("Default port: %d", defaultPort),
+		"Custom ports: Enter your own list or ranges",
+	})
+	if err != nil {
+		return nil, err
+	}
+	switch index {
+	case 0:
+		return append([]int(nil), common...), nil
+	case 1:
+		return []int{defaultPort}, nil
+	}
+	var errMsg string
+	for {
+		value, err := promptInput("Ports", "Comma or space separated, ranges allowed, e.g. 25565 25570-25580", errMsg)
+		if err != nil {
+			return nil, err
+		}
+		ports, err := parsePortList(value)
+		if err != nil {
+			errMsg = err.Error()
+			continue
+		}
+		return ports, nil
+	}
+}
+
+func (a *App) runNetworkScan(config networkScanConfig) error {
+	total := len(config.Addresses) * len(config.Ports)
+	progressView := newLookupProgressViewForTotal(a.settings, config.Edition, total)
+	startedAt := time.Now()
+
+	resultText, err := withControlledSpinner("Network scan", func(frame int, control *spinnerControl) string {
+		status := progressView.Render(frame)
+		if control.IsPaused() {
+			status = "Status: paused\n" + status
+		}
+		if control.IsCancelled() {
+			status = "Status: aborting\n" + status
+		}
+		return status
+	}, 120*time.Millisecond, func(control *spinnerControl) (string, error) {
+		result, scanErr := ping.LookupDomains(control.Context(), ping.LookupConfig{
+			Edition:     config.Edition,
+			Port:        ping.DefaultPort(config.Edition),
+			Ports:       config.Ports,
+			Targets:     networkScanTargets(config.Addresses),
+			Concurrency: a.settings.LookupConcurrency,
+			Adaptive:    a.settings.AdaptiveConcurrency,
+			RateLimit:   a.settings.LookupRateLimit,
+			Options: ping.ExecuteOptions{
+				Timeout:    a.settings.RequestTimeout(),
+				RetryCount: a.settings.RetryCount,
+				RetryDelay: a.settings.RetryDelay(),
+				IPMode:     a.settings.IPMode,
+			},
+			Progress: func(progress ping.LookupProgress) {
+				progressView.Observe(progress)
+			},
+			Accept: lookupMatchFilter("network_scan", config.Edition, config.Filter),
+			Paused: control.IsPaused,
+		})
+		if scanErr != nil && !errors.Is(scanErr, context.Canceled) {
+			return "", scanErr
+		}
+		sortNetworkScanMatches(result.Matches)
+		reverse := lookupReverseDNS(control.Context(), matchHosts(result.Matches), a.settings.RequestTimeout())
+		links, linkErr := a.startLookupMatchLinks(config.Edition, result.Matches)
+		metrics := networkScanMetrics{
+			Targets:   config.Targets,
+			Addresses: len(config.Addresses),
+			Ports:     len(config.Ports),
+			Lookup: lookupMetrics{
+				Duration:      time.Since(startedAt),
+				AverageRate:   calculateLookupAverageRate(result.Completed, startedAt),
+				Concurrency:   resolveLookupConcurrency(a.settings.LookupConcurrency, result.Attempts),
+				Adaptive:      a.settings.AdaptiveConcurrency,
+				FinalWorkers:  result.Workers,
+				PeakWorkers:   result.PeakWorkers,
+				RateLimit:     a.settings.LookupRateLimit,
+				CompletionPct: calculateLookupCompletion(result.Completed, result.Attempts),
+				Filter:        lookupFilterLabel(config.Filter),
+				Canceled:      errors.Is(scanErr, context.Canceled) || control.IsCancelled(),
+			},
+		}
+		displayOptions := resultFormatOptions{Verbose: a.settings.Verbose, ColorMOTD: a.settings.ColorMOTD}
+		exportOptions := resultFormatOptions{Verbose: a.settings.Verbose, ColorMOTD: false}
+		displayText := formatNetworkScanResult(result, reverse, links, metrics, displayOptions)
+		exportText := formatNetworkScanResult(result, reverse, links, metrics, exportOptions)
+		records, iconErr := a.lookupExportRecords("network_scan", config.Edition, result, links)
+		for i := range records {
+			records[i].ReverseDNS = reverse[records[i].Host]
+		}
+		displayText, exportText = a.templateResultText(newResultTemplateData("Network scan", "network_scan", records, &metrics.Lookup), displayText, exportText)
+		if linkErr != nil {
+			displayText = appendWarningText(displayText, "Bedrock browser links unavailable", linkErr)
+		}
+		if iconErr != nil {
+			displayText = appendWarningText(displayText, "One or more server icons could not be saved", iconErr)
+		}
+		if a.settings.SaveResults {
+			path, err := a.saveExport("Network scan", exportText, records)
+			if err != nil {
+				displayText = appendWarningText(displayText, "Result export failed", err)
+			} else {
+				displayText += fmt.Sprintf("\nSaved result: %s", path)
+			}
+		}
+		a.rememberResults("Network scan", records)
+		displayText = a.appendDashboardText(displayText, "Network scan", records)
+		return displayText, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return renderTextPageAndWait("Network scan", resultText)
+}
+
+func splitNetworkTargets(value string) []string {
+	targets := make([]string, 0)
+	for _, line := range strings.Split(value, "\n") {
+		line = stripInlineComment(line)
+		var current strings.Builder
+		depth := 0
+		flush := func() {
+			if token := strings.TrimSpace(current.String()); token != "" {
+				targets = append(targets, token)
+			}
+			current.Reset()
+		}
+		for _, r := range line {
+			switch {
+			case r == '[':
+				depth++
+				current.WriteRune(r)
+			case r == ']':
+				if depth > 0 {
+					depth--
+				}
+				current.WriteRune(r)
+			case depth == 0 && (r == ',' || r == ';' || r == ' ' || r == '\t' || r == '\r'):
+				flush()
+			default:
+				current.WriteRune(r)
+			}
+		}
+		flush()
+	}
+	return targets
+}
+
+func expandNetworkTargets(targets []string, limit int) ([]string, error) {
+	if len(targets) == 0 {
+		return nil, fmt.Errorf("no targets provided")
+	}
+	if limit < 1 {
+		limit = 1
+	}
+	seen := make(map[netip.Addr]struct{})
+	addresses := make([]string, 0)
+	add := func(addr netip.Addr) error {
+		addr = addr.Unmap()
+		if _, ok := seen[addr]; ok {
+			return nil
+		}
+		if len(addresses) >= limit {
+			return fmt.Errorf("targets exceed the scan cap of %d addresses", limit)
+		}
+		seen[addr] = struct{}{}
+		addresses = append(addresses, addr.String())
+		return nil
+	}
+	for _, target := range targets {
+		if err := expandNetworkTarget(target, limit-len(addresses), add); err != nil {
+			return nil, err
+		}
+	}
+	return addresses, nil
+}
+
+func expandNetworkTarget(target string, remaining int, add func(netip.Addr) error) error {
+	switch {
+	case strings.Contains(target, "["):
+		return expandPrefixHosts(target, add)
+	case strings.Contains(target, "/"):
+		prefix, err := netip.ParsePrefix(target)
+		if err != nil {
+			return fmt.Errorf("invalid CIDR %q", target)
+		}
+		prefix = prefix.Masked()
+		hostBits := prefix.Addr().BitLen() - prefix.Bits()
+		if hostBits >= 62 || 1<<hostBits > remaining {
+			return fmt.Errorf("%s exceeds the scan cap; narrow the prefix or list hosts as %s[::1,::2]", target, prefix)
+		}
+		for addr := prefix.Addr(); addr.IsValid() && prefix.Contains(addr); addr = addr.Next() {
+			if err := add(addr); err != nil {
+				return err
+			}
+		}
+		return nil
+	case strings.Contains(target, "-"):
+		start, end, err := parseAddressRange(target)
+		if err != nil {
+			return err
+		}
+		for addr := start; addr.IsValid() && addr.Compare(end) <= 0; addr = addr.Next() {
+			if err := add(addr); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		addr, err := netip.ParseAddr(target)
+		if err != nil {
+			return fmt.Errorf("invalid IP address %q", target)
+		}
+		return add(addr)
+	}
+}
+
+func expandPrefixHosts(target string, add func(netip.Addr) error) error {
+	open := strings.Index(target, "[")
+	if !strings.HasSuffix(target, "]") {
+		return fmt.Errorf("invalid host list in %q", target)
+	}
+	prefix, err := netip.ParsePrefix(strings.TrimSpace(target[:open]))
+	if err != nil {
+		return fmt.Errorf("invalid CIDR %q", target[:open])
+	}
+	prefix = prefix.Masked()
+	hosts := strings.FieldsFunc(target[open+1:len(target)-1], func(r rune) bool {
+		return r == ',' || r == ' '
+	})
+	if len(hosts) == 0 {
+		return fmt.Errorf("empty host list in %q", target)
+	}
+	for _, host := range hosts {
+		addr, err := combinePrefixHost(prefix, host)
+		if err != nil {
+			return err
+		}
+		if err := add(addr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func combinePrefixHost(prefix netip.Prefix, host string) (netip.Addr, error) {
+	suffix, err := netip.ParseAddr(strings.TrimSpace(host))
+	if err != nil && prefix.Addr().Is4() {
+		suffix, err = netip.ParseAddr("0.0.0." + strings.TrimSpace(host))
+	}
+	if err != nil || suffix.BitLen() != prefix.Addr().BitLen() {
+		return netip.Addr{}, fmt.Errorf("invalid host %q for %s", host, prefix)
+	}
+	base := prefix.Addr().AsSlice()
+	extra := suffix.AsSlice()
+	for i := range base {
+		bit := i * 8
+		var mask byte
+		switch {
+		case bit+8 <= prefix.Bits():
+			mask = 0xff
+		case bit < prefix.Bits():
+			mask = byte(0xff << (8 - (prefix.Bits() - bit)))
+		}
+		if extra[i]&mask != 0 {
+			return netip.Addr{}, fmt.Errorf("host %q does not fit inside %s", host, prefix)
+		}
+		base[i] |= extra[i]
+	}
+	addr, _ := netip.AddrFromSlice(base)
+	return addr, nil
+}
+
+func parseAddressRange(target string) (netip.Addr, netip.Addr, error) {
+	startText, endText, _ := strings.Cut(target, "-")
+	start, err := netip.ParseAddr(strings.TrimSpace(startText))
+	if err != nil {
+		return netip.Addr{}, netip.Addr{}, fmt.Errorf("invalid range start in %q", target)
+	}
+	endText = strings.TrimSpace(endText)
+	end, err := netip.ParseAddr(endText)
+	if err != nil && start.Is4() {
+		octets := strings.Split(start.String(), ".")
+		octets[3] = endText
+		end, err = netip.ParseAddr(strings.Join(octets, "."))
+	}
+	if err != nil {
+		return netip.Addr{}, netip.Addr{}, fmt.Errorf("invalid range end in %q", target)
+	}
+	if start.BitLen() != end.BitLen() {
+		return netip.Addr{}, netip.Addr{}, fmt.Errorf("range %q mixes IPv4 and IPv6", target)
+	}
+	if end.Less(start) {
+		return netip.Addr{}, netip.Addr{}, fmt.Errorf("range %q ends before it starts", target)
+	}
+	return start, end, nil
+}
+
+func networkScanTargets(addresses []string) []ping.LookupTarget {
+	targets := make([]ping.LookupTarget, 0, len(addresses))
+	for _, address := range addresses {
+		targets = append(targets, ping.LookupTarget{Host: address, Source: "network"})
+	}
+	return targets
+}
+
+func sortNetworkScanMatches(matches []ping.LookupMatch) {
+	sort.SliceStable(matches, func(i, j int) bool {
+		left, leftErr := netip.ParseAddr(matches[i].Host)
+		right, rightErr := netip.ParseAddr(matches[j].Host)
+		if leftErr == nil && rightErr == nil && left != right {
+			return left.Less(right)
+		}
+		if matches[i].Host != matches[j].Host {
+			return matches[i].Host < matches[j].Host
+		}
+		return matches[i].Port < matches[j].Port
+	})
+}
+
+func matchHosts(matches []ping.LookupMatch) []string {
+	hosts := make([]string, 0, len(matches))
+	seen := make(map[string]struct{}, len(matches))
+	for _, match := range matches {
+		if _, ok := seen[match.Host]; ok {
+			continue
+		}
+		seen[match.Host] = struct{}{}
+		hosts = append(hosts, match.Host)
+	}
+	return hosts
+}
+
+func lookupReverseDNS(ctx context.Context, hosts []string, timeout time.Duration) map[string][]string {
+	if timeout <= 0 {
+		timeout = 3 * time.Second
+	}
+	names := make(map[string][]string, len(hosts))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, reverseDNSWorkers)
+	for _, host := range hosts {
+		if ctx.Err() != nil {
+			break
+		}
+		wg.Add(1)
+		slots <- struct{}{}
+		go func(host string) {
+			defer wg.Done()
+			defer func() { <-slots }()
+			ctx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+			values, err := net.DefaultResolver.LookupAddr(ctx, host)
+			if err != nil || len(values) == 0 {
+				return
+			}
+			list := make([]string, 0, len(values))
+			for _, value := range values {
+				list = append(list, strings.TrimSuffix(value, "."))
+			}
+			mu.Lock()
+			names[host] = list
+			mu.Unlock()
+		}(host)
+	}
+	wg.Wait()
+	return names
+}
+
+func formatNetworkScanResult(result ping.LookupResult, reverse map[string][]string, links []web.LookupLinkURLs, metrics networkScanMetrics, options resultFormatOptions) string {
+	var builder strings.Builder
+	builder.WriteString("Summary\n")
+	builder.WriteString(fmt.Sprintf("- Targets: %s\n", strings.Join(metrics.Targets, ", ")))
+	builder.WriteString(fmt.Sprintf("- Addresses: %d\n", metrics.Addresses))
+	builder.WriteString(fmt.Sprintf("- Ports: %d\n", metrics.Ports))
+	builder.WriteString(fmt.Sprintf("- Checked combinations: %d/%d\n", result.Completed, result.Attempts))
+	builder.WriteString(fmt.Sprintf("- Completion: %.1f%%\n", metrics.Lookup.CompletionPct))
+	builder.WriteString(fmt.Sprintf("- Servers found: %d\n", len(result.Matches)))
+	if metrics.Lookup.Filter != "" {
+		builder.WriteString(fmt.Sprintf("- Filter: %s\n", metrics.Lookup.Filter))
+	}
+	if result.Rejected > 0 {
+		builder.WriteString(fmt.Sprintf("- Not matching: %d\n", result.Rejected))
+	}
+	builder.WriteString(fmt.Sprintf("- Elapsed: %s\n", formatLookupDuration(metrics.Lookup.Duration)))
+	builder.WriteString(fmt.Sprintf("- Average throughput: %s\n", formatLookupRate(metrics.Lookup.AverageRate)))
+	builder.WriteString(fmt.Sprintf("- Pipeline: %s\n", formatLookupPipeline(metrics.Lookup)))
+	builder.WriteString(fmt.Sprintf("- Rate cap: %s\n", formatLookupRateCap(metrics.Lookup.RateLimit)))
+	if metrics.Lookup.Canceled {
+		builder.WriteString("- Status: canceled\n")
+	}
+	builder.WriteString("\n")
+	builder.WriteString(formatFailureSection(result.Failures, lookupUnresponsiveLines(result.Unresponsive)))
+
+	if len(result.Matches) == 0 {
+		builder.WriteString("No servers found.")
+		return builder.String()
+	}
+
+	builder.WriteString("Servers\n")
+	for i, match := range result.Matches {
+		if i > 0 {
+			builder.WriteString("\n")
+		}
+		builder.WriteString(fmt.Sprintf("Server %d\n", i+1))
+		builder.WriteString(fmt.Sprintf("Address: %s\n", match.Host))
+		builder.WriteString(fmt.Sprintf("Port: %d\n", match.Port))
+		if names := reverse[match.Host]; len(names) > 0 {
+			builder.WriteString(fmt.Sprintf("Reverse DNS: %s\n", strings.Join(names, ", ")))
+		}
+		if i < len(links) {
+			builder.WriteString(fmt.Sprintf("Add link (browser): %s\n", links[i].AddURL))
+			builder.WriteString(fmt.Sprintf("Join link (browser): %s\n", links[i].ConnectURL))
+		}
+		builder.WriteString(formatDirectResult(match.Result, match.Detail, options))
+		builder.WriteString("\n")
+	}
+	return builder.String()
+}
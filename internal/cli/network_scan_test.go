@@ -0,0 +1,40 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestExpandNetworkTargetsMixesFormats(t *testing.T) {
+	targets := splitNetworkTargets("192.0.2.0/30, 192.0.2.10-12 # office\n2001:db8::/64[::1, ::a]\n192.0.2.1")
+	got, err := expandNetworkTargets(targets, 100)
+	if err != nil {
+		t.Fatalf("expandNetworkTargets returned error: %v", err)
+	}
+	want := []string{
+		"192.0.2.0", "192.0.2.1", "192.0.2.2", "192.0.2.3",
+		"192.0.2.10", "192.0.2.11", "192.0.2.12",
+		"2001:db8::1", "2001:db8::a",
+	}
+	if strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Fatalf("addresses = %v, want %v", got, want)
+	}
+}
+
+func TestExpandNetworkTargetsEnforcesCap(t *testing.T) {
+	if _, err := expandNetworkTargets([]string{"10.0.0.0/24"}, 100); err == nil {
+		t.Fatal("expected /24 to exceed a cap of 100")
+	}
+	if _, err := expandNetworkTargets([]string{"2001:db8::/64"}, 65536); err == nil {
+		t.Fatal("expected bare IPv6 /64 to be rejected")
+	}
+	if _, err := expandNetworkTargets([]string{"10.0.0.1-10.0.0.200"}, 100); err == nil {
+		t.Fatal("expected range to exceed a cap of 100")
+	}
+}
+
+func TestExpandNetworkTargetsRejectsHostOutsidePrefix(t *testing.T) {
+	if _, err := expandNetworkTargets([]string{"2001:db8::/64[2001:db9::1]"}, 10); err == nil {
+		t.Fatal("expected host outside the prefix to be rejected")
+	}
+}
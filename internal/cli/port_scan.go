@@ -29,14 +29,15 @@ func (a *App) executePortScan() error {
 		return fmt.Sprintf("Host: %s\n%s", host, progress.Render(frame, control))
 	}, 120*time.Millisecond, func(control *spinnerControl) (string, error) {
 		runResults = a.runBatchEntries(control, entries, progress)
-		return formatBatchResults("Port scan", runResults, nil, control.IsCancelled()), nil
+		return formatBatchResults("Port scan", runResults, nil, control.IsCancelled(), a.settings.ReportUnresponsive), nil
 	})
 	if err != nil {
 		return err
 	}
 
-	exportText := formatBatchResults("Port scan", runResults, nil, false)
+	exportText := formatBatchResults("Port scan", runResults, nil, false, a.settings.ReportUnresponsive)
 	records := batchExportRecords("port_scan", runResults)
+	resultText, exportText = a.templateResultText(newResultTemplateData("Port scan", "port_scan", records, nil), resultText, exportText)
 	if a.settings.SaveResults {
 		path, err := a.saveExport("Port scan", exportText, records)
 		if err != nil {
@@ -0,0 +1,645 @@
+package cli
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"UWP-TCP-Con/internal/ping"
+)
+
+type filterKind int
+
+const (
+	filterNumber filterKind = iota
+	filterString
+	filterBool
+	filterList
+)
+
+func (k filterKind) String() string {
+	switch k {
+	case filterNumber:
+		return "number"
+	case filterString:
+		return "text"
+	case filterBool:
+		return "boolean"
+	default:
+		return "list"
+	}
+}
+
+type filterValue struct {
+	num     float64
+	str     string
+	boolean bool
+	list    []string
+	missing bool
+}
+
+type filterField struct {
+	kind  filterKind
+	value func(record exportRecord) filterValue
+}
+
+func onlineNumber(record exportRecord, value int64) filterValue {
+	return filterValue{num: float64(value), missing: !record.Success}
+}
+
+var filterFields = map[string]filterField{
+	"host":    {filterString, func(r exportRecord) filterValue { return filterValue{str: r.Host} }},
+	"name":    {filterString, func(r exportRecord) filterValue { return filterValue{str: ping.StripFormatting(r.Name)} }},
+	"edition": {filterString, func(r exportRecord) filterValue { return filterValue{str: r.Edition} }},
+	"port":    {filterNumber, func(r exportRecord) filterValue { return filterValue{num: float64(r.Port)} }},
+	"online":  {filterBool, func(r exportRecord) filterValue { return filterValue{boolean: r.Success} }},
+	"players": {filterNumber, func(r exportRecord) filterValue { return onlineNumber(r, int64(r.PlayersOnline)) }},
+	"max":     {filterNumber, func(r exportRecord) filterValue { return onlineNumber(r, int64(r.PlayersMax)) }},
+	"latency": {filterNumber, func(r exportRecord) filterValue {
+		return filterValue{num: float64(r.LatencyMillis), missing: !r.Success || r.LatencyMillis <= 0}
+	}},
+	"version": {filterString, func(r exportRecord) filterValue {
+		return filterValue{str: ping.StripFormatting(r.Version), missing: !r.Success}
+	}},
+	"protocol": {filterNumber, func(r exportRecord) filterValue {
+		protocol, err := strconv.Atoi(strings.TrimSpace(r.Protocol))
+		return filterValue{num: float64(protocol), missing: err != nil}
+	}},
+	"motd": {filterString, func(r exportRecord) filterValue {
+		return filterValue{str: strings.TrimSpace(ping.StripFormatting(recordCleanMOTD(r))), missing: !r.Success}
+	}},
+	"source": {filterString, func(r exportRecord) filterValue { return filterValue{str: r.Source} }},
+	"tags":   {filterList, func(r exportRecord) filterValue { return filterValue{list: r.Tags} }},
+	"ip":     {filterString, func(r exportRecord) filterValue { return filterValue{str: r.SelectedIP} }},
+	"srv":    {filterBool, func(r exportRecord) filterValue { return filterValue{boolean: r.SRVUsed} }},
+	"icon": {filterBool, func(r exportRecord) filterValue {
+		return filterValue{boolean: len(r.IconPNG) > 0 || r.JavaIconSavedTo != ""}
+	}},
+	"class": {filterString, func(r exportRecord) filterValue { return filterValue{str: r.FailureClass} }},
+	"error": {filterString, func(r exportRecord) filterValue { return filterValue{str: r.Error} }},
+}
+
+func filterFieldNames() string {
+	names := make([]string, 0, len(filterFields))
+	for name := range filterFields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+type resultFilter struct {
+	source string
+	root   filterNode
+}
+
+type filterNode interface {
+	match(record exportRecord) bool
+}
+
+type filterOr struct{ left, right filterNode }
+type filterAnd struct{ left, right filterNode }
+type filterNot struct{ inner filterNode }
+
+type filterTruth struct{ operand filterOperand }
+
+type filterCompare struct {
+	left, right filterOperand
+	op          string
+	pattern     *regexp.Regexp
+}
+
+type filterMethod struct {
+	field   string
+	method  string
+	arg     string
+	pattern *regexp.Regexp
+}
+
+type filterOperand struct {
+	kind    filterKind
+	field   string
+	literal filterValue
+}
+
+func (o filterOperand) value(record exportRecord) filterValue {
+	if o.field == "" {
+		return o.literal
+	}
+	return filterFields[o.field].value(record)
+}
+
+func (n filterOr) match(r exportRecord) bool  { return n.left.match(r) || n.right.match(r) }
+func (n filterAnd) match(r exportRecord) bool { return n.left.match(r) && n.right.match(r) }
+func (n filterNot) match(r exportRecord) bool { return !n.inner.match(r) }
+
+func (n filterTruth) match(r exportRecord) bool {
+	value := n.operand.value(r)
+	if value.missing {
+		return false
+	}
+	switch n.operand.kind {
+	case filterNumber:
+		return value.num != 0
+	case filterString:
+		return value.str != ""
+	case filterBool:
+		return value.boolean
+	default:
+		return len(value.list) > 0
+	}
+}
+
+func (n filterCompare) match(r exportRecord) bool {
+	left, right := n.left.value(r), n.right.value(r)
+	if left.missing || right.missing {
+		return false
+	}
+	switch n.op {
+	case "~":
+		return n.pattern.MatchString(left.str)
+	case "!~":
+		return !n.pattern.MatchString(left.str)
+	}
+	var order int
+	switch n.left.kind {
+	case filterNumber:
+		order = compareFloat(left.num, right.num)
+	case filterString:
+		order = strings.Compare(strings.ToLower(left.str), strings.ToLower(right.str))
+	case filterBool:
+		order = compareBool(left.boolean, right.boolean)
+	}
+	switch n.op {
+	case "==":
+		return order == 0
+	case "!=":
+		return order != 0
+	case ">":
+		return order > 0
+	case ">=":
+		return order >= 0
+	case "<":
+		return order < 0
+	default:
+		return order <= 0
+	}
+}
+
+func (n filterMethod) match(r exportRecord) bool {
+	value := filterFields[n.field].value(r)
+	if value.missing {
+		return false
+	}
+	if filterFields[n.field].kind == filterList {
+		for _, item := range value.list {
+			if strings.EqualFold(item, n.arg) {
+				return true
+			}
+		}
+		return false
+	}
+	text := strings.ToLower(value.str)
+	switch n.method {
+	case "contains":
+		return strings.Contains(text, n.arg)
+	case "startsWith":
+		return strings.HasPrefix(text, n.arg)
+	case "endsWith":
+		return strings.HasSuffix(text, n.arg)
+	default:
+		return n.pattern.MatchString(value.str)
+	}
+}
+
+func compareFloat(left, right float64) int {
+	switch {
+	case left < right:
+		return -1
+	case left > right:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func compareBool(left, right bool) int {
+	switch {
+	case left == right:
+		return 0
+	case right:
+		return -1
+	default:
+		return 1
+	}
+}
+
+func (f *resultFilter) Match(record exportRecord) bool {
+	return f == nil || f.root.match(record)
+}
+
+func (f *resultFilter) String() string {
+	if f == nil {
+		return ""
+	}
+	return f.source
+}
+
+func (f *resultFilter) Apply(records []exportRecord) []exportRecord {
+	if f == nil {
+		return records
+	}
+	matched := make([]exportRecord, 0, len(records))
+	for _, record := range records {
+		if f.Match(record) {
+			matched = append(matched, record)
+		}
+	}
+	return matched
+}
+
+func parseResultFilter(source string) (*resultFilter, error) {
+	source = strings.TrimSpace(source)
+	if source == "" {
+		return nil, nil
+	}
+	tokens, err := scanFilterTokens(source)
+	if err != nil {
+		return nil, err
+	}
+	parser := &filterParser{tokens: tokens}
+	root, err := parser.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if token := parser.peek(); token.kind != filterTokenEnd {
+		return nil, fmt.Errorf("unexpected %q at column %d", token.text, token.pos+1)
+	}
+	return &resultFilter{source: source, root: root}, nil
+}
+
+type filterTokenKind int
+
+const (
+	filterTokenEnd filterTokenKind = iota
+	filterTokenIdent
+	filterTokenNumber
+	filterTokenString
+	filterTokenOp
+)
+
+type filterToken struct {
+	kind filterTokenKind
+	text string
+	pos  int
+}
+
+var filterOperators = []string{"&&", "||", "==", "!=", ">=", "<=", "!~", ">", "<", "~", "!", "(", ")", ".", ","}
+
+func scanFilterTokens(source string) ([]filterToken, error) {
+	var tokens []filterToken
+	for i := 0; i < len(source); {
+		c := rune(source[i])
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case c == '"':
+			end := i + 1
+			for end < len(source) && source[end] != '"' {
+				if source[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(source) {
+				return nil, fmt.Errorf("unterminated string at column %d", i+1)
+			}
+			text, err := strconv.Unquote(source[i : end+1])
+			if err != nil {
+				return nil, fmt.Errorf("invalid string at column %d: %w", i+1, err)
+			}
+			tokens = append(tokens, filterToken{filterTokenString, text, i})
+			i = end + 1
+		case unicode.IsDigit(c):
+			end := i
+			for end < len(source) && (unicode.IsDigit(rune(source[end])) || source[end] == '.') {
+				end++
+			}
+			tokens = append(tokens, filterToken{filterTokenNumber, source[i:end], i})
+			i = end
+		case unicode.IsLetter(c) || c == '_':
+			end := i
+			for end < len(source) && (unicode.IsLetter(rune(source[end])) || unicode.IsDigit(rune(source[end])) || source[end] == '_') {
+				end++
+			}
+			tokens = append(tokens, filterToken{filterTokenIdent, source[i:end], i})
+			i = end
+		default:
+			matched := false
+			for _, op := range filterOperators {
+				if strings.HasPrefix(source[i:], op) {
+					tokens = append(tokens, filterToken{filterTokenOp, op, i})
+					i += len(op)
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return nil, fmt.Errorf("unexpected %q at column %d", c, i+1)
+			}
+		}
+	}
+	return append(tokens, filterToken{kind: filterTokenEnd, text: "end of expression", pos: len(source)}), nil
+}
+
+type filterParser struct {
+	tokens []filterToken
+	pos    int
+}
+
+func (p *filterParser) peek() filterToken {
+	return p.tokens[p.pos]
+}
+
+func (p *filterParser) next() filterToken {
+	token := p.tokens[p.pos]
+	if token.kind != filterTokenEnd {
+		p.pos++
+	}
+	return token
+}
+
+func (p *filterParser) accept(texts ...string) bool {
+	token := p.peek()
+	for _, text := range texts {
+		if (token.kind == filterTokenOp || token.kind == filterTokenIdent) && token.text == text {
+			p.pos++
+			return true
+		}
+	}
+	return false
+}
+
+func (p *filterParser) expect(text string) error {
+	if p.accept(text) {
+		return nil
+	}
+	token := p.peek()
+	return fmt.Errorf("expected %q at column %d, found %q", text, token.pos+1, token.text)
+}
+
+func (p *filterParser) parseOr() (filterNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("||", "or") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = filterOr{left, right}
+	}
+	return left, nil
+}
+
+func (p *filterParser) parseAnd() (filterNode, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("&&", "and") {
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = filterAnd{left, right}
+	}
+	return left, nil
+}
+
+func (p *filterParser) parseUnary() (filterNode, error) {
+	if p.accept("!", "not") {
+		inner, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return filterNot{inner}, nil
+	}
+	if p.accept("(") {
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		return inner, p.expect(")")
+	}
+	return p.parseComparison()
+}
+
+func (p *filterParser) parseComparison() (filterNode, error) {
+	start := p.peek()
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	if left.field != "" && p.accept(".") {
+		return p.parseMethod(left, start)
+	}
+	op := p.peek()
+	if op.kind != filterTokenOp || !isFilterComparison(op.text) {
+		return filterTruth{left}, nil
+	}
+	p.next()
+	rightToken := p.peek()
+	right, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	node := filterCompare{left: left, right: right, op: op.text}
+	switch {
+	case op.text == "~" || op.text == "!~":
+		if left.kind != filterString || right.field != "" || right.kind != filterString {
+			return nil, fmt.Errorf("column %d: %s needs a text field and a quoted pattern", op.pos+1, op.text)
+		}
+		node.pattern, err = regexp.Compile("(?i)" + right.literal.str)
+		if err != nil {
+			return nil, fmt.Errorf("column %d: invalid pattern: %w", rightToken.pos+1, err)
+		}
+	case left.kind != right.kind:
+		return nil, fmt.Errorf("column %d: cannot compare %s with %s", op.pos+1, left.kind, right.kind)
+	case left.kind == filterList:
+		return nil, fmt.Errorf("column %d: use %s.contains(...) for lists", op.pos+1, left.field)
+	case left.kind == filterBool && op.text != "==" && op.text != "!=":
+		return nil, fmt.Errorf("column %d: booleans only support == and !=", op.pos+1)
+	}
+	return node, nil
+}
+
+func isFilterComparison(op string) bool {
+	switch op {
+	case "==", "!=", ">", ">=", "<", "<=", "~", "!~":
+		return true
+	default:
+		return false
+	}
+}
+
+func (p *filterParser) parseOperand() (filterOperand, error) {
+	token := p.next()
+	switch token.kind {
+	case filterTokenNumber:
+		value, err := strconv.ParseFloat(token.text, 64)
+		if err != nil {
+			return filterOperand{}, fmt.Errorf("invalid number %q at column %d", token.text, token.pos+1)
+		}
+		return filterOperand{kind: filterNumber, literal: filterValue{num: value}}, nil
+	case filterTokenString:
+		return filterOperand{kind: filterString, literal: filterValue{str: token.text}}, nil
+	case filterTokenIdent:
+		switch token.text {
+		case "true", "false":
+			return filterOperand{kind: filterBool, literal: filterValue{boolean: token.text == "true"}}, nil
+		}
+		field, ok := filterFields[token.text]
+		if !ok {
+			return filterOperand{}, fmt.Errorf("unknown field %q at column %d (fields: %s)", token.text, token.pos+1, filterFieldNames())
+		}
+		return filterOperand{kind: field.kind, field: token.text}, nil
+	default:
+		return filterOperand{}, fmt.Errorf("expected a field or value at column %d, found %q", token.pos+1, token.text)
+	}
+}
+
+func (p *filterParser) parseMethod(target filterOperand, start filterToken) (filterNode, error) {
+	name := p.next()
+	if name.kind != filterTokenIdent {
+		return nil, fmt.Errorf("expected a method after %s. at column %d", target.field, name.pos+1)
+	}
+	node := filterMethod{field: target.field, method: name.text}
+	switch {
+	case target.kind == filterList && name.text != "contains":
+		return nil, fmt.Errorf("column %d: lists only support contains", name.pos+1)
+	case target.kind != filterList && target.kind != filterString:
+		return nil, fmt.Errorf("column %d: %s is a %s and has no methods", start.pos+1, target.field, target.kind)
+	}
+	switch name.text {
+	case "contains", "startsWith", "endsWith", "matches":
+	default:
+		return nil, fmt.Errorf("unknown method %q at column %d (contains, startsWith, endsWith, matches)", name.text, name.pos+1)
+	}
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+	arg := p.next()
+	if arg.kind != filterTokenString {
+		return nil, fmt.Errorf("column %d: %s expects a quoted argument", arg.pos+1, name.text)
+	}
+	node.arg = strings.ToLower(arg.text)
+	if name.text == "matches" {
+		pattern, err := regexp.Compile("(?i)" + arg.text)
+		if err != nil {
+			return nil, fmt.Errorf("column %d: invalid pattern: %w", arg.pos+1, err)
+		}
+		node.pattern = pattern
+	}
+	return node, p.expect(")")
+}
+
+type resultSortKey struct {
+	field string
+	desc  bool
+}
+
+type resultSort []resultSortKey
+
+func parseResultSort(source string) (resultSort, error) {
+	var keys resultSort
+	for _, part := range strings.Split(source, ",") {
+		fields := strings.Fields(part)
+		if len(fields) == 0 {
+			continue
+		}
+		key := resultSortKey{field: fields[0]}
+		if name, ok := strings.CutPrefix(key.field, "-"); ok {
+			key.field, key.desc = name, true
+		}
+		if len(fields) > 2 {
+			return nil, fmt.Errorf("sort key %q: expected a field and asc or desc", strings.TrimSpace(part))
+		}
+		if len(fields) == 2 {
+			switch strings.ToLower(fields[1]) {
+			case "asc":
+			case "desc":
+				key.desc = !key.desc
+			default:
+				return nil, fmt.Errorf("sort key %q: expected asc or desc, found %q", strings.TrimSpace(part), fields[1])
+			}
+		}
+		field, ok := filterFields[key.field]
+		if !ok {
+			return nil, fmt.Errorf("unknown sort field %q (fields: %s)", key.field, filterFieldNames())
+		}
+		if field.kind == filterList {
+			return nil, fmt.Errorf("cannot sort by the list field %q", key.field)
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+func (s resultSort) String() string {
+	parts := make([]string, 0, len(s))
+	for _, key := range s {
+		if key.desc {
+			parts = append(parts, key.field+" desc")
+		} else {
+			parts = append(parts, key.field)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+func (s resultSort) Apply(records []exportRecord) {
+	if len(s) == 0 {
+		return
+	}
+	sort.SliceStable(records, func(i, j int) bool { return s.Less(records[i], records[j]) })
+}
+
+func (s resultSort) Less(left, right exportRecord) bool {
+	for _, key := range s {
+		if order := compareSortField(key, left, right); order != 0 {
+			return order < 0
+		}
+	}
+	return false
+}
+
+func compareSortField(key resultSortKey, left, right exportRecord) int {
+	field := filterFields[key.field]
+	a, b := field.value(left), field.value(right)
+	switch {
+	case a.missing && b.missing:
+		return 0
+	case a.missing:
+		return 1
+	case b.missing:
+		return -1
+	}
+	var order int
+	switch field.kind {
+	case filterNumber:
+		order = compareFloat(a.num, b.num)
+	case filterString:
+		order = strings.Compare(strings.ToLower(a.str), strings.ToLower(b.str))
+	case filterBool:
+		order = compareBool(a.boolean, b.boolean)
+	}
+	if key.desc {
+		return -order
+	}
+	return order
+}
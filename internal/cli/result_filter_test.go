@@ -0,0 +1,161 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+
+	"UWP-TCP-Con/internal/ping"
+	"UWP-TCP-Con/internal/web"
+)
+
+func filterTestRecords() []exportRecord {
+	return []exportRecord{
+		{Host: "play.example.net", Port: 25565, Edition: "java", Success: true, PlayersOnline: 42, PlayersMax: 100, LatencyMillis: 30, Version: "Paper 1.20.4", Protocol: "765", CleanMOTD: "Survival", Tags: []string{"prod"}},
+		{Host: "test.example.net", Port: 25565, Edition: "java", Success: true, PlayersOnline: 12, PlayersMax: 50, LatencyMillis: 80, Version: "1.20.1", CleanMOTD: "Down for Maintenance"},
+		{Host: "old.example.net", Port: 25566, Edition: "java", Success: true, PlayersOnline: 3, PlayersMax: 20, LatencyMillis: 10, Version: "1.8.9", CleanMOTD: "Legacy"},
+		{Host: "dead.example.net", Port: 25565, Edition: "java", Error: "connection refused", FailureClass: "refused"},
+	}
+}
+
+func filterHosts(records []exportRecord) string {
+	hosts := make([]string, 0, len(records))
+	for _, record := range records {
+		hosts = append(hosts, strings.SplitN(record.Host, ".", 2)[0])
+	}
+	return strings.Join(hosts, ",")
+}
+
+func TestResultFilterMatches(t *testing.T) {
+	cases := []struct {
+		source string
+		want   string
+	}{
+		{`players > 10 && version ~ "1.20" && !motd.contains("maintenance")`, "play"},
+		{`players > 10 and version ~ "1.20"`, "play,test"},
+		{`online == false || port != 25565`, "old,dead"},
+		{`not online`, "dead"},
+		{`players >= 3 && (latency < 20 || tags.contains("PROD"))`, "play,old"},
+		{`host.endsWith(".example.net") && class == "refused"`, "dead"},
+		{`version !~ "^1\\.20" && online`, "play,old"},
+		{`motd.matches("^(survival|legacy)$")`, "play,old"},
+		{`protocol`, "play"},
+		{`name == ""`, "play,test,old,dead"},
+	}
+	for _, tc := range cases {
+		filter, err := parseResultFilter(tc.source)
+		if err != nil {
+			t.Fatalf("parseResultFilter(%q): %v", tc.source, err)
+		}
+		if got := filterHosts(filter.Apply(filterTestRecords())); got != tc.want {
+			t.Fatalf("%s: got %q, want %q", tc.source, got, tc.want)
+		}
+	}
+}
+
+func TestResultFilterEmptyMatchesAll(t *testing.T) {
+	filter, err := parseResultFilter("  ")
+	if err != nil || filter != nil {
+		t.Fatalf("expected nil filter, got %v, %v", filter, err)
+	}
+	if got := len(filter.Apply(filterTestRecords())); got != 4 {
+		t.Fatalf("expected all records, got %d", got)
+	}
+}
+
+func TestResultFilterErrors(t *testing.T) {
+	cases := []struct {
+		source string
+		want   string
+	}{
+		{`players > "ten"`, "number"},
+		{`motd > 3`, "text"},
+		{`nickname == "x"`, "unknown field"},
+		{`players > 10 &&`, "end of expression"},
+		{`(players > 1`, ")"},
+		{`version ~ "["`, "invalid pattern"},
+		{`tags.startsWith("p")`, "only support contains"},
+		{`motd.shout("x")`, "shout"},
+		{`host == "open`, "unterminated"},
+	}
+	for _, tc := range cases {
+		_, err := parseResultFilter(tc.source)
+		if err == nil {
+			t.Fatalf("parseResultFilter(%q): expected error", tc.source)
+		}
+		if !strings.Contains(err.Error(), tc.want) {
+			t.Fatalf("parseResultFilter(%q): error %q does not mention %q", tc.source, err, tc.want)
+		}
+	}
+}
+
+func TestResultSortComposesKeys(t *testing.T) {
+	order, err := parseResultSort("port desc, -players")
+	if err != nil {
+		t.Fatalf("parseResultSort: %v", err)
+	}
+	if got := order.String(); got != "port desc, players desc" {
+		t.Fatalf("unexpected sort label %q", got)
+	}
+	records := filterTestRecords()
+	order.Apply(records)
+	if got := filterHosts(records); got != "old,play,test,dead" {
+		t.Fatalf("unexpected order %q", got)
+	}
+}
+
+func TestResultSortMissingValuesLast(t *testing.T) {
+	for _, source := range []string{"latency", "latency desc"} {
+		order, err := parseResultSort(source)
+		if err != nil {
+			t.Fatalf("parseResultSort(%q): %v", source, err)
+		}
+		records := filterTestRecords()
+		records[0], records[3] = records[3], records[0]
+		order.Apply(records)
+		if records[3].Host != "dead.example.net" {
+			t.Fatalf("%s: failed record should sort last, got %q", source, filterHosts(records))
+		}
+	}
+}
+
+func TestResultSortErrors(t *testing.T) {
+	for _, source := range []string{"nickname", "players sideways", "tags", "players desc extra"} {
+		if _, err := parseResultSort(source); err == nil {
+			t.Fatalf("parseResultSort(%q): expected error", source)
+		}
+	}
+}
+
+func TestViewLookupMatchesKeepsLinksAligned(t *testing.T) {
+	result := ping.LookupResult{
+		Matches: []ping.LookupMatch{
+			{Host: "a.example.net", Port: 19132, Result: ping.JavaStatus{CurrentPlayers: 2}},
+			{Host: "b.example.net", Port: 19132, Result: ping.JavaStatus{CurrentPlayers: 0}},
+			{Host: "c.example.net", Port: 19132, Result: ping.JavaStatus{CurrentPlayers: 9}},
+		},
+		Rejected: 1,
+	}
+	links := []web.LookupLinkURLs{{Name: "a"}, {Name: "b"}, {Name: "c"}}
+	filter, err := parseResultFilter("players > 0")
+	if err != nil {
+		t.Fatalf("parseResultFilter: %v", err)
+	}
+	order, err := parseResultSort("players desc")
+	if err != nil {
+		t.Fatalf("parseResultSort: %v", err)
+	}
+
+	view, viewLinks := viewLookupMatches(result, links, "lookup", ping.EditionJava, filter, order)
+	if len(view.Matches) != 2 || view.Matches[0].Host != "c.example.net" || view.Matches[1].Host != "a.example.net" {
+		t.Fatalf("unexpected matches %+v", view.Matches)
+	}
+	if len(viewLinks) != 2 || viewLinks[0].Name != "c" || viewLinks[1].Name != "a" {
+		t.Fatalf("links not aligned: %+v", viewLinks)
+	}
+	if view.Rejected != 2 {
+		t.Fatalf("expected 2 rejected, got %d", view.Rejected)
+	}
+	if len(result.Matches) != 3 {
+		t.Fatalf("original result changed: %+v", result.Matches)
+	}
+}
@@ -0,0 +1,288 @@
+package cli
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"UWP-TCP-Con/internal/ping"
+)
+
+const resultTemplateExt = ".tmpl"
+
+type resultTemplateMode struct {
+	Mode  string
+	Label string
+}
+
+var resultTemplateModes = []resultTemplateMode{
+	{"direct", "Direct query"},
+	{"lookup", "Lookup"},
+	{"batch", "Batch check"},
+	{"port_scan", "Port scan"},
+	{"network_scan", "Network scan"},
+}
+
+type resultTemplateData struct {
+	Title   string
+	Mode    string
+	Time    time.Time
+	Records []exportRecord
+	Online  int
+	Failed  int
+	Metrics *lookupMetrics
+}
+
+func newResultTemplateData(title, mode string, records []exportRecord, metrics *lookupMetrics) resultTemplateData {
+	data := resultTemplateData{Title: title, Mode: mode, Time: time.Now(), Records: records, Metrics: metrics}
+	for _, record := range records {
+		if record.Success {
+			data.Online++
+		} else {
+			data.Failed++
+		}
+	}
+	return data
+}
+
+const exampleResultTemplate = `{{.Title}}: {{.Online}} online, {{.Failed}} failed
+{{range .Records}}{{if .Success -}}
+[OK]  {{address .Host .Port | pad 28}} {{.Version | pad 12}} {{.PlayersOnline}}/{{.PlayersMax}} {{.LatencyMillis}}ms  {{motd . | oneline}}
+{{else -}}
+[ERR] {{address .Host .Port | pad 28}} {{failure .}}
+{{end}}{{end}}`
+
+func resultTemplatesDir() (string, error) {
+	return configFile("templates")
+}
+
+func listResultTemplates() ([]string, error) {
+	dir, err := resultTemplatesDir()
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var names []string
+	for _, entry := range entries {
+		if name, ok := strings.CutSuffix(entry.Name(), resultTemplateExt); ok && !entry.IsDir() {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func resultTemplateFuncs(color bool) template.FuncMap {
+	colorText := func(value string) string {
+		if color {
+			return ping.RenderFormattingANSI(value)
+		}
+		return ping.StripFormatting(value)
+	}
+	return template.FuncMap{
+		"strip":    ping.StripFormatting,
+		"color":    colorText,
+		"motdHTML": ping.RenderFormattingHTML,
+		"motd": func(record exportRecord) string {
+			if record.MOTD != "" {
+				return colorText(record.MOTD)
+			}
+			return record.CleanMOTD
+		},
+		"oneline": func(value string) string {
+			lines := strings.Split(strings.TrimSpace(strings.ReplaceAll(value, "\r", "")), "\n")
+			for i := range lines {
+				lines[i] = strings.TrimSpace(lines[i])
+			}
+			return strings.Join(lines, " / ")
+		},
+		"address": func(host string, port int) string { return net.JoinHostPort(host, strconv.Itoa(port)) },
+		"failure": savedFailureText,
+		"join":    func(sep string, values []string) string { return strings.Join(values, sep) },
+		"pad": func(width int, value string) string {
+			if n := width - len([]rune(ping.StripFormatting(value))); n > 0 {
+				return value + strings.Repeat(" ", n)
+			}
+			return value
+		},
+		"ms":    func(value time.Duration) int64 { return value.Milliseconds() },
+		"upper": strings.ToUpper,
+		"lower": strings.ToLower,
+	}
+}
+
+func parseResultTemplate(name, text string, color bool) (*template.Template, error) {
+	tmpl, err := template.New(name).Funcs(resultTemplateFuncs(color)).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("template %s: %w", name, err)
+	}
+	return tmpl, nil
+}
+
+func executeResultTemplate(tmpl *template.Template, data resultTemplateData) (string, error) {
+	var builder strings.Builder
+	if err := tmpl.Execute(&builder, data); err != nil {
+		return "", fmt.Errorf("template %s: %w", tmpl.Name(), err)
+	}
+	return strings.TrimRight(builder.String(), "\n"), nil
+}
+
+func renderResultTemplate(name string, data resultTemplateData, color bool) (string, string, error) {
+	dir, err := resultTemplatesDir()
+	if err != nil {
+		return "", "", err
+	}
+	text, err := os.ReadFile(filepath.Join(dir, name+resultTemplateExt))
+	if err != nil {
+		return "", "", err
+	}
+	display, err := parseResultTemplate(name, string(text), color)
+	if err != nil {
+		return "", "", err
+	}
+	plain, err := parseResultTemplate(name, string(text), false)
+	if err != nil {
+		return "", "", err
+	}
+	displayText, err := executeResultTemplate(display, data)
+	if err != nil {
+		return "", "", err
+	}
+	exportText, err := executeResultTemplate(plain, data)
+	if err != nil {
+		return "", "", err
+	}
+	return displayText, exportText, nil
+}
+
+func (a *App) templateResultText(data resultTemplateData, displayText, exportText string) (string, string) {
+	name := a.settings.ResultTemplates[data.Mode]
+	if name == "" {
+		return displayText, exportText
+	}
+	display, export, err := renderResultTemplate(name, data, a.settings.ColorMOTD)
+	if err != nil {
+		return appendWarningText(displayText, "Result template failed", err), exportText
+	}
+	return display, export
+}
+
+func (a *App) runRenderCommand(args []string) error {
+	flags := newFlagSet("render")
+	name := flags.String("t", "", "template name (default: the template selected for the export's mode)")
+	where := flags.String("where", "", "only render servers matching this filter expression")
+	sortKeys := flags.String("sort", "", "sort the servers by these keys, e.g. 'players desc, host'")
+	if err := parseCommandFlags(flags, args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return fmt.Errorf("usage: render [-t template] [-where expr] [-sort keys] <export.json>")
+	}
+	filter, err := parseResultFilter(*where)
+	if err != nil {
+		return fmt.Errorf("-where: %w", err)
+	}
+	order, err := parseResultSort(*sortKeys)
+	if err != nil {
+		return fmt.Errorf("-sort: %w", err)
+	}
+	payload, err := loadExportPayload(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	mode := recordsMode(payload.Records)
+	templateName := strings.TrimSpace(*name)
+	if templateName == "" {
+		templateName = a.settings.ResultTemplates[mode]
+	}
+	if templateName == "" {
+		return fmt.Errorf("no template selected for %q results; pass -t", mode)
+	}
+	title := payload.Title
+	if title == "" {
+		title = modeTitle(mode)
+	}
+	records := filter.Apply(payload.Records)
+	order.Apply(records)
+	data := newResultTemplateData(title, mode, records, nil)
+	if createdAt, err := time.Parse(time.RFC3339, payload.CreatedAt); err == nil {
+		data.Time = createdAt
+	}
+	_, text, err := renderResultTemplate(templateName, data, false)
+	if err != nil {
+		return err
+	}
+	fmt.Println(text)
+	return nil
+}
+
+func (a *App) manageResultTemplates() error {
+	dir, err := resultTemplatesDir()
+	if err != nil {
+		return err
+	}
+	for {
+		names, err := listResultTemplates()
+		if err != nil {
+			return err
+		}
+		options := make([]string, 0, len(resultTemplateModes)+2)
+		for _, mode := range resultTemplateModes {
+			current := a.settings.ResultTemplates[mode.Mode]
+			if current == "" {
+				current = "built-in"
+			}
+			options = append(options, fmt.Sprintf("%s: %s", mode.Label, current))
+		}
+		if len(names) == 0 {
+			options = append(options, "Create example: Write example.tmpl")
+		}
+		options = append(options, "Back")
+		index, err := selectOption(fmt.Sprintf("Result templates (%s)", dir), options)
+		if err != nil {
+			return err
+		}
+		switch {
+		case index == len(options)-1:
+			return nil
+		case index == len(resultTemplateModes):
+			if err := os.MkdirAll(dir, 0o755); err != nil {
+				return err
+			}
+			path := filepath.Join(dir, "example"+resultTemplateExt)
+			if err := os.WriteFile(path, []byte(exampleResultTemplate), 0o644); err != nil {
+				return err
+			}
+			continue
+		}
+		mode := resultTemplateModes[index]
+		choices := append([]string{"Built-in layout"}, names...)
+		choice, err := selectOption(mode.Label+" template", choices)
+		if err != nil {
+			return err
+		}
+		if a.settings.ResultTemplates == nil {
+			a.settings.ResultTemplates = make(map[string]string)
+		}
+		if choice == 0 {
+			delete(a.settings.ResultTemplates, mode.Mode)
+		} else {
+			a.settings.ResultTemplates[mode.Mode] = names[choice-1]
+		}
+		if err := saveSettings(a.settings); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"UWP-TCP-Con/internal/ping"
+)
+
+func TestExampleResultTemplate(t *testing.T) {
+	records := []exportRecord{
+		{Edition: "java", Host: "hub.example.com", Port: 25565, Success: true, Version: "1.21", PlayersOnline: 4, PlayersMax: 20, LatencyMillis: 42, MOTD: "§bHello\n§fWorld"},
+		{Edition: "bedrock", Host: "down.example.com", Port: 19132, FailureClass: "connection_refused", Error: "refused"},
+	}
+	data := newResultTemplateData("Batch check", "batch", records, nil)
+	for _, color := range []bool{false, true} {
+		tmpl, err := parseResultTemplate("example", exampleResultTemplate, color)
+		if err != nil {
+			t.Fatalf("parse: %v", err)
+		}
+		text, err := executeResultTemplate(tmpl, data)
+		if err != nil {
+			t.Fatalf("execute: %v", err)
+		}
+		lines := strings.Split(text, "\n")
+		if len(lines) != 3 || lines[0] != "Batch check: 1 online, 1 failed" {
+			t.Fatalf("unexpected output:\n%s", text)
+		}
+		if !strings.HasPrefix(lines[2], "[ERR] down.example.com:19132") || !strings.HasSuffix(lines[2], "connection refused: refused") {
+			t.Fatalf("unexpected failure line %q", lines[2])
+		}
+		if hasANSI := strings.Contains(lines[1], "\x1b["); hasANSI != color {
+			t.Fatalf("color=%v but line is %q", color, lines[1])
+		}
+		if !color && lines[1] != "[OK]  hub.example.com:25565        1.21         4/20 42ms  Hello / World" {
+			t.Fatalf("unexpected OK line %q", lines[1])
+		}
+	}
+}
+
+func TestResultTemplateDataAndFallback(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+	dir, err := resultTemplatesDir()
+	if err != nil {
+		t.Fatalf("resultTemplatesDir: %v", err)
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	text := "{{range .Records}}{{.Host}} via {{.Details.SelectedIP}} in {{ms .Details.ResolveTime}}ms; {{end}}{{with .Metrics}}{{.BaseHost}} {{ms .Duration}}{{end}}"
+	if err := os.WriteFile(filepath.Join(dir, "chat.tmpl"), []byte(text), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "broken.tmpl"), []byte("{{.Nope"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	names, err := listResultTemplates()
+	if err != nil || strings.Join(names, ",") != "broken,chat" {
+		t.Fatalf("unexpected templates %v (%v)", names, err)
+	}
+
+	record := newExportRecord("lookup", ping.EditionJava, "hub.example.com", 25565, ping.JavaStatus{}, ping.ExecuteDetails{SelectedIP: "192.0.2.1", ResolveTime: 3 * time.Millisecond}, nil, nil)
+	data := newResultTemplateData("Lookup", "lookup", []exportRecord{record}, &lookupMetrics{BaseHost: "example.com", Duration: 2 * time.Second})
+	app := &App{settings: defaultSettings()}
+	app.settings.ResultTemplates = map[string]string{"lookup": "chat", "batch": "broken"}
+
+	display, export := app.templateResultText(data, "built-in", "built-in export")
+	want := "hub.example.com via 192.0.2.1 in 3ms; example.com 2000"
+	if display != want || export != want {
+		t.Fatalf("unexpected template output %q / %q", display, export)
+	}
+
+	data.Mode = "batch"
+	display, export = app.templateResultText(data, "built-in", "built-in export")
+	if !strings.HasPrefix(display, "built-in") || !strings.Contains(display, "Result template failed") || export != "built-in export" {
+		t.Fatalf("broken template should fall back: %q / %q", display, export)
+	}
+	data.Mode = "port_scan"
+	if display, _ := app.templateResultText(data, "built-in", ""); display != "built-in" {
+		t.Fatalf("modes without a template keep the built-in text, got %q", display)
+	}
+}
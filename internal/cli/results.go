@@ -3,6 +3,7 @@ package cli
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"UWP-TCP-Con/internal/ping"
 )
@@ -33,6 +34,9 @@ func formatDirectResult(result ping.Result, details ping.ExecuteDetails, options
 		} else if details.SRVError != "" {
 			builder.WriteString(fmt.Sprintf("SRV error: %s\n", details.SRVError))
 		}
+		if details.ResolveTime > 0 {
+			builder.WriteString(fmt.Sprintf("Resolve time: %s\n", details.ResolveTime.Round(time.Microsecond)))
+		}
 		if details.Attempts > 0 {
 			builder.WriteString(fmt.Sprintf("Attempts: %d\n", details.Attempts))
 		}
@@ -93,3 +97,49 @@ func formatMOTD(value string, options resultFormatOptions) string {
 	}
 	return ping.RenderFormattingANSI(value)
 }
+
+func formatFailureSection(counts map[ping.FailureClass]int, unresponsive []string) string {
+	var builder strings.Builder
+	if lines := formatFailureCounts(counts); len(lines) > 0 {
+		builder.WriteString("Failures\n")
+		for _, line := range lines {
+			builder.WriteString(fmt.Sprintf("- %s\n", line))
+		}
+		builder.WriteString("\n")
+	}
+	if len(unresponsive) > 0 {
+		builder.WriteString("Unresponsive\n")
+		for _, line := range unresponsive {
+			builder.WriteString(fmt.Sprintf("- %s\n", line))
+		}
+		builder.WriteString("\n")
+	}
+	return builder.String()
+}
+
+func formatFailureCounts(counts map[ping.FailureClass]int) []string {
+	lines := make([]string, 0, len(counts))
+	for _, class := range ping.FailureClasses {
+		if counts[class] == 0 {
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("%s: %d", failureClassTitle(class), counts[class]))
+	}
+	return lines
+}
+
+func failureClassTitle(class ping.FailureClass) string {
+	label := class.Label()
+	if label == "" {
+		return label
+	}
+	return strings.ToUpper(label[:1]) + label[1:]
+}
+
+func lookupUnresponsiveLines(failures []ping.LookupFailure) []string {
+	lines := make([]string, 0, len(failures))
+	for _, failure := range failures {
+		lines = append(lines, fmt.Sprintf("%s:%d (%s) %s", failure.Host, failure.Port, failure.Detail.SelectedIP, failure.Class.Label()))
+	}
+	return lines
+}
@@ -0,0 +1,459 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"UWP-TCP-Con/internal/ping"
+)
+
+const savedResultsLimit = 50
+
+type savedResult struct {
+	Path     string
+	Format   string
+	Modified time.Time
+	Title    string
+	SavedAt  time.Time
+	Mode     string
+	Records  int
+	Hits     int
+}
+
+func savedResultFormat(name string) string {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".json":
+		return exportFormatJSON
+	case ".csv":
+		return exportFormatCSV
+	case ".ndjson":
+		return exportFormatNDJSON
+	case ".md":
+		return exportFormatMarkdown
+	case ".html":
+		return exportFormatHTML
+	case ".prom":
+		return exportFormatPrometheus
+	case ".lp":
+		return exportFormatInflux
+	case ".txt":
+		return exportFormatText
+	default:
+		return ""
+	}
+}
+
+func (r savedResult) hasRecords() bool {
+	return r.Format == exportFormatJSON || r.Format == exportFormatCSV || r.Format == exportFormatNDJSON
+}
+
+func (r savedResult) reportKind() string {
+	switch r.Format {
+	case exportFormatMarkdown:
+		return "Markdown report"
+	case exportFormatHTML:
+		return "HTML report"
+	case exportFormatPrometheus:
+		return "Prometheus metrics"
+	case exportFormatInflux:
+		return "InfluxDB points"
+	default:
+		return "text report"
+	}
+}
+
+func (r savedResult) label() string {
+	title := r.Title
+	if title == "" {
+		title = filepath.Base(r.Path)
+	}
+	details := []string{r.SavedAt.Format("2006-01-02 15:04")}
+	if r.Mode != "" {
+		details = append(details, strings.ReplaceAll(r.Mode, "_", " "))
+	}
+	if r.Records >= 0 {
+		details = append(details, fmt.Sprintf("%d hits of %d", r.Hits, r.Records))
+	} else {
+		details = append(details, r.reportKind())
+	}
+	return fmt.Sprintf("%s: %s", title, strings.Join(details, ", "))
+}
+
+func (a *App) listSavedResults() ([]savedResult, error) {
+	dir := a.resultsDir()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	results := make([]savedResult, 0, len(entries))
+	for _, entry := range entries {
+		format := savedResultFormat(entry.Name())
+		if entry.IsDir() || format == "" || strings.HasPrefix(entry.Name(), "compare-") {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		results = append(results, savedResult{
+			Path:     filepath.Join(dir, entry.Name()),
+			Format:   format,
+			Modified: info.ModTime(),
+			SavedAt:  info.ModTime(),
+			Records:  -1,
+			Hits:     -1,
+		})
+	}
+	sort.Slice(results, func(i, j int) bool { return results[i].Modified.After(results[j].Modified) })
+	if len(results) > savedResultsLimit {
+		results = results[:savedResultsLimit]
+	}
+	for i := range results {
+		summarizeSavedResult(&results[i])
+	}
+	return results, nil
+}
+
+func summarizeSavedResult(result *savedResult) {
+	switch {
+	case result.Format == exportFormatHTML || result.Format == exportFormatPrometheus || result.Format == exportFormatInflux:
+		return
+	case !result.hasRecords():
+		result.Title, result.SavedAt = readTextExportHeader(result.Path, result.SavedAt)
+		return
+	}
+	payload, err := loadExportPayload(result.Path)
+	if err != nil {
+		result.Title = filepath.Base(result.Path) + " (unreadable)"
+		return
+	}
+	result.Title = payload.Title
+	if createdAt, err := time.Parse(time.RFC3339, payload.CreatedAt); err == nil {
+		result.SavedAt = createdAt
+	}
+	result.Mode = recordsMode(payload.Records)
+	result.Records = len(payload.Records)
+	result.Hits = 0
+	for _, record := range payload.Records {
+		if record.Success {
+			result.Hits++
+		}
+	}
+	if result.Title == "" {
+		result.Title = modeTitle(result.Mode)
+	}
+}
+
+func readTextExportHeader(path string, fallback time.Time) (string, time.Time) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", fallback
+	}
+	defer file.Close()
+	scanner := bufio.NewScanner(file)
+	title := ""
+	for lines := 0; lines < 40 && scanner.Scan(); lines++ {
+		line := strings.TrimSpace(scanner.Text())
+		if value, ok := strings.CutPrefix(line, "Saved at: "); ok {
+			if savedAt, err := time.Parse(time.RFC3339, strings.TrimSpace(value)); err == nil {
+				return title, savedAt
+			}
+			continue
+		}
+		if title == "" && line != "" {
+			title = strings.TrimPrefix(line, "# ")
+		}
+	}
+	return title, fallback
+}
+
+func recordsMode(records []exportRecord) string {
+	mode := ""
+	for _, record := range records {
+		switch {
+		case mode == "":
+			mode = record.Mode
+		case record.Mode != mode:
+			return "mixed"
+		}
+	}
+	return mode
+}
+
+func modeTitle(mode string) string {
+	switch mode {
+	case "lookup":
+		return "Lookup"
+	case "batch":
+		return "Batch check"
+	case "port_scan":
+		return "Port scan"
+	case "network_scan":
+		return "Network scan"
+	default:
+		return ""
+	}
+}
+
+func (a *App) browseSavedResults() error {
+	for {
+		results, err := a.listSavedResults()
+		if err != nil {
+			return err
+		}
+		if len(results) == 0 {
+			return renderTextPageAndWait("History", fmt.Sprintf("No saved results in %s.\nTurn on Save results in Settings to keep reports.", a.resultsDir()))
+		}
+		options := make([]string, 0, len(results)+1)
+		for _, result := range results {
+			options = append(options, result.label())
+		}
+		options = append(options, "Back")
+		index, err := selectOption("History", options)
+		if err != nil {
+			return err
+		}
+		if index == len(results) {
+			return nil
+		}
+		if err := a.manageSavedResult(results[index]); err != nil {
+			return err
+		}
+	}
+}
+
+func (a *App) manageSavedResult(result savedResult) error {
+	type action struct {
+		label string
+		run   func() error
+	}
+	actions := []action{{"Open: Show the saved report", func() error { return a.openSavedResult(result) }}}
+	if result.hasRecords() && result.Records > 0 {
+		actions = append(actions,
+			action{fmt.Sprintf("Re-run targets: Check the %d servers again", result.Records), func() error { return a.rerunSavedResult(result) }},
+			action{"Compare: Diff with another export", func() error { return a.compareSavedResult(result) }},
+			action{"Filter: Show matching servers", func() error { return a.filterSavedResult(result) }},
+		)
+	}
+	actions = append(actions, action{"Delete: Remove the file", func() error { return deleteSavedResult(result) }})
+
+	options := make([]string, 0, len(actions)+1)
+	for _, item := range actions {
+		options = append(options, item.label)
+	}
+	options = append(options, "Back")
+	index, err := selectOption(filepath.Base(result.Path), options)
+	if err != nil || index == len(actions) {
+		return err
+	}
+	return actions[index].run()
+}
+
+func (a *App) openSavedResult(result savedResult) error {
+	title := result.Title
+	if title == "" {
+		title = filepath.Base(result.Path)
+	}
+	if result.Format == exportFormatHTML {
+		return renderTextPageAndWait(title, fmt.Sprintf("HTML report\nFile: %s\n\nOpen the file in a browser to view it.", result.Path))
+	}
+	if !result.hasRecords() {
+		data, err := os.ReadFile(result.Path)
+		if err != nil {
+			return err
+		}
+		return renderTextPageAndWait(title, strings.TrimRight(string(data), "\n"))
+	}
+	payload, err := loadExportPayload(result.Path)
+	if err != nil {
+		return err
+	}
+	a.rememberResults(title, payload.Records)
+	return renderTextPageAndWait(title, formatSavedRecords(result, payload.Records))
+}
+
+func (a *App) filterSavedResult(result savedResult) error {
+	filterSource, err := askViewExpression("Filter results", lookupFilterPresets, "filter", `Expression: e.g. players > 10 && version ~ "1.20"`, validateFilterExpression)
+	if err != nil {
+		return err
+	}
+	sortSource, err := askViewExpression("Sort results", lookupSortPresets, "sort", "Sort keys: e.g. players desc, latency, host", validateSortExpression)
+	if err != nil {
+		return err
+	}
+	filter, err := parseResultFilter(filterSource)
+	if err != nil {
+		return err
+	}
+	order, err := parseResultSort(sortSource)
+	if err != nil {
+		return err
+	}
+	payload, err := loadExportPayload(result.Path)
+	if err != nil {
+		return err
+	}
+	records := filter.Apply(payload.Records)
+	order.Apply(records)
+	text := fmt.Sprintf("Filter: %s\nSort: %s\nMatching: %d of %d\n\n%s",
+		lookupFilterLabel(filter), lookupSortLabel(order), len(records), len(payload.Records), formatSavedRecords(result, records))
+	return renderTextPageAndWait("Filtered results", text)
+}
+
+func formatSavedRecords(result savedResult, records []exportRecord) string {
+	var builder strings.Builder
+	builder.WriteString("Summary\n")
+	builder.WriteString(fmt.Sprintf("- File: %s\n", result.Path))
+	builder.WriteString(fmt.Sprintf("- Saved: %s\n", result.SavedAt.Format("2006-01-02 15:04:05")))
+	if result.Mode != "" {
+		builder.WriteString(fmt.Sprintf("- Mode: %s\n", strings.ReplaceAll(result.Mode, "_", " ")))
+	}
+	online := 0
+	for _, record := range records {
+		if record.Success {
+			online++
+		}
+	}
+	builder.WriteString(fmt.Sprintf("- Records: %d\n", len(records)))
+	builder.WriteString(fmt.Sprintf("- Online: %d\n", online))
+	builder.WriteString(fmt.Sprintf("- Failed: %d\n", len(records)-online))
+
+	builder.WriteString("\nResults\n")
+	for _, record := range records {
+		target := fmt.Sprintf("%s:%d", record.Host, record.Port)
+		if record.Name != "" {
+			target = fmt.Sprintf("%s (%s)", record.Name, target)
+		}
+		if !record.Success {
+			builder.WriteString(fmt.Sprintf("[ERR] %s %s - %s\n", record.Edition, target, savedFailureText(record)))
+			continue
+		}
+		status := fmt.Sprintf("%s players %d/%d", record.Version, record.PlayersOnline, record.PlayersMax)
+		if record.LatencyMillis > 0 {
+			status += fmt.Sprintf(" latency %dms", record.LatencyMillis)
+		}
+		builder.WriteString(fmt.Sprintf("[OK]  %s %s - %s\n", record.Edition, target, strings.TrimSpace(status)))
+		if motd := recordCleanMOTD(record); motd != "" {
+			builder.WriteString(fmt.Sprintf("      %s\n", strings.ReplaceAll(ping.StripFormatting(motd), "\n", " / ")))
+		}
+	}
+	return strings.TrimRight(builder.String(), "\n")
+}
+
+func savedFailureText(record exportRecord) string {
+	label := ping.FailureClass(record.FailureClass).Label()
+	switch {
+	case record.FailureClass == "":
+		return record.Error
+	case record.Error == "":
+		return label
+	default:
+		return label + ": " + record.Error
+	}
+}
+
+func (a *App) rerunSavedResult(result savedResult) error {
+	payload, err := loadExportPayload(result.Path)
+	if err != nil {
+		return err
+	}
+	entries := make([]batchEntry, 0, len(payload.Records))
+	var parseErrors []string
+	seen := make(map[string]bool, len(payload.Records))
+	for i, record := range payload.Records {
+		key := diffRecordKey(record)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		entry, err := recordBatchEntry(record)
+		if err != nil {
+			parseErrors = append(parseErrors, fmt.Sprintf("record %d: %v", i+1, err))
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	if len(entries) == 0 {
+		return renderTextPageAndWait("Re-run targets", "No servers to check in this export.")
+	}
+	return a.runBatchSession(entries, parseErrors)
+}
+
+func (a *App) compareSavedResult(result savedResult) error {
+	results, err := a.listSavedResults()
+	if err != nil {
+		return err
+	}
+	var others []savedResult
+	for _, other := range results {
+		if other.Path != result.Path && other.hasRecords() {
+			others = append(others, other)
+		}
+	}
+	if len(others) == 0 {
+		return renderTextPageAndWait("Compare results", "No other JSON or CSV export to compare with.")
+	}
+	options := make([]string, 0, len(others)+1)
+	for _, other := range others {
+		options = append(options, other.label())
+	}
+	options = append(options, "Back")
+	index, err := selectOption("Compare with", options)
+	if err != nil || index == len(others) {
+		return err
+	}
+	older, newer := result, others[index]
+	if newer.SavedAt.Before(older.SavedAt) {
+		older, newer = newer, older
+	}
+	return a.showComparison(older.Path, newer.Path)
+}
+
+func deleteSavedResult(result savedResult) error {
+	ok, err := askConfirm(fmt.Sprintf("Delete %s?", filepath.Base(result.Path)))
+	if err != nil || !ok {
+		return err
+	}
+	return os.Remove(result.Path)
+}
+
+func (a *App) selectExportFile(title string) (string, error) {
+	results, err := a.listSavedResults()
+	if err != nil {
+		return "", err
+	}
+	var files []savedResult
+	for _, result := range results {
+		if result.hasRecords() {
+			files = append(files, result)
+		}
+	}
+	options := make([]string, 0, len(files)+2)
+	for _, file := range files {
+		options = append(options, file.label())
+	}
+	options = append(options, "Other file: Enter a path", "Back")
+	index, err := selectOption(title, options)
+	if err != nil {
+		return "", err
+	}
+	switch {
+	case index < len(files):
+		return files[index].Path, nil
+	case index == len(files)+1:
+		return "", nil
+	}
+	value, err := promptInput(title, "Path to a JSON or CSV export", "")
+	if err != nil {
+		return "", err
+	}
+	return strings.Trim(strings.TrimSpace(value), `"`), nil
+}
@@ -0,0 +1,80 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestListSavedResults(t *testing.T) {
+	dir := t.TempDir()
+	app := &App{settings: defaultSettings()}
+	app.settings.ResultsPath = dir
+	records := []exportRecord{
+		{Mode: "batch", Name: "Hub", Edition: "java", Host: "hub.example.com", Port: 25565, Success: true, Version: "1.21", PlayersOnline: 4, PlayersMax: 20,
+			ResolvedIPs: []string{"192.0.2.1", "192.0.2.2"}, Tags: []string{"eu"},
+			Probe: &exportProbe{TimeoutMillis: 3000, Retries: 1, SRV: true, IPMode: "auto", Kind: "status"}},
+		{Mode: "batch", Edition: "bedrock", Host: "down.example.com", Port: 19132, FailureClass: "connection_refused", Error: "refused"},
+	}
+	files := map[string]string{
+		"result-1.json": exportFormatJSON,
+		"result-2.csv":  exportFormatCSV,
+		"result-3.txt":  exportFormatText,
+		"result-4.md":   exportFormatMarkdown,
+	}
+	for name, format := range files {
+		if err := writeExport(filepath.Join(dir, name), format, "Batch check", "Summary", records); err != nil {
+			t.Fatalf("writeExport %s: %v", name, err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(dir, "compare-1.json"), []byte("{}"), 0o644); err != nil {
+		t.Fatalf("write compare: %v", err)
+	}
+	base := time.Now().Add(-time.Hour)
+	for i, name := range []string{"result-1.json", "result-2.csv", "result-3.txt", "result-4.md"} {
+		stamp := base.Add(time.Duration(i) * time.Minute)
+		if err := os.Chtimes(filepath.Join(dir, name), stamp, stamp); err != nil {
+			t.Fatalf("chtimes: %v", err)
+		}
+	}
+
+	results, err := app.listSavedResults()
+	if err != nil {
+		t.Fatalf("listSavedResults: %v", err)
+	}
+	if len(results) != 4 {
+		t.Fatalf("expected 4 saved results, got %#v", results)
+	}
+	if markdown := results[0]; markdown.Title != "Batch check" || markdown.Records != -1 || !strings.HasSuffix(markdown.label(), "Markdown report") {
+		t.Fatalf("unexpected Markdown result %#v", markdown)
+	}
+	results = results[1:]
+	if filepath.Base(results[0].Path) != "result-3.txt" || results[0].Title != "Batch check" || results[0].Records != -1 {
+		t.Fatalf("unexpected text result %#v", results[0])
+	}
+	csvResult := results[1]
+	if csvResult.Title != "Batch check" || csvResult.Mode != "batch" || csvResult.Records != 2 || csvResult.Hits != 1 {
+		t.Fatalf("unexpected CSV result %#v", csvResult)
+	}
+	if label := results[2].label(); !strings.HasPrefix(label, "Batch check: ") || !strings.HasSuffix(label, ", batch, 1 hits of 2") {
+		t.Fatalf("unexpected label %q", label)
+	}
+
+	payload, err := loadExportPayload(csvResult.Path)
+	if err != nil {
+		t.Fatalf("loadExportPayload csv: %v", err)
+	}
+	if !reflect.DeepEqual(payload.Records, records) {
+		t.Fatalf("CSV round trip mismatch:\n%#v\n%#v", payload.Records, records)
+	}
+
+	text := formatSavedRecords(csvResult, payload.Records)
+	for _, want := range []string{"- Online: 1", "[OK]  java Hub (hub.example.com:25565) - 1.21 players 4/20", "[ERR] bedrock down.example.com:19132 - connection refused: refused"} {
+		if !strings.Contains(text, want) {
+			t.Fatalf("expected %q in %q", want, text)
+		}
+	}
+}
@@ -0,0 +1,800 @@
+package cli
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"UWP-TCP-Con/internal/ping"
+	"UWP-TCP-Con/internal/web"
+)
+
+const (
+	defaultServeAddress = "127.0.0.1:8765"
+	apiJobRetention     = time.Hour
+	apiStreamInterval   = 250 * time.Millisecond
+	apiMaxBodyBytes     = 4 << 20
+)
+
+const (
+	apiJobRunning   = "running"
+	apiJobCompleted = "completed"
+	apiJobCanceled  = "canceled"
+	apiJobFailed    = "failed"
+)
+
+type apiOverrides struct {
+	TimeoutSeconds   *int         `json:"timeout_seconds,omitempty"`
+	Retries          *int         `json:"retries,omitempty"`
+	RetryDelayMillis *int         `json:"retry_delay_ms,omitempty"`
+	SRV              *bool        `json:"srv,omitempty"`
+	IPMode           *ping.IPMode `json:"ip_mode,omitempty"`
+	Concurrency      *int         `json:"concurrency,omitempty"`
+	Adaptive         *bool        `json:"adaptive,omitempty"`
+	RateLimit        *int         `json:"rate_limit,omitempty"`
+	Unresponsive     *bool        `json:"unresponsive,omitempty"`
+}
+
+type apiQueryRequest struct {
+	Edition ping.Edition `json:"edition"`
+	Host    string       `json:"host"`
+	Port    int          `json:"port,omitempty"`
+	apiOverrides
+}
+
+type apiLookupRequest struct {
+	Edition    ping.Edition `json:"edition"`
+	BaseHost   string       `json:"base_host,omitempty"`
+	Hosts      []string     `json:"hosts,omitempty"`
+	Subdomains []string     `json:"subdomains,omitempty"`
+	Endings    []string     `json:"endings,omitempty"`
+	Port       int          `json:"port,omitempty"`
+	Ports      []int        `json:"ports,omitempty"`
+	apiOverrides
+}
+
+type apiBatchEntry struct {
+	Name    string       `json:"name,omitempty"`
+	Edition ping.Edition `json:"edition,omitempty"`
+	Host    string       `json:"host"`
+	Port    int          `json:"port,omitempty"`
+}
+
+type apiBatchRequest struct {
+	Edition ping.Edition    `json:"edition,omitempty"`
+	Entries []apiBatchEntry `json:"entries"`
+	apiOverrides
+}
+
+type apiProgress struct {
+	Total     int    `json:"total"`
+	Completed int    `json:"completed"`
+	Workers   int    `json:"workers"`
+	Current   string `json:"current,omitempty"`
+}
+
+type apiJobStatus struct {
+	ID         string      `json:"id"`
+	Kind       string      `json:"kind"`
+	Status     string      `json:"status"`
+	CreatedAt  time.Time   `json:"created_at"`
+	FinishedAt *time.Time  `json:"finished_at,omitempty"`
+	Paused     bool        `json:"paused"`
+	Found      int         `json:"found"`
+	Progress   apiProgress `json:"progress"`
+	Error      string      `json:"error,omitempty"`
+}
+
+type apiEvent struct {
+	Type     string        `json:"type"`
+	Status   *apiJobStatus `json:"status,omitempty"`
+	Progress *apiProgress  `json:"progress,omitempty"`
+	Match    *exportRecord `json:"match,omitempty"`
+	Result   any           `json:"result,omitempty"`
+}
+
+type apiControlMessage struct {
+	Action string `json:"action"`
+}
+
+type apiLookupResult struct {
+	Matches      []exportRecord `json:"matches"`
+	Unresponsive []exportRecord `json:"unresponsive,omitempty"`
+	Attempts     int            `json:"attempts"`
+	Completed    int            `json:"completed"`
+	Failures     map[string]int `json:"failures,omitempty"`
+	DurationMS   int64          `json:"duration_ms"`
+	Canceled     bool           `json:"canceled"`
+}
+
+type apiBatchResult struct {
+	Results    []exportRecord `json:"results"`
+	DurationMS int64          `json:"duration_ms"`
+	Canceled   bool           `json:"canceled"`
+}
+
+type apiJob struct {
+	id        string
+	kind      string
+	createdAt time.Time
+	control   *spinnerControl
+	snapshot  func() apiProgress
+
+	mu         sync.Mutex
+	status     string
+	finishedAt time.Time
+	result     any
+	err        string
+	found      []exportRecord
+}
+
+type apiServer struct {
+	app   *App
+	token string
+	port  int
+
+	mu   sync.Mutex
+	jobs map[string]*apiJob
+}
+
+func newAPIServer(app *App, token string, port int) *apiServer {
+	return &apiServer{app: app, token: token, port: port, jobs: make(map[string]*apiJob)}
+}
+
+func (s *apiServer) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /health", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+	})
+	mux.HandleFunc("POST /query", s.handleQuery)
+	mux.HandleFunc("POST /lookups", s.handleLookup)
+	mux.HandleFunc("POST /batches", s.handleBatch)
+	mux.HandleFunc("GET /jobs", s.handleJobs)
+	for route, kind := range map[string]string{"lookups": "lookup", "batches": "batch"} {
+		mux.HandleFunc("GET /"+route+"/{id}", s.withJob(kind, s.handleJobStatus))
+		mux.HandleFunc("GET /"+route+"/{id}/result", s.withJob(kind, s.handleJobResult))
+		mux.HandleFunc("GET /"+route+"/{id}/events", s.withJob(kind, s.handleJobEvents))
+		mux.HandleFunc("DELETE /"+route+"/{id}", s.withJob(kind, s.handleJobCancel))
+		mux.HandleFunc("POST /"+route+"/{id}/cancel", s.withJob(kind, s.handleJobCancel))
+		mux.HandleFunc("POST /"+route+"/{id}/pause", s.withJob(kind, s.handleJobPause))
+		mux.HandleFunc("POST /"+route+"/{id}/resume", s.withJob(kind, s.handleJobResume))
+	}
+	if s.token == "" {
+		return web.LocalHostOnly(s.port, mux)
+	}
+	return s.authorize(mux)
+}
+
+func (s *apiServer) authorize(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/health" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		header := r.Header.Get("Authorization")
+		token, ok := strings.CutPrefix(header, "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(strings.TrimSpace(token)), []byte(s.token)) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="mcquery"`)
+			writeAPIError(w, http.StatusUnauthorized, fmt.Errorf("missing or invalid bearer token"))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *apiServer) handleQuery(w http.ResponseWriter, r *http.Request) {
+	var req apiQueryRequest
+	if !decodeAPIRequest(w, r, &req) {
+		return
+	}
+	settings, err := req.apiOverrides.Apply(s.app.settings)
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, err)
+		return
+	}
+	edition, err := apiEdition(req.Edition, ping.EditionJava)
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, err)
+		return
+	}
+	host := strings.TrimSpace(req.Host)
+	if host == "" {
+		writeAPIError(w, http.StatusBadRequest, fmt.Errorf("host is required"))
+		return
+	}
+	port, err := apiPort(req.Port, edition)
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, err)
+		return
+	}
+	options := settings.ExecuteOptions()
+	result, details, runErr := ping.Execute(r.Context(), ping.ExecuteConfig{
+		Edition:    edition,
+		Host:       host,
+		Port:       port,
+		Timeout:    options.Timeout,
+		RetryCount: options.RetryCount,
+		RetryDelay: options.RetryDelay,
+		EnableSRV:  options.EnableSRV,
+		IPMode:     options.IPMode,
+	})
+	writeJSON(w, http.StatusOK, newExportRecord("query", edition, host, port, result, details, nil, runErr))
+}
+
+func (s *apiServer) handleLookup(w http.ResponseWriter, r *http.Request) {
+	var req apiLookupRequest
+	if !decodeAPIRequest(w, r, &req) {
+		return
+	}
+	settings, err := req.apiOverrides.Apply(s.app.settings)
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, err)
+		return
+	}
+	config, err := req.lookupConfig(settings)
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	var progressMu sync.Mutex
+	var progress apiProgress
+	config.Progress = func(update ping.LookupProgress) {
+		progressMu.Lock()
+		progress = apiProgress{Total: update.Total, Completed: update.Completed, Workers: update.Workers, Current: fmt.Sprintf("%s:%d", update.Host, update.Port)}
+		progressMu.Unlock()
+	}
+	job := s.startJob("lookup", func() apiProgress {
+		progressMu.Lock()
+		defer progressMu.Unlock()
+		return progress
+	}, func(job *apiJob) (any, error) {
+		config.Paused = job.control.IsPaused
+		config.Found = func(match ping.LookupMatch) {
+			job.addFound(apiMatchRecord(config.Edition, match))
+		}
+		startedAt := time.Now()
+		result, err := ping.LookupDomains(job.control.Context(), config)
+		if err != nil && !errors.Is(err, context.Canceled) {
+			return nil, err
+		}
+		return newAPILookupResult(config.Edition, result, time.Since(startedAt), job.control.IsCancelled()), nil
+	})
+	writeJSON(w, http.StatusAccepted, job.Status())
+}
+
+func (s *apiServer) handleBatch(w http.ResponseWriter, r *http.Request) {
+	var req apiBatchRequest
+	if !decodeAPIRequest(w, r, &req) {
+		return
+	}
+	settings, err := req.apiOverrides.Apply(s.app.settings)
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, err)
+		return
+	}
+	defaultEdition, err := apiEdition(req.Edition, ping.EditionJava)
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, err)
+		return
+	}
+	if len(req.Entries) == 0 {
+		writeAPIError(w, http.StatusBadRequest, fmt.Errorf("entries cannot be empty"))
+		return
+	}
+	entries := make([]batchEntry, 0, len(req.Entries))
+	for i, item := range req.Entries {
+		edition, err := apiEdition(item.Edition, defaultEdition)
+		if err != nil {
+			writeAPIError(w, http.StatusBadRequest, fmt.Errorf("entry %d: %w", i+1, err))
+			return
+		}
+		host := strings.TrimSpace(item.Host)
+		if host == "" {
+			writeAPIError(w, http.StatusBadRequest, fmt.Errorf("entry %d: host is required", i+1))
+			return
+		}
+		port, err := apiPort(item.Port, edition)
+		if err != nil {
+			writeAPIError(w, http.StatusBadRequest, fmt.Errorf("entry %d: %w", i+1, err))
+			return
+		}
+		entries = append(entries, batchEntry{Name: item.Name, Edition: edition, Host: host, Port: port})
+	}
+
+	runner := &App{settings: settings}
+	progress := &batchProgress{total: len(entries)}
+	job := s.startJob("batch", func() apiProgress {
+		current, _ := progress.current.Load().(string)
+		return apiProgress{
+			Total:     progress.total,
+			Completed: int(progress.completed.Load()),
+			Workers:   int(progress.workers.Load()),
+			Current:   current,
+		}
+	}, func(job *apiJob) (any, error) {
+		startedAt := time.Now()
+		results := runner.runBatchEntries(job.control, entries, progress)
+		return apiBatchResult{
+			Results:    batchExportRecords("batch", results),
+			DurationMS: time.Since(startedAt).Milliseconds(),
+			Canceled:   job.control.IsCancelled(),
+		}, nil
+	})
+	writeJSON(w, http.StatusAccepted, job.Status())
+}
+
+func (s *apiServer) handleJobs(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	jobs := make([]*apiJob, 0, len(s.jobs))
+	for _, job := range s.jobs {
+		jobs = append(jobs, job)
+	}
+	s.mu.Unlock()
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].createdAt.Before(jobs[j].createdAt) })
+	statuses := make([]apiJobStatus, 0, len(jobs))
+	for _, job := range jobs {
+		statuses = append(statuses, job.Status())
+	}
+	writeJSON(w, http.StatusOK, statuses)
+}
+
+func (s *apiServer) handleJobStatus(w http.ResponseWriter, r *http.Request, job *apiJob) {
+	writeJSON(w, http.StatusOK, job.Status())
+}
+
+func (s *apiServer) handleJobResult(w http.ResponseWriter, r *http.Request, job *apiJob) {
+	job.mu.Lock()
+	status, result, errText := job.status, job.result, job.err
+	job.mu.Unlock()
+	switch {
+	case status == apiJobRunning:
+		writeJSON(w, http.StatusConflict, job.Status())
+	case result == nil:
+		writeAPIError(w, http.StatusInternalServerError, errors.New(errText))
+	default:
+		writeJSON(w, http.StatusOK, result)
+	}
+}
+
+func (s *apiServer) handleJobCancel(w http.ResponseWriter, r *http.Request, job *apiJob) {
+	job.control.Cancel()
+	writeJSON(w, http.StatusAccepted, job.Status())
+}
+
+func (s *apiServer) handleJobPause(w http.ResponseWriter, r *http.Request, job *apiJob) {
+	job.control.setPaused(true)
+	writeJSON(w, http.StatusOK, job.Status())
+}
+
+func (s *apiServer) handleJobResume(w http.ResponseWriter, r *http.Request, job *apiJob) {
+	job.control.setPaused(false)
+	writeJSON(w, http.StatusOK, job.Status())
+}
+
+func (s *apiServer) handleJobEvents(w http.ResponseWriter, r *http.Request, job *apiJob) {
+	if web.IsWebSocketRequest(r) {
+		s.streamJobWebSocket(w, r, job)
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeAPIError(w, http.StatusInternalServerError, fmt.Errorf("streaming not supported"))
+		return
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+	_ = streamJobEvents(r.Context(), job, func(event apiEvent) error {
+		data, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, data); err != nil {
+			return err
+		}
+		flusher.Flush()
+		return nil
+	})
+}
+
+func (s *apiServer) streamJobWebSocket(w http.ResponseWriter, r *http.Request, job *apiJob) {
+	conn, err := web.UpgradeWebSocket(w, r)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		defer cancel()
+		for {
+			op, payload, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			if op != web.OpText {
+				continue
+			}
+			applyJobAction(job, parseAPIControlMessage(payload))
+		}
+	}()
+	_ = streamJobEvents(ctx, job, func(event apiEvent) error {
+		data, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+		return conn.WriteText(data)
+	})
+}
+
+func streamJobEvents(ctx context.Context, job *apiJob, send func(event apiEvent) error) error {
+	status := job.Status()
+	if err := send(apiEvent{Type: "status", Status: &status}); err != nil {
+		return err
+	}
+	sentFound := 0
+	lastProgress := status.Progress
+	lastPaused := status.Paused
+	ticker := time.NewTicker(apiStreamInterval)
+	defer ticker.Stop()
+	for {
+		found := job.foundSince(sentFound)
+		for i := range found {
+			if err := send(apiEvent{Type: "match", Match: &found[i]}); err != nil {
+				return err
+			}
+		}
+		sentFound += len(found)
+
+		status := job.Status()
+		if status.Progress != lastProgress {
+			lastProgress = status.Progress
+			progress := status.Progress
+			if err := send(apiEvent{Type: "progress", Progress: &progress}); err != nil {
+				return err
+			}
+		}
+		if status.Paused != lastPaused {
+			lastPaused = status.Paused
+			if err := send(apiEvent{Type: "status", Status: &status}); err != nil {
+				return err
+			}
+		}
+		if status.Status != apiJobRunning {
+			job.mu.Lock()
+			result := job.result
+			job.mu.Unlock()
+			return send(apiEvent{Type: "done", Status: &status, Result: result})
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func parseAPIControlMessage(payload []byte) string {
+	var message apiControlMessage
+	if err := json.Unmarshal(payload, &message); err == nil && message.Action != "" {
+		return strings.ToLower(strings.TrimSpace(message.Action))
+	}
+	return strings.ToLower(strings.TrimSpace(string(payload)))
+}
+
+func applyJobAction(job *apiJob, action string) {
+	switch action {
+	case "pause":
+		job.control.setPaused(true)
+	case "resume":
+		job.control.setPaused(false)
+	case "toggle":
+		job.control.togglePause()
+	case "cancel", "abort":
+		job.control.Cancel()
+	}
+}
+
+func (s *apiServer) withJob(kind string, handler func(w http.ResponseWriter, r *http.Request, job *apiJob)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		job, ok := s.jobs[r.PathValue("id")]
+		s.mu.Unlock()
+		if !ok || job.kind != kind {
+			writeAPIError(w, http.StatusNotFound, fmt.Errorf("job not found"))
+			return
+		}
+		handler(w, r, job)
+	}
+}
+
+func (s *apiServer) startJob(kind string, snapshot func() apiProgress, run func(job *apiJob) (any, error)) *apiJob {
+	job := &apiJob{
+		id:        newAPIJobID(),
+		kind:      kind,
+		createdAt: time.Now(),
+		control:   newSpinnerControl(context.Background()),
+		snapshot:  snapshot,
+		status:    apiJobRunning,
+	}
+	s.mu.Lock()
+	s.pruneJobsLocked(job.createdAt)
+	s.jobs[job.id] = job
+	s.mu.Unlock()
+
+	go func() {
+		defer job.control.cancel()
+		result, err := run(job)
+		job.mu.Lock()
+		defer job.mu.Unlock()
+		job.finishedAt = time.Now()
+		job.result = result
+		switch {
+		case err != nil:
+			job.status = apiJobFailed
+			job.err = err.Error()
+		case job.control.IsCancelled():
+			job.status = apiJobCanceled
+		default:
+			job.status = apiJobCompleted
+		}
+	}()
+	return job
+}
+
+func (s *apiServer) pruneJobsLocked(now time.Time) {
+	for id, job := range s.jobs {
+		job.mu.Lock()
+		expired := job.status != apiJobRunning && now.Sub(job.finishedAt) > apiJobRetention
+		job.mu.Unlock()
+		if expired {
+			delete(s.jobs, id)
+		}
+	}
+}
+
+func (j *apiJob) addFound(record exportRecord) {
+	j.mu.Lock()
+	j.found = append(j.found, record)
+	j.mu.Unlock()
+}
+
+func (j *apiJob) foundSince(index int) []exportRecord {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if index >= len(j.found) {
+		return nil
+	}
+	return append([]exportRecord(nil), j.found[index:]...)
+}
+
+func (j *apiJob) Status() apiJobStatus {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	status := apiJobStatus{
+		ID:        j.id,
+		Kind:      j.kind,
+		Status:    j.status,
+		CreatedAt: j.createdAt,
+		Paused:    j.control.IsPaused(),
+		Found:     len(j.found),
+		Progress:  j.snapshot(),
+		Error:     j.err,
+	}
+	if !j.finishedAt.IsZero() {
+		finishedAt := j.finishedAt
+		status.FinishedAt = &finishedAt
+	}
+	return status
+}
+
+func (o apiOverrides) Apply(settings Settings) (Settings, error) {
+	if o.TimeoutSeconds != nil {
+		settings.RequestTimeoutSeconds = *o.TimeoutSeconds
+	}
+	if o.Retries != nil {
+		settings.RetryCount = *o.Retries
+	}
+	if o.RetryDelayMillis != nil {
+		settings.RetryDelayMillis = *o.RetryDelayMillis
+	}
+	if o.SRV != nil {
+		settings.EnableSRV = *o.SRV
+	}
+	if o.IPMode != nil {
+		settings.IPMode = *o.IPMode
+	}
+	if o.Concurrency != nil {
+		settings.LookupConcurrency = *o.Concurrency
+	}
+	if o.Adaptive != nil {
+		settings.AdaptiveConcurrency = *o.Adaptive
+	}
+	if o.RateLimit != nil {
+		settings.LookupRateLimit = *o.RateLimit
+	}
+	if o.Unresponsive != nil {
+		settings.ReportUnresponsive = *o.Unresponsive
+	}
+	if err := settings.Validate(); err != nil {
+		return settings, err
+	}
+	return settings, nil
+}
+
+func (r apiLookupRequest) lookupConfig(settings Settings) (ping.LookupConfig, error) {
+	edition, err := apiEdition(r.Edition, ping.EditionJava)
+	if err != nil {
+		return ping.LookupConfig{}, err
+	}
+	baseHost := strings.TrimSpace(r.BaseHost)
+	targets := make([]ping.LookupTarget, 0, len(r.Hosts))
+	for _, host := range r.Hosts {
+		if name := normalizeCandidateName(host); name != "" {
+			targets = append(targets, ping.LookupTarget{Host: name, Source: "api"})
+		}
+	}
+	if baseHost == "" && len(targets) == 0 {
+		return ping.LookupConfig{}, fmt.Errorf("base_host or hosts is required")
+	}
+	port := r.Port
+	if port == 0 && len(r.Ports) == 0 {
+		port = ping.DefaultPort(edition)
+	}
+	for _, value := range append([]int{port}, r.Ports...) {
+		if value < 0 || value > 65535 {
+			return ping.LookupConfig{}, fmt.Errorf("port %d out of range (1-65535)", value)
+		}
+	}
+	subdomains, endings := []string(nil), []string(nil)
+	if baseHost != "" {
+		presets, _ := loadLookupPresets()
+		subdomains = expandAPIList(r.Subdomains, subdomainPool, presets.Subdomains)
+		pool, _ := loadDomainEndings()
+		endings = expandAPIList(r.Endings, pool, presets.Endings)
+	}
+	return ping.LookupConfig{
+		Edition:       edition,
+		Port:          port,
+		Ports:         r.Ports,
+		BaseHost:      baseHost,
+		Targets:       targets,
+		Subdomains:    subdomains,
+		DomainEndings: endings,
+		Concurrency:   settings.LookupConcurrency,
+		Adaptive:      settings.AdaptiveConcurrency,
+		RateLimit:     settings.LookupRateLimit,
+		Options:       settings.ExecuteOptions(),
+		Unresponsive:  settings.ReportUnresponsive,
+	}, nil
+}
+
+func expandAPIList(values, pool, presets []string) []string {
+	expanded := make([]string, 0, len(values))
+	for _, value := range values {
+		switch strings.ToLower(strings.TrimSpace(value)) {
+		case "@pool":
+			expanded = append(expanded, pool...)
+		case "@presets":
+			expanded = append(expanded, presets...)
+		default:
+			expanded = append(expanded, value)
+		}
+	}
+	return expanded
+}
+
+func newAPILookupResult(edition ping.Edition, result ping.LookupResult, duration time.Duration, canceled bool) apiLookupResult {
+	response := apiLookupResult{
+		Matches:    make([]exportRecord, 0, len(result.Matches)),
+		Attempts:   result.Attempts,
+		Completed:  result.Completed,
+		DurationMS: duration.Milliseconds(),
+		Canceled:   canceled,
+	}
+	for _, match := range result.Matches {
+		response.Matches = append(response.Matches, apiMatchRecord(edition, match))
+	}
+	for _, failure := range result.Unresponsive {
+		record := newExportRecord("lookup", edition, failure.Host, failure.Port, nil, failure.Detail, nil, failure.Err)
+		record.Source = failure.Source
+		response.Unresponsive = append(response.Unresponsive, record)
+	}
+	if len(result.Failures) > 0 {
+		response.Failures = make(map[string]int, len(result.Failures))
+		for class, count := range result.Failures {
+			response.Failures[string(class)] = count
+		}
+	}
+	return response
+}
+
+func apiMatchRecord(edition ping.Edition, match ping.LookupMatch) exportRecord {
+	record := newExportRecord("lookup", edition, match.Host, match.Port, match.Result, match.Detail, nil, nil)
+	record.Source = match.Source
+	return record
+}
+
+func apiEdition(value, fallback ping.Edition) (ping.Edition, error) {
+	edition := ping.Edition(strings.ToLower(strings.TrimSpace(string(value))))
+	switch edition {
+	case "":
+		return fallback, nil
+	case ping.EditionJava, ping.EditionBedrock:
+		return edition, nil
+	default:
+		return "", fmt.Errorf("edition must be java or bedrock")
+	}
+}
+
+func apiPort(value int, edition ping.Edition) (int, error) {
+	if value == 0 {
+		return ping.DefaultPort(edition), nil
+	}
+	if value < 1 || value > 65535 {
+		return 0, fmt.Errorf("port out of range (1-65535)")
+	}
+	return value, nil
+}
+
+func decodeAPIRequest(w http.ResponseWriter, r *http.Request, target any) bool {
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil || mediaType != "application/json" {
+		writeAPIError(w, http.StatusUnsupportedMediaType, fmt.Errorf("request body must be application/json"))
+		return false
+	}
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, apiMaxBodyBytes))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(target); err != nil {
+		writeAPIError(w, http.StatusBadRequest, fmt.Errorf("invalid request body: %w", err))
+		return false
+	}
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, status int, value any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	_ = encoder.Encode(value)
+}
+
+func writeAPIError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+func newAPIJobID() string {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return fmt.Sprintf("%x", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(buf)
+}
+
+func isLoopbackAddress(address string) bool {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
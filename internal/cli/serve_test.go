@@ -0,0 +1,182 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAPIServerRequiresToken(t *testing.T) {
+	server := newAPIServer(&App{settings: defaultSettings()}, "secret", 8765)
+	handler := server.Handler()
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/query", strings.NewReader(`{"host":"example.com"}`)))
+	if recorder.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401, got %d", recorder.Code)
+	}
+
+	recorder = httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodPost, "/query", strings.NewReader(`{"host":"example.com","edition":"pocket"}`))
+	request.Header.Set("Authorization", "Bearer secret")
+	request.Header.Set("Content-Type", "application/json")
+	handler.ServeHTTP(recorder, request)
+	if recorder.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for invalid edition, got %d", recorder.Code)
+	}
+
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/health", nil))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected health without token, got %d", recorder.Code)
+	}
+}
+
+func TestAPIServerBatchJob(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	port := listener.Addr().(*net.TCPAddr).Port
+	listener.Close()
+
+	server := newAPIServer(&App{settings: defaultSettings()}, "", 8765)
+	handler := server.Handler()
+	body := `{"entries":[{"host":"127.0.0.1","port":` + strconv.Itoa(port) + `}],"timeout_seconds":2,"retries":0}`
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodPost, "http://127.0.0.1:8765/batches", strings.NewReader(body))
+	request.Header.Set("Content-Type", "application/json")
+	handler.ServeHTTP(recorder, request)
+	if recorder.Code != http.StatusAccepted {
+		t.Fatalf("expected 202, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+	var status apiJobStatus
+	if err := json.Unmarshal(recorder.Body.Bytes(), &status); err != nil {
+		t.Fatalf("decode status: %v", err)
+	}
+
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://127.0.0.1:8765/lookups/"+status.ID, nil))
+	if recorder.Code != http.StatusNotFound {
+		t.Fatalf("expected batch job to be hidden from /lookups, got %d", recorder.Code)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		recorder = httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://localhost:8765/batches/"+status.ID+"/result", nil))
+		if recorder.Code != http.StatusConflict {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("batch job did not finish")
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	var result apiBatchResult
+	if err := json.Unmarshal(recorder.Body.Bytes(), &result); err != nil {
+		t.Fatalf("decode result: %v", err)
+	}
+	if len(result.Results) != 1 || result.Results[0].Success || result.Results[0].FailureClass != "connection_refused" {
+		t.Fatalf("unexpected batch result: %+v", result)
+	}
+}
+
+func TestAPIServerRejectsForeignHostAndPlainBodies(t *testing.T) {
+	handler := newAPIServer(&App{settings: defaultSettings()}, "", 8765).Handler()
+
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodGet, "http://rebind.example.com:8765/jobs", nil)
+	handler.ServeHTTP(recorder, request)
+	if recorder.Code != http.StatusForbidden {
+		t.Fatalf("expected 403 for a foreign Host, got %d", recorder.Code)
+	}
+
+	recorder = httptest.NewRecorder()
+	request = httptest.NewRequest(http.MethodGet, "http://[::1]:8765/jobs", nil)
+	handler.ServeHTTP(recorder, request)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected 200 for [::1], got %d", recorder.Code)
+	}
+
+	recorder = httptest.NewRecorder()
+	request = httptest.NewRequest(http.MethodPost, "http://127.0.0.1:8765/query", strings.NewReader(`{"host":"example.com"}`))
+	request.Header.Set("Content-Type", "text/plain")
+	handler.ServeHTTP(recorder, request)
+	if recorder.Code != http.StatusUnsupportedMediaType {
+		t.Fatalf("expected 415 for a text/plain body, got %d", recorder.Code)
+	}
+}
+
+func TestAPIOverridesValidate(t *testing.T) {
+	negative := -1
+	if _, err := (apiOverrides{Retries: &negative}).Apply(defaultSettings()); err == nil {
+		t.Fatalf("expected negative retries to be rejected")
+	}
+}
+
+func TestAPIServerStreamsJobEvents(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	port := listener.Addr().(*net.TCPAddr).Port
+	listener.Close()
+
+	server := httptest.NewUnstartedServer(nil)
+	api := newAPIServer(&App{settings: defaultSettings()}, "", server.Listener.Addr().(*net.TCPAddr).Port)
+	server.Config.Handler = api.Handler()
+	server.Start()
+	defer server.Close()
+
+	body := `{"entries":[{"host":"127.0.0.1","port":` + strconv.Itoa(port) + `}],"timeout_seconds":2,"retries":0}`
+	response, err := http.Post(server.URL+"/batches", "application/json", strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("post batch: %v", err)
+	}
+	var status apiJobStatus
+	if err := json.NewDecoder(response.Body).Decode(&status); err != nil {
+		t.Fatalf("decode status: %v", err)
+	}
+	response.Body.Close()
+
+	stream, err := http.Get(server.URL + "/batches/" + status.ID + "/events")
+	if err != nil {
+		t.Fatalf("get events: %v", err)
+	}
+	defer stream.Body.Close()
+	if !strings.HasPrefix(stream.Header.Get("Content-Type"), "text/event-stream") {
+		t.Fatalf("unexpected content type %q", stream.Header.Get("Content-Type"))
+	}
+	data, err := io.ReadAll(stream.Body)
+	if err != nil {
+		t.Fatalf("read events: %v", err)
+	}
+	text := string(data)
+	if !strings.HasPrefix(text, "event: status\n") || !strings.Contains(text, "event: done\n") {
+		t.Fatalf("unexpected event stream:\n%s", text)
+	}
+}
+
+func TestApplyJobAction(t *testing.T) {
+	job := &apiJob{control: newSpinnerControl(context.Background())}
+	applyJobAction(job, parseAPIControlMessage([]byte(`{"action":"pause"}`)))
+	if !job.control.IsPaused() {
+		t.Fatalf("expected job to be paused")
+	}
+	applyJobAction(job, parseAPIControlMessage([]byte("resume")))
+	if job.control.IsPaused() {
+		t.Fatalf("expected job to resume")
+	}
+	applyJobAction(job, "cancel")
+	if !job.control.IsCancelled() || job.control.Context().Err() == nil {
+		t.Fatalf("expected job to be cancelled")
+	}
+}
@@ -0,0 +1,260 @@
+package cli
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"UWP-TCP-Con/internal/ping"
+)
+
+type serverListEntry struct {
+	Name string
+	Host string
+	Port int
+	Icon string
+}
+
+type serverListFormat struct {
+	Title       string
+	Edition     ping.Edition
+	DefaultPath func() string
+	Read        func(path string) ([]serverListEntry, error)
+	Write       func(path string, entries []serverListEntry, merge bool) (int, error)
+}
+
+type recentResults struct {
+	Title   string
+	Records []exportRecord
+}
+
+func (a *App) rememberResults(title string, records []exportRecord) {
+	a.recent = recentResults{Title: title, Records: records}
+}
+
+func importServerList(favorites []favorite, edition ping.Edition, entries []serverListEntry, now time.Time) ([]favorite, int, int) {
+	added, updated := 0, 0
+	for _, entry := range entries {
+		index := -1
+		for i, fav := range favorites {
+			if fav.Edition == edition && strings.EqualFold(fav.Host, entry.Host) && fav.Port == entry.Port {
+				index = i
+				break
+			}
+		}
+		if index >= 0 {
+			if favorites[index].Icon == "" && entry.Icon != "" {
+				favorites[index].Icon = entry.Icon
+				updated++
+			}
+			continue
+		}
+		favorites = append(favorites, favorite{
+			Name:      ping.StripFormatting(entry.Name),
+			Edition:   edition,
+			Host:      entry.Host,
+			Port:      entry.Port,
+			Icon:      entry.Icon,
+			CreatedAt: now.Format(time.RFC3339),
+		})
+		added++
+	}
+	return favorites, added, updated
+}
+
+func favoriteServerEntries(favorites []favorite, edition ping.Edition) []serverListEntry {
+	entries := make([]serverListEntry, 0, len(favorites))
+	for _, fav := range favorites {
+		if fav.Edition != edition {
+			continue
+		}
+		entries = append(entries, serverListEntry{Name: fav.Name, Host: fav.Host, Port: fav.Port, Icon: fav.Icon})
+	}
+	return entries
+}
+
+func recordServerEntries(records []exportRecord, edition ping.Edition) []serverListEntry {
+	entries := make([]serverListEntry, 0, len(records))
+	for _, record := range records {
+		if !record.Success || record.Edition != string(edition) {
+			continue
+		}
+		entry := serverListEntry{Name: record.Name, Host: record.Host, Port: record.Port}
+		if entry.Name == "" {
+			entry.Name = record.Host
+			if record.Port != ping.DefaultPort(edition) {
+				entry.Name = serverEntryAddress(entry)
+			}
+		}
+		if len(record.IconPNG) > 0 {
+			entry.Icon = base64.StdEncoding.EncodeToString(record.IconPNG)
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func serverEntryAddress(entry serverListEntry) string {
+	return net.JoinHostPort(entry.Host, strconv.Itoa(entry.Port))
+}
+
+func backupServerList(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	if err := os.WriteFile(path+"_old", data, 0o644); err != nil {
+		return fmt.Errorf("backup server list: %w", err)
+	}
+	return nil
+}
+
+func (a *App) importServerListFile(format serverListFormat) error {
+	title := "Import " + format.Title
+	path, err := askServerListPath(format, title)
+	if err != nil || path == "" {
+		return err
+	}
+	entries, err := format.Read(path)
+	if err != nil {
+		return renderTextPageAndWait(title, fmt.Sprintf("Import failed\nFile: %s\nError: %v", path, err))
+	}
+	favorites, err := loadFavorites()
+	if err != nil {
+		return err
+	}
+	favorites, added, updated := importServerList(favorites, format.Edition, entries, time.Now())
+	if err := saveFavorites(favorites); err != nil {
+		return err
+	}
+	summary := fmt.Sprintf("Import complete\nFile: %s\nServers in file: %d\nNew favorites: %d\nAlready saved: %d", path, len(entries), added, len(entries)-added)
+	if updated > 0 {
+		summary += fmt.Sprintf("\nIcons added: %d", updated)
+	}
+	return renderTextPageAndWait(title, summary)
+}
+
+func (a *App) exportServerListFile(format serverListFormat) error {
+	title := "Export " + format.Title
+	edition := editionLabel(format.Edition)
+	sources := []string{fmt.Sprintf("Favorites: Saved %s profiles", edition)}
+	if len(a.recent.Records) > 0 {
+		sources = append(sources, fmt.Sprintf("Recent results: %s", a.recent.Title))
+	}
+	sources = append(sources, "Back")
+	source, err := selectOption(title, sources)
+	if err != nil {
+		return err
+	}
+	var entries []serverListEntry
+	switch {
+	case source == len(sources)-1:
+		return nil
+	case source == 0:
+		favorites, err := loadFavorites()
+		if err != nil {
+			return err
+		}
+		entries = favoriteServerEntries(favorites, format.Edition)
+	default:
+		entries = recordServerEntries(a.recent.Records, format.Edition)
+	}
+	if len(entries) == 0 {
+		return renderTextPageAndWait(title, fmt.Sprintf("No online %s servers to export.", edition))
+	}
+
+	entries, err = selectServerEntries(entries)
+	if err != nil || len(entries) == 0 {
+		return err
+	}
+	path, err := askServerListPath(format, title)
+	if err != nil || path == "" {
+		return err
+	}
+	merge := false
+	if _, err := os.Stat(path); err == nil {
+		choice, err := selectOption("Existing "+format.Title, []string{
+			"Merge: Append new servers to the list",
+			"Replace: Write a new list",
+			"Back",
+		})
+		if err != nil {
+			return err
+		}
+		if choice == 2 {
+			return nil
+		}
+		merge = choice == 0
+	}
+	added, err := format.Write(path, entries, merge)
+	if err != nil {
+		return renderTextPageAndWait(title, fmt.Sprintf("Export failed\nFile: %s\nError: %v", path, err))
+	}
+	return renderTextPageAndWait(title, fmt.Sprintf(
+		"Export complete\nFile: %s\nServers written: %d\nAlready listed: %d",
+		path, added, len(entries)-added,
+	))
+}
+
+func editionLabel(edition ping.Edition) string {
+	if edition == ping.EditionJava {
+		return "Java"
+	}
+	return "Bedrock"
+}
+
+func askServerListPath(format serverListFormat, title string) (string, error) {
+	defaultPath := format.DefaultPath()
+	value, err := promptInput(title, fmt.Sprintf("Path to %s. Leave empty for %s", format.Title, defaultPath), "")
+	if err != nil {
+		return "", err
+	}
+	value = strings.Trim(strings.TrimSpace(value), `"`)
+	if value == "" {
+		value = defaultPath
+	}
+	return value, nil
+}
+
+func selectServerEntries(entries []serverListEntry) ([]serverListEntry, error) {
+	selected := make([]bool, len(entries))
+	for i := range selected {
+		selected[i] = true
+	}
+	cursor := 0
+	for {
+		count := 0
+		options := make([]string, 0, len(entries)+1)
+		for i, entry := range entries {
+			mark := "[ ]"
+			if selected[i] {
+				mark = "[x]"
+				count++
+			}
+			options = append(options, fmt.Sprintf("%s %s: %s", mark, ping.StripFormatting(entry.Name), serverEntryAddress(entry)))
+		}
+		options = append(options, fmt.Sprintf("Continue: Export %d servers", count))
+		index, err := selectOptionWithInitial("Select servers", options, cursor)
+		if err != nil {
+			return nil, err
+		}
+		if index == len(entries) {
+			chosen := make([]serverListEntry, 0, count)
+			for i, entry := range entries {
+				if selected[i] {
+					chosen = append(chosen, entry)
+				}
+			}
+			return chosen, nil
+		}
+		selected[index] = !selected[index]
+		cursor = index
+	}
+}
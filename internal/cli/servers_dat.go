@@ -0,0 +1,133 @@
+package cli
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"UWP-TCP-Con/internal/nbt"
+	"UWP-TCP-Con/internal/ping"
+)
+
+var serversDatFormat = serverListFormat{
+	Title:       "servers.dat",
+	Edition:     ping.EditionJava,
+	DefaultPath: defaultServersDatPath,
+	Read:        readServersDat,
+	Write:       writeServersDat,
+}
+
+func defaultServersDatPath() string {
+	switch runtime.GOOS {
+	case "windows":
+		if appData := os.Getenv("APPDATA"); appData != "" {
+			return filepath.Join(appData, ".minecraft", "servers.dat")
+		}
+	case "darwin":
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, "Library", "Application Support", "minecraft", "servers.dat")
+		}
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		return filepath.Join(home, ".minecraft", "servers.dat")
+	}
+	return "servers.dat"
+}
+
+func readServersDat(path string) ([]serverListEntry, error) {
+	doc, err := nbt.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	list, _ := doc.Root.GetList("servers")
+	entries := make([]serverListEntry, 0, len(list.Items))
+	for _, item := range list.Items {
+		server, ok := item.(nbt.Compound)
+		if !ok {
+			continue
+		}
+		address := strings.TrimSpace(server.GetString("ip"))
+		if address == "" {
+			continue
+		}
+		host, port, ok := splitHostPortLoose(address)
+		if !ok {
+			host, port = strings.Trim(address, "[]"), ping.DefaultPort(ping.EditionJava)
+		}
+		name := strings.TrimSpace(server.GetString("name"))
+		if name == "" {
+			name = address
+		}
+		entries = append(entries, serverListEntry{Name: name, Host: host, Port: port, Icon: server.GetString("icon")})
+	}
+	return entries, nil
+}
+
+func javaServerAddress(host string, port int) string {
+	if port == ping.DefaultPort(ping.EditionJava) {
+		return host
+	}
+	return net.JoinHostPort(host, strconv.Itoa(port))
+}
+
+func javaServerCompound(entry serverListEntry) nbt.Compound {
+	server := nbt.Compound{
+		{Name: "ip", Value: nbt.String(javaServerAddress(entry.Host, entry.Port))},
+		{Name: "name", Value: nbt.String(entry.Name)},
+	}
+	if entry.Icon != "" {
+		server.Set("icon", nbt.String(entry.Icon))
+	}
+	return server
+}
+
+func writeServersDat(path string, entries []serverListEntry, merge bool) (int, error) {
+	doc := nbt.Document{Root: nbt.Compound{}}
+	if merge {
+		existing, err := nbt.ReadFile(path)
+		if err != nil && !os.IsNotExist(err) {
+			return 0, err
+		}
+		if err == nil {
+			doc = existing
+		}
+	}
+	list, _ := doc.Root.GetList("servers")
+	list.Elem = nbt.TagCompound
+	known := make(map[string]bool, len(list.Items))
+	for _, item := range list.Items {
+		if server, ok := item.(nbt.Compound); ok {
+			known[javaServerKey(server.GetString("ip"))] = true
+		}
+	}
+	added := 0
+	for _, entry := range entries {
+		key := javaServerKey(javaServerAddress(entry.Host, entry.Port))
+		if known[key] {
+			continue
+		}
+		known[key] = true
+		list.Items = append(list.Items, javaServerCompound(entry))
+		added++
+	}
+	doc.Root.Set("servers", list)
+
+	if err := backupServerList(path); err != nil {
+		return 0, err
+	}
+	if err := nbt.WriteFile(path, doc); err != nil {
+		return 0, err
+	}
+	return added, nil
+}
+
+func javaServerKey(address string) string {
+	address = strings.ToLower(strings.TrimSpace(address))
+	if host, port, ok := splitHostPortLoose(address); ok {
+		return javaServerAddress(host, port)
+	}
+	return strings.Trim(address, "[]")
+}
@@ -0,0 +1,85 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"UWP-TCP-Con/internal/nbt"
+	"UWP-TCP-Con/internal/ping"
+)
+
+func TestServersDatRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "servers.dat")
+	if err := nbt.WriteFile(path, nbt.Document{Root: nbt.Compound{
+		{Name: "servers", Value: nbt.List{Elem: nbt.TagCompound, Items: []nbt.Value{
+			nbt.Compound{
+				{Name: "ip", Value: nbt.String("play.example.com")},
+				{Name: "name", Value: nbt.String("§aExample")},
+				{Name: "icon", Value: nbt.String("aWNvbg==")},
+				{Name: "acceptTextures", Value: nbt.Byte(1)},
+			},
+			nbt.Compound{
+				{Name: "ip", Value: nbt.String("[2001:db8::1]:25570")},
+				{Name: "name", Value: nbt.String("IPv6")},
+			},
+		}}},
+	}}); err != nil {
+		t.Fatalf("write fixture: %v", err)
+	}
+
+	entries, err := readServersDat(path)
+	if err != nil {
+		t.Fatalf("readServerList: %v", err)
+	}
+	if len(entries) != 2 || entries[0].Host != "play.example.com" || entries[0].Port != 25565 || entries[0].Icon != "aWNvbg==" {
+		t.Fatalf("unexpected entries: %+v", entries)
+	}
+	if entries[1].Host != "2001:db8::1" || entries[1].Port != 25570 {
+		t.Fatalf("unexpected IPv6 entry: %+v", entries[1])
+	}
+
+	existing := []favorite{{Name: "Saved", Edition: ping.EditionJava, Host: "PLAY.example.com", Port: 25565}}
+	favorites, added, updated := importServerList(existing, ping.EditionJava, entries, time.Unix(0, 0))
+	if added != 1 || updated != 1 || len(favorites) != 2 || favorites[0].Icon != "aWNvbg==" || favorites[1].Name != "IPv6" {
+		t.Fatalf("unexpected import: added=%d updated=%d %+v", added, updated, favorites)
+	}
+
+	written, err := writeServersDat(path, []serverListEntry{
+		{Name: "Dup", Host: "play.example.com", Port: 25565},
+		{Name: "New", Host: "new.example.com", Port: 25566},
+	}, true)
+	if err != nil {
+		t.Fatalf("writeServerList: %v", err)
+	}
+	if written != 1 {
+		t.Fatalf("expected one new server, got %d", written)
+	}
+	doc, err := nbt.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read merged: %v", err)
+	}
+	list, _ := doc.Root.GetList("servers")
+	if len(list.Items) != 3 {
+		t.Fatalf("expected 3 servers after merge, got %d", len(list.Items))
+	}
+	first := list.Items[0].(nbt.Compound)
+	if value, ok := first.Get("acceptTextures"); !ok || value != nbt.Byte(1) {
+		t.Fatalf("merge dropped existing fields: %#v", first)
+	}
+	if last := list.Items[2].(nbt.Compound); last.GetString("ip") != "new.example.com:25566" {
+		t.Fatalf("unexpected appended server: %#v", last)
+	}
+	if _, err := os.Stat(path + "_old"); err != nil {
+		t.Fatalf("expected backup file: %v", err)
+	}
+
+	if _, err := writeServersDat(path, []serverListEntry{{Name: "Only", Host: "only.example.com", Port: 25565}}, false); err != nil {
+		t.Fatalf("replace: %v", err)
+	}
+	entries, err = readServersDat(path)
+	if err != nil || len(entries) != 1 || entries[0].Name != "Only" {
+		t.Fatalf("unexpected replaced list: %+v, %v", entries, err)
+	}
+}
@@ -11,20 +11,28 @@ import (
 )
 
 type Settings struct {
-	RequestTimeoutSeconds int         `json:"request_timeout_seconds"`
-	RetryCount            int         `json:"retry_count"`
-	RetryDelayMillis      int         `json:"retry_delay_millis"`
-	EnableSRV             bool        `json:"enable_srv"`
-	IPMode                ping.IPMode `json:"ip_mode"`
-	LookupConcurrency     int         `json:"lookup_concurrency"`
-	LookupRateLimit       int         `json:"lookup_rate_limit"`
-	Verbose               bool        `json:"verbose"`
-	ColorMOTD             bool        `json:"color_motd"`
-	SaveResults           bool        `json:"save_results"`
-	ExportFormat          string      `json:"export_format"`
-	SaveJavaIcons         bool        `json:"save_java_icons"`
-	ResultsPath           string      `json:"results_path"`
-	CheckForUpdates       bool        `json:"check_for_updates"`
+	RequestTimeoutSeconds int               `json:"request_timeout_seconds"`
+	RetryCount            int               `json:"retry_count"`
+	RetryDelayMillis      int               `json:"retry_delay_millis"`
+	EnableSRV             bool              `json:"enable_srv"`
+	IPMode                ping.IPMode       `json:"ip_mode"`
+	LookupConcurrency     int               `json:"lookup_concurrency"`
+	AdaptiveConcurrency   bool              `json:"adaptive_concurrency"`
+	LookupRateLimit       int               `json:"lookup_rate_limit"`
+	LearnLookupOrder      bool              `json:"learn_lookup_order"`
+	ReportUnresponsive    bool              `json:"report_unresponsive"`
+	ScanMaxAddresses      int               `json:"scan_max_addresses"`
+	WatchIntervalSeconds  int               `json:"watch_interval_seconds"`
+	Verbose               bool              `json:"verbose"`
+	ColorMOTD             bool              `json:"color_motd"`
+	TerminalIcons         bool              `json:"terminal_icons"`
+	SaveResults           bool              `json:"save_results"`
+	ExportFormat          string            `json:"export_format"`
+	SaveJavaIcons         bool              `json:"save_java_icons"`
+	ResultsPath           string            `json:"results_path"`
+	CheckForUpdates       bool              `json:"check_for_updates"`
+	WebDashboard          bool              `json:"web_dashboard"`
+	ResultTemplates       map[string]string `json:"result_templates,omitempty"`
 }
 
 func defaultSettings() Settings {
@@ -35,14 +43,21 @@ func defaultSettings() Settings {
 		EnableSRV:             true,
 		IPMode:                ping.IPModeAuto,
 		LookupConcurrency:     0,
+		AdaptiveConcurrency:   true,
 		LookupRateLimit:       0,
+		LearnLookupOrder:      true,
+		ReportUnresponsive:    false,
+		ScanMaxAddresses:      65536,
+		WatchIntervalSeconds:  5,
 		Verbose:               false,
 		ColorMOTD:             true,
+		TerminalIcons:         true,
 		SaveResults:           false,
 		ExportFormat:          exportFormatText,
 		SaveJavaIcons:         true,
 		ResultsPath:           defaultResultsPath(),
 		CheckForUpdates:       false,
+		WebDashboard:          false,
 	}
 }
 
@@ -106,6 +121,23 @@ func (s Settings) RetryDelay() time.Duration {
 	return time.Duration(s.RetryDelayMillis) * time.Millisecond
 }
 
+func (s Settings) WatchInterval() time.Duration {
+	if s.WatchIntervalSeconds <= 0 {
+		return 5 * time.Second
+	}
+	return time.Duration(s.WatchIntervalSeconds) * time.Second
+}
+
+func (s Settings) ExecuteOptions() ping.ExecuteOptions {
+	return ping.ExecuteOptions{
+		Timeout:    s.RequestTimeout(),
+		RetryCount: s.RetryCount,
+		RetryDelay: s.RetryDelay(),
+		EnableSRV:  s.EnableSRV,
+		IPMode:     s.IPMode,
+	}
+}
+
 func (s Settings) Validate() error {
 	if s.RequestTimeoutSeconds < 0 {
 		return fmt.Errorf("request timeout cannot be negative")
@@ -122,6 +154,12 @@ func (s Settings) Validate() error {
 	if s.LookupRateLimit < 0 {
 		return fmt.Errorf("lookup rate limit cannot be negative")
 	}
+	if s.WatchIntervalSeconds < 1 {
+		return fmt.Errorf("watch interval must be at least 1 second")
+	}
+	if s.ScanMaxAddresses < 1 {
+		return fmt.Errorf("scan address cap must be at least 1")
+	}
 	if s.IPMode != ping.IPModeAuto && s.IPMode != ping.IPModeIPv4 && s.IPMode != ping.IPModeIPv6 {
 		return fmt.Errorf("invalid IP mode")
 	}
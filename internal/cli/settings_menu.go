@@ -20,15 +20,24 @@ func (a *App) manageSettings() error {
 			fmt.Sprintf("Java SRV lookup: %s", boolText(a.settings.EnableSRV)),
 			fmt.Sprintf("IP mode: %s", a.settings.IPMode),
 			fmt.Sprintf("Lookup workers: %s", lookupWorkerSettingText(a.settings.LookupConcurrency)),
+			fmt.Sprintf("Adaptive workers: %s", boolText(a.settings.AdaptiveConcurrency)),
 			fmt.Sprintf("Lookup rate cap: %s", settingRateLimitText(a.settings.LookupRateLimit)),
+			fmt.Sprintf("Learned ordering: %s", boolText(a.settings.LearnLookupOrder)),
+			fmt.Sprintf("Unresponsive hosts: %s", boolText(a.settings.ReportUnresponsive)),
+			fmt.Sprintf("Scan address cap: %d", a.settings.ScanMaxAddresses),
+			fmt.Sprintf("Watch interval: %d s", a.settings.WatchIntervalSeconds),
 			fmt.Sprintf("Verbose output: %s", boolText(a.settings.Verbose)),
 			fmt.Sprintf("Colored MOTD: %s", boolText(a.settings.ColorMOTD)),
+			fmt.Sprintf("Terminal icons: %s", boolText(a.settings.TerminalIcons)),
 			fmt.Sprintf("Save results: %s", boolText(a.settings.SaveResults)),
 			fmt.Sprintf("Export format: %s", a.settings.ExportFormat),
 			fmt.Sprintf("Save Java icons: %s", boolText(a.settings.SaveJavaIcons)),
 			fmt.Sprintf("Results path: %s", a.settings.ResultsPath),
 			fmt.Sprintf("Check for updates: %s", boolText(a.settings.CheckForUpdates)),
+			fmt.Sprintf("Web dashboard: %s", boolText(a.settings.WebDashboard)),
 			"Lookup presets: Subdomains and endings",
+			"Result templates: Custom result layouts per mode",
+			"Alerts: Test-fire channels",
 			"Reset settings: Restore defaults",
 			"Back",
 		}
@@ -74,42 +83,78 @@ func (a *App) manageSettings() error {
 			}
 			a.settings.LookupConcurrency = value
 		case 6:
+			value, err := askBoolValue("Adaptive workers", a.settings.AdaptiveConcurrency)
+			if err != nil {
+				return err
+			}
+			a.settings.AdaptiveConcurrency = value
+		case 7:
 			value, err := askIntValue("Lookup rate limit (req/s)", a.settings.LookupRateLimit)
 			if err != nil {
 				return err
 			}
 			a.settings.LookupRateLimit = value
-		case 7:
+		case 8:
+			value, err := askBoolValue("Learn lookup order from past hits", a.settings.LearnLookupOrder)
+			if err != nil {
+				return err
+			}
+			a.settings.LearnLookupOrder = value
+		case 9:
+			value, err := askBoolValue("List unresponsive hosts", a.settings.ReportUnresponsive)
+			if err != nil {
+				return err
+			}
+			a.settings.ReportUnresponsive = value
+		case 10:
+			value, err := askIntValue("Network scan address cap", a.settings.ScanMaxAddresses)
+			if err != nil {
+				return err
+			}
+			a.settings.ScanMaxAddresses = value
+		case 11:
+			value, err := askIntValue("Watch interval (seconds)", a.settings.WatchIntervalSeconds)
+			if err != nil {
+				return err
+			}
+			a.settings.WatchIntervalSeconds = value
+		case 12:
 			value, err := askBoolValue("Verbose output", a.settings.Verbose)
 			if err != nil {
 				return err
 			}
 			a.settings.Verbose = value
-		case 8:
+		case 13:
 			value, err := askBoolValue("Colored MOTD", a.settings.ColorMOTD)
 			if err != nil {
 				return err
 			}
 			a.settings.ColorMOTD = value
-		case 9:
+		case 14:
+			value, err := askBoolValue("Draw server icons in the terminal", a.settings.TerminalIcons)
+			if err != nil {
+				return err
+			}
+			a.settings.TerminalIcons = value
+		case 15:
 			value, err := askBoolValue("Save results", a.settings.SaveResults)
 			if err != nil {
 				return err
 			}
 			a.settings.SaveResults = value
-		case 10:
+		case 16:
 			value, err := askExportFormat(a.settings.ExportFormat)
 			if err != nil {
 				return err
 			}
 			a.settings.ExportFormat = value
-		case 11:
+		case 17:
 			value, err := askBoolValue("Save Java icons", a.settings.SaveJavaIcons)
 			if err != nil {
 				return err
 			}
 			a.settings.SaveJavaIcons = value
-		case 12:
+		case 18:
 			value, err := askTextValue("Results path", a.settings.ResultsPath)
 			if err != nil {
 				return err
@@ -118,18 +163,34 @@ func (a *App) manageSettings() error {
 				value = defaultResultsPath()
 			}
 			a.settings.ResultsPath = value
-		case 13:
+		case 19:
 			value, err := askBoolValue("Check for updates", a.settings.CheckForUpdates)
 			if err != nil {
 				return err
 			}
 			a.settings.CheckForUpdates = value
-		case 14:
+		case 20:
+			value, err := askBoolValue("Web dashboard", a.settings.WebDashboard)
+			if err != nil {
+				return err
+			}
+			a.settings.WebDashboard = value
+		case 21:
 			if err := a.manageLookupPresets(); err != nil {
 				return err
 			}
 			continue
-		case 15:
+		case 22:
+			if err := a.manageResultTemplates(); err != nil {
+				return err
+			}
+			continue
+		case 23:
+			if err := a.testFireAlerts(); err != nil {
+				return err
+			}
+			continue
+		case 24:
 			ok, err := askConfirm("Reset all settings?")
 			if err != nil {
 				return err
@@ -172,7 +233,7 @@ func settingRateLimitText(value int) string {
 }
 
 func askExportFormat(current string) (string, error) {
-	options := []string{"text", "json", "csv"}
+	options := []string{exportFormatText, exportFormatJSON, exportFormatCSV, exportFormatMarkdown, exportFormatHTML, exportFormatNDJSON, exportFormatPrometheus, exportFormatInflux}
 	initial := 0
 	for i, option := range options {
 		if option == normalizeExportFormat(current) {
@@ -48,6 +48,49 @@ func supportsColor() bool {
 	return os.Getenv("WT_SESSION") != "" || os.Getenv("ConEmuANSI") == "ON" || os.Getenv("ANSICON") != ""
 }
 
+type imageProtocol string
+
+const (
+	imageNone   imageProtocol = ""
+	imageBlocks imageProtocol = "blocks"
+	imageKitty  imageProtocol = "kitty"
+	imageITerm  imageProtocol = "iterm"
+	imageSixel  imageProtocol = "sixel"
+)
+
+func terminalImageProtocol() imageProtocol {
+	switch strings.ToLower(strings.TrimSpace(os.Getenv("MCQUERY_ICONS"))) {
+	case "off", "none", "0", "false":
+		return imageNone
+	case "blocks":
+		return imageBlocks
+	case "kitty":
+		return imageKitty
+	case "iterm":
+		return imageITerm
+	case "sixel":
+		return imageSixel
+	}
+	if !supportsColor() {
+		return imageNone
+	}
+	term := strings.ToLower(os.Getenv("TERM"))
+	program := os.Getenv("TERM_PROGRAM")
+	switch {
+	case os.Getenv("KITTY_WINDOW_ID") != "" || term == "xterm-kitty" || term == "xterm-ghostty" || program == "ghostty":
+		return imageKitty
+	case program == "iTerm.app" || program == "WezTerm" || os.Getenv("LC_TERMINAL") == "iTerm2":
+		return imageITerm
+	case strings.Contains(term, "sixel") || strings.HasPrefix(term, "foot") || term == "mlterm" || program == "mlterm":
+		return imageSixel
+	}
+	colorTerm := strings.ToLower(os.Getenv("COLORTERM"))
+	if colorTerm == "truecolor" || colorTerm == "24bit" || os.Getenv("WT_SESSION") != "" {
+		return imageBlocks
+	}
+	return imageNone
+}
+
 func style(text, color string) string {
 	if !supportsColor() || color == "" {
 		return text
@@ -244,17 +287,21 @@ func renderTextPageAndWait(title, content string) error {
 }
 
 func renderPage(title string, lines []string) {
+	renderFrame(title, pageBody(lines, contentWidth()))
+}
+
+func pageBody(lines []string, width int) []string {
 	body := make([]string, 0, len(lines))
 	for _, line := range lines {
 		if strings.TrimSpace(line) == "" {
 			body = append(body, "")
 			continue
 		}
-		for _, wrapped := range wrapDisplayLine(line, contentWidth()) {
+		for _, wrapped := range wrapDisplayLine(line, width) {
 			body = append(body, formatPageLine(wrapped))
 		}
 	}
-	renderFrame(title, body)
+	return body
 }
 
 func renderSpinnerPage(title, message, frame string) {
@@ -281,7 +328,14 @@ func drawFrame(lines []string) int {
 	if len(lines) == 0 {
 		lines = []string{""}
 	}
-	lines = fitFrameToViewport(lines)
+	overlay := pendingFrameImage
+	pendingFrameImage = nil
+	fitted := fitFrameToViewport(lines)
+	if len(fitted) < len(lines) && overlay != nil && overlay.row+iconRows >= len(fitted) {
+		overlay = nil
+	}
+	lines = fitted
+	clearFrameImages()
 	if frameReady {
 		moveCursorUp(activeFrameLines - 1)
 	} else {
@@ -311,11 +365,15 @@ func drawFrame(lines []string) int {
 		col = printableWidth(lines[len(lines)-1]) + 1
 	}
 	moveCursorColumn(clampInt(col, 1, terminalWidth()))
+	if overlay != nil {
+		drawFrameImage(*overlay, targetLine-1)
+	}
 	activeFrameLines = targetLine
 	return len(lines)
 }
 
 func clearCurrentFrame() {
+	clearFrameImages()
 	if !frameReady || activeFrameLines <= 0 {
 		return
 	}
@@ -353,9 +411,12 @@ func printableWidth(value string) int {
 	for _, r := range value {
 		switch escapeState {
 		case 1:
-			if r == '[' {
+			switch r {
+			case '[':
 				escapeState = 2
-			} else {
+			case ']', '_', 'P', '^', 'X':
+				escapeState = 3
+			default:
 				escapeState = 0
 			}
 			continue
@@ -364,6 +425,20 @@ func printableWidth(value string) int {
 				escapeState = 0
 			}
 			continue
+		case 3:
+			if r == '\a' {
+				escapeState = 0
+			} else if r == '\033' {
+				escapeState = 4
+			}
+			continue
+		case 4:
+			if r == '\\' {
+				escapeState = 0
+			} else {
+				escapeState = 3
+			}
+			continue
 		}
 		if r == '\033' {
 			escapeState = 1
@@ -610,11 +685,24 @@ func formatPageLine(line string) string {
 
 func isSectionTitle(value string) bool {
 	switch value {
-	case "Summary", "Details", "Server", "Players", "Performance", "Debug", "Skipped", "Results", "Matches", "Update", "Links":
+	case "Summary", "Details", "Server", "Players", "Performance", "Debug", "Skipped", "Results", "Matches", "Update", "Links", "Failures", "Unresponsive", "Servers", "Subdomains", "Endings", "Ports", "Versions":
 		return true
 	default:
-		return strings.HasPrefix(value, "Match ")
+		return strings.HasPrefix(value, "Match ") || isNumberedTitle(value, "Server ")
+	}
+}
+
+func isNumberedTitle(value, prefix string) bool {
+	number, ok := strings.CutPrefix(value, prefix)
+	if !ok || number == "" {
+		return false
+	}
+	for _, r := range number {
+		if r < '0' || r > '9' {
+			return false
+		}
 	}
+	return true
 }
 
 func formatBulletContent(value string) string {
@@ -784,6 +872,11 @@ type spinnerControl struct {
 	cancelled atomic.Bool
 }
 
+func newSpinnerControl(parent context.Context) *spinnerControl {
+	ctx, cancel := context.WithCancel(parent)
+	return &spinnerControl{ctx: ctx, cancel: cancel}
+}
+
 func (c *spinnerControl) Context() context.Context {
 	if c == nil {
 		return context.Background()
@@ -806,6 +899,13 @@ func (c *spinnerControl) togglePause() {
 	c.paused.Store(!c.paused.Load())
 }
 
+func (c *spinnerControl) setPaused(paused bool) {
+	if c == nil || c.IsCancelled() {
+		return
+	}
+	c.paused.Store(paused)
+}
+
 func (c *spinnerControl) Cancel() {
 	if c == nil {
 		return
@@ -816,9 +916,8 @@ func (c *spinnerControl) Cancel() {
 }
 
 func withControlledSpinner(title string, message func(frame int, control *spinnerControl) string, tick time.Duration, action func(control *spinnerControl) (string, error)) (string, error) {
-	ctx, cancel := context.WithCancel(context.Background())
-	control := &spinnerControl{ctx: ctx, cancel: cancel}
-	defer cancel()
+	control := newSpinnerControl(context.Background())
+	defer control.cancel()
 
 	resultCh := make(chan struct {
 		result string
@@ -975,6 +1074,10 @@ func formatLiveLine(line string) string {
 	switch {
 	case strings.HasPrefix(trimmed, "Controls:"):
 		return style(trimmed, colorDim)
+	case strings.HasPrefix(trimmed, "[UP]"):
+		return colorize(trimmed, colorGreen, colorBold)
+	case strings.HasPrefix(trimmed, "[DOWN]"):
+		return colorize(trimmed, colorRed, colorBold)
 	case strings.HasPrefix(trimmed, "Status:"):
 		value := strings.TrimSpace(strings.TrimPrefix(trimmed, "Status:"))
 		level := "success"
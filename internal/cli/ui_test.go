@@ -48,6 +48,36 @@ func TestPrintableWidthIgnoresANSISequences(t *testing.T) {
 	}
 }
 
+func TestPrintableWidthIgnoresGraphicsSequences(t *testing.T) {
+	value := "\033_Ga=T,f=100;AAAA\033\\icon \033]1337;File=inline=1:AAAA\a\033Pq#0!4~\033\\ok"
+
+	if got, want := printableWidth(value), len("icon ok"); got != want {
+		t.Fatalf("printableWidth() = %d, want %d", got, want)
+	}
+}
+
+func TestTerminalImageProtocol(t *testing.T) {
+	clearColorEnv(t)
+	t.Setenv("MCQUERY_ICONS", "")
+	t.Setenv("KITTY_WINDOW_ID", "")
+	t.Setenv("TERM_PROGRAM", "")
+	t.Setenv("LC_TERMINAL", "")
+	t.Setenv("COLORTERM", "")
+	t.Setenv("TERM", "xterm-kitty")
+	if got := terminalImageProtocol(); got != imageKitty {
+		t.Fatalf("expected kitty, got %q", got)
+	}
+	t.Setenv("TERM", "xterm-256color")
+	t.Setenv("COLORTERM", "truecolor")
+	if got := terminalImageProtocol(); got != imageBlocks {
+		t.Fatalf("expected blocks, got %q", got)
+	}
+	t.Setenv("MCQUERY_ICONS", "off")
+	if got := terminalImageProtocol(); got != imageNone {
+		t.Fatalf("expected icons to be disabled, got %q", got)
+	}
+}
+
 func clearColorEnv(t *testing.T) {
 	t.Helper()
 
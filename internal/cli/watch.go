@@ -0,0 +1,455 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"UWP-TCP-Con/internal/ping"
+)
+
+const (
+	watchHistorySize  = 40
+	watchChangeLimit  = 4
+	watchTickInterval = 250 * time.Millisecond
+)
+
+var sparklineLevels = []rune("▁▂▃▄▅▆▇█")
+
+type watchTarget struct {
+	Name    string
+	Edition ping.Edition
+	Host    string
+	Port    int
+}
+
+type watchSample struct {
+	At       time.Time
+	Up       bool
+	Latency  time.Duration
+	Players  int
+	Max      int
+	Version  string
+	Protocol string
+	MOTD     string
+	Err      error
+}
+
+type watchStateChange struct {
+	At time.Time
+	Up bool
+}
+
+type watchState struct {
+	Target      watchTarget
+	Samples     []watchSample
+	Changes     []watchStateChange
+	Checks      int
+	Fails       int
+	Transitions int
+}
+
+type watchBoard struct {
+	mu        sync.Mutex
+	states    []*watchState
+	interval  time.Duration
+	startedAt time.Time
+	rounds    int
+	lastRound time.Time
+	checking  bool
+	export    string
+	exportErr error
+}
+
+func (a *App) executeWatch() error {
+	targets, err := a.askWatchTargets()
+	if err != nil {
+		return err
+	}
+	if len(targets) == 0 {
+		return renderTextPageAndWait("Watch", "No favorites saved yet.")
+	}
+	return a.runWatch(targets)
+}
+
+func (a *App) askWatchTargets() ([]watchTarget, error) {
+	index, err := selectOption("Watch", []string{
+		"One server: Enter a host to watch",
+		"All favorites: Watch every saved profile",
+	})
+	if err != nil {
+		return nil, err
+	}
+	if index == 1 {
+		favorites, err := loadFavorites()
+		if err != nil {
+			return nil, err
+		}
+		targets := make([]watchTarget, 0, len(favorites))
+		for _, fav := range favorites {
+			targets = append(targets, watchTarget{Name: fav.Name, Edition: fav.Edition, Host: fav.Host, Port: fav.Port})
+		}
+		return targets, nil
+	}
+	config, err := a.collectDirectConfig()
+	if err != nil {
+		return nil, err
+	}
+	return []watchTarget{{
+		Name:    fmt.Sprintf("%s:%d", config.Host, config.Port),
+		Edition: config.Edition,
+		Host:    config.Host,
+		Port:    config.Port,
+	}}, nil
+}
+
+func (a *App) runWatch(targets []watchTarget) error {
+	board := newWatchBoard(targets, a.settings.WatchInterval())
+	resultText, err := withControlledSpinner("Watch", func(frame int, control *spinnerControl) string {
+		status := board.Render(time.Now())
+		if control.IsPaused() {
+			status = "Status: paused\n" + status
+		}
+		if control.IsCancelled() {
+			status = "Status: aborting\n" + status
+		}
+		return status
+	}, watchTickInterval, func(control *spinnerControl) (string, error) {
+		ctx := control.Context()
+		for {
+			if !control.IsPaused() {
+				a.watchRound(ctx, board)
+			}
+			select {
+			case <-ctx.Done():
+				return board.Summary(time.Now()), nil
+			case <-time.After(board.interval):
+			}
+		}
+	})
+	if err != nil {
+		return err
+	}
+	return renderTextPageAndWait("Watch", resultText)
+}
+
+func (a *App) watchRound(ctx context.Context, board *watchBoard) {
+	board.mu.Lock()
+	board.checking = true
+	states := append([]*watchState(nil), board.states...)
+	board.mu.Unlock()
+
+	var wg sync.WaitGroup
+	for _, state := range states {
+		wg.Add(1)
+		go func(state *watchState) {
+			defer wg.Done()
+			sample := a.watchProbe(ctx, state.Target)
+			if ctx.Err() != nil {
+				return
+			}
+			board.mu.Lock()
+			state.Record(sample)
+			board.mu.Unlock()
+		}(state)
+	}
+	wg.Wait()
+
+	board.mu.Lock()
+	board.checking = false
+	if ctx.Err() == nil {
+		board.rounds++
+		board.lastRound = time.Now()
+	}
+	board.mu.Unlock()
+	if ctx.Err() == nil {
+		a.exportWatchRound(board)
+	}
+}
+
+func (a *App) exportWatchRound(board *watchBoard) {
+	format := normalizeExportFormat(a.settings.ExportFormat)
+	if !a.settings.SaveResults || (format != exportFormatPrometheus && format != exportFormatInflux) {
+		return
+	}
+	board.mu.Lock()
+	records := board.Records()
+	at := board.lastRound
+	board.mu.Unlock()
+
+	path := a.watchExportPath(exportExtension(format))
+	var err error
+	if format == exportFormatPrometheus {
+		err = writeExport(path, format, "Watch", "", records)
+	} else {
+		err = appendInfluxExport(path, at, records)
+	}
+	board.mu.Lock()
+	board.export, board.exportErr = path, err
+	board.mu.Unlock()
+}
+
+func (a *App) watchExportPath(ext string) string {
+	if trimmed := strings.TrimSpace(a.settings.ResultsPath); strings.EqualFold(filepath.Ext(trimmed), "."+ext) {
+		return filepath.Clean(trimmed)
+	}
+	return filepath.Join(a.resultsDir(), "watch."+ext)
+}
+
+func (b *watchBoard) Records() []exportRecord {
+	records := make([]exportRecord, 0, len(b.states))
+	for _, state := range b.states {
+		last, ok := state.Last()
+		if !ok {
+			continue
+		}
+		record := exportRecord{
+			Mode:    "watch",
+			Name:    state.Target.Name,
+			Edition: string(state.Target.Edition),
+			Host:    state.Target.Host,
+			Port:    state.Target.Port,
+			Success: last.Up,
+		}
+		if last.Err != nil {
+			record.Error = last.Err.Error()
+			record.FailureClass = string(ping.ClassifyError(last.Err))
+		}
+		if last.Up {
+			record.Version = last.Version
+			record.Protocol = last.Protocol
+			record.PlayersOnline = last.Players
+			record.PlayersMax = last.Max
+			record.LatencyMillis = last.Latency.Milliseconds()
+			record.CleanMOTD = last.MOTD
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+func (a *App) watchProbe(ctx context.Context, target watchTarget) watchSample {
+	startedAt := time.Now()
+	result, _, err := ping.Execute(ctx, ping.ExecuteConfig{
+		Edition:    target.Edition,
+		Host:       target.Host,
+		Port:       target.Port,
+		Timeout:    a.settings.RequestTimeout(),
+		RetryCount: a.settings.RetryCount,
+		RetryDelay: a.settings.RetryDelay(),
+		EnableSRV:  a.settings.EnableSRV,
+		IPMode:     a.settings.IPMode,
+	})
+	sample := watchSample{At: time.Now(), Latency: time.Since(startedAt), Err: err}
+	if err != nil {
+		return sample
+	}
+	sample.Up = true
+	switch value := result.(type) {
+	case ping.BedrockPong:
+		sample.Players = parseCount(value.CurrentPlayers)
+		sample.Max = parseCount(value.MaxPlayers)
+		sample.Version = value.GameVersion
+		sample.Protocol = value.ProtocolVersion
+		sample.MOTD = value.CleanMOTD
+	case ping.JavaStatus:
+		sample.Players = value.CurrentPlayers
+		sample.Max = value.MaxPlayers
+		sample.Version = value.VersionName
+		sample.Protocol = strconv.Itoa(value.ProtocolVersion)
+		sample.MOTD = value.CleanMOTD
+		if value.LatencyMillis > 0 {
+			sample.Latency = time.Duration(value.LatencyMillis) * time.Millisecond
+		}
+	}
+	return sample
+}
+
+func newWatchBoard(targets []watchTarget, interval time.Duration) *watchBoard {
+	states := make([]*watchState, 0, len(targets))
+	for _, target := range targets {
+		states = append(states, &watchState{Target: target})
+	}
+	return &watchBoard{states: states, interval: interval, startedAt: time.Now()}
+}
+
+func (s *watchState) Record(sample watchSample) {
+	s.Checks++
+	if !sample.Up {
+		s.Fails++
+	}
+	last, ok := s.Last()
+	if ok && last.Up != sample.Up {
+		s.Transitions++
+	}
+	if !ok || last.Up != sample.Up {
+		s.Changes = append(s.Changes, watchStateChange{At: sample.At, Up: sample.Up})
+		if len(s.Changes) > watchChangeLimit {
+			s.Changes = s.Changes[len(s.Changes)-watchChangeLimit:]
+		}
+	}
+	s.Samples = append(s.Samples, sample)
+	if len(s.Samples) > watchHistorySize {
+		s.Samples = s.Samples[len(s.Samples)-watchHistorySize:]
+	}
+}
+
+func (s *watchState) Last() (watchSample, bool) {
+	if len(s.Samples) == 0 {
+		return watchSample{}, false
+	}
+	return s.Samples[len(s.Samples)-1], true
+}
+
+func (b *watchBoard) Render(now time.Time) string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	next := "checking"
+	if !b.checking && !b.lastRound.IsZero() {
+		remaining := b.interval - now.Sub(b.lastRound)
+		if remaining < 0 {
+			remaining = 0
+		}
+		next = fmt.Sprintf("in %s", remaining.Round(time.Second))
+	}
+	lines := []string{
+		fmt.Sprintf("Targets: %d | interval %s | rounds %d", len(b.states), b.interval, b.rounds),
+		fmt.Sprintf("Next check: %s", next),
+	}
+	switch {
+	case b.exportErr != nil:
+		lines = append(lines, fmt.Sprintf("Export failed: %v", b.exportErr))
+	case b.export != "":
+		lines = append(lines, fmt.Sprintf("Export: %s", b.export))
+	}
+	lines = append(lines, "")
+	for i, state := range b.states {
+		if i > 0 {
+			lines = append(lines, "")
+		}
+		lines = append(lines, state.Lines(now)...)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (b *watchBoard) Summary(now time.Time) string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var builder strings.Builder
+	builder.WriteString("Summary\n")
+	builder.WriteString(fmt.Sprintf("- Targets: %d\n", len(b.states)))
+	builder.WriteString(fmt.Sprintf("- Rounds: %d\n", b.rounds))
+	builder.WriteString(fmt.Sprintf("- Watched for: %s\n", formatLookupDuration(now.Sub(b.startedAt))))
+	if b.export != "" {
+		builder.WriteString(fmt.Sprintf("- Export: %s\n", b.export))
+	}
+	builder.WriteString("\nResults\n")
+	for _, state := range b.states {
+		uptime := 0.0
+		if state.Checks > 0 {
+			uptime = float64(state.Checks-state.Fails) / float64(state.Checks) * 100
+		}
+		builder.WriteString(fmt.Sprintf("- %s: %s, uptime %.1f%% over %d checks, %d state changes\n", state.Target.Name, state.StateLabel(), uptime, state.Checks, state.Transitions))
+	}
+	return strings.TrimRight(builder.String(), "\n")
+}
+
+func (s *watchState) StateLabel() string {
+	last, ok := s.Last()
+	switch {
+	case !ok:
+		return "pending"
+	case last.Up:
+		return "up"
+	default:
+		return "down"
+	}
+}
+
+func (s *watchState) Lines(now time.Time) []string {
+	header := fmt.Sprintf("%s (%s %s:%d)", s.Target.Name, s.Target.Edition, s.Target.Host, s.Target.Port)
+	last, ok := s.Last()
+	if !ok {
+		return []string{fmt.Sprintf("[....] %s", header), "State: waiting for first check"}
+	}
+
+	tag := "[UP]"
+	detail := fmt.Sprintf("%d/%d players, %s", last.Players, last.Max, last.Latency.Round(time.Millisecond))
+	if last.Version != "" {
+		detail += ", " + last.Version
+	}
+	if !last.Up {
+		tag = "[DOWN]"
+		detail = friendlyErrorMessage(last.Err)
+	}
+
+	latencies := make([]float64, len(s.Samples))
+	players := make([]float64, len(s.Samples))
+	for i, sample := range s.Samples {
+		latencies[i] = -1
+		players[i] = -1
+		if sample.Up {
+			latencies[i] = float64(sample.Latency.Milliseconds())
+			players[i] = float64(sample.Players)
+		}
+	}
+
+	lines := []string{
+		fmt.Sprintf("%s %s", tag, header),
+		fmt.Sprintf("Now: %s", detail),
+		fmt.Sprintf("Latency: %s", sparkline(latencies)),
+		fmt.Sprintf("Players: %s", sparkline(players)),
+	}
+	if len(s.Changes) > 0 {
+		current := s.Changes[len(s.Changes)-1]
+		changes := make([]string, 0, len(s.Changes))
+		for _, change := range s.Changes {
+			label := "down"
+			if change.Up {
+				label = "up"
+			}
+			changes = append(changes, fmt.Sprintf("%s %s", label, change.At.Format("15:04:05")))
+		}
+		lines = append(lines, fmt.Sprintf("State: %s for %s | %s", s.StateLabel(), formatLookupDuration(now.Sub(current.At)), strings.Join(changes, " -> ")))
+	}
+	return lines
+}
+
+func sparkline(values []float64) string {
+	if len(values) == 0 {
+		return ""
+	}
+	minValue, maxValue := 0.0, 0.0
+	found := false
+	for _, value := range values {
+		if value < 0 {
+			continue
+		}
+		if !found || value < minValue {
+			minValue = value
+		}
+		if !found || value > maxValue {
+			maxValue = value
+		}
+		found = true
+	}
+	runes := make([]rune, 0, len(values))
+	for _, value := range values {
+		if value < 0 {
+			runes = append(runes, '·')
+			continue
+		}
+		level := 0
+		if maxValue > minValue {
+			level = int((value - minValue) / (maxValue - minValue) * float64(len(sparklineLevels)-1))
+		}
+		runes = append(runes, sparklineLevels[level])
+	}
+	return string(runes)
+}
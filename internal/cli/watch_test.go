@@ -0,0 +1,79 @@
+package cli
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSparklineScalesAndMarksGaps(t *testing.T) {
+	got := sparkline([]float64{10, 20, -1, 80, 10})
+	if got != "▁▂·█▁" {
+		t.Fatalf("unexpected sparkline: %q", got)
+	}
+	if flat := sparkline([]float64{5, 5, 5}); flat != "▁▁▁" {
+		t.Fatalf("unexpected flat sparkline: %q", flat)
+	}
+}
+
+func TestWatchStateRecordsTransitions(t *testing.T) {
+	state := &watchState{Target: watchTarget{Name: "hub"}}
+	start := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	state.Record(watchSample{At: start, Up: true, Players: 3})
+	state.Record(watchSample{At: start.Add(5 * time.Second), Err: errors.New("timeout")})
+	state.Record(watchSample{At: start.Add(10 * time.Second), Err: errors.New("timeout")})
+	state.Record(watchSample{At: start.Add(15 * time.Second), Up: true, Players: 1})
+
+	if state.Checks != 4 || state.Fails != 2 || state.Transitions != 2 {
+		t.Fatalf("unexpected counters: checks=%d fails=%d transitions=%d", state.Checks, state.Fails, state.Transitions)
+	}
+	if len(state.Changes) != 3 || !state.Changes[2].At.Equal(start.Add(15*time.Second)) {
+		t.Fatalf("unexpected state changes: %+v", state.Changes)
+	}
+	if state.StateLabel() != "up" {
+		t.Fatalf("unexpected state: %s", state.StateLabel())
+	}
+}
+
+func TestWatchExportRoundReplacesPrometheusFile(t *testing.T) {
+	dir := t.TempDir()
+	app := &App{settings: defaultSettings()}
+	app.settings.ResultsPath = dir
+	app.settings.SaveResults = true
+	app.settings.ExportFormat = exportFormatPrometheus
+	board := newWatchBoard([]watchTarget{{Name: "hub", Edition: "java", Host: "hub.example.com", Port: 25565}}, time.Second)
+	for _, players := range []int{3, 5} {
+		board.states[0].Record(watchSample{At: time.Now(), Up: true, Players: players, Max: 20, Protocol: "767"})
+		app.exportWatchRound(board)
+	}
+	if board.exportErr != nil || board.export != filepath.Join(dir, "watch.prom") {
+		t.Fatalf("unexpected export state: %q %v", board.export, board.exportErr)
+	}
+	data, err := os.ReadFile(board.export)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if !strings.Contains(string(data), `mcquery_players_online{host="hub.example.com",edition="java",port="25565"} 5`) || strings.Contains(string(data), "} 3\n") {
+		t.Fatalf("unexpected metrics file:\n%s", data)
+	}
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 1 {
+		t.Fatalf("expected only watch.prom, got %d files", len(entries))
+	}
+}
+
+func TestWatchExportPathKeepsOtherResultFiles(t *testing.T) {
+	dir := t.TempDir()
+	app := &App{settings: defaultSettings()}
+	app.settings.ResultsPath = filepath.Join(dir, "results.json")
+	if got := app.watchExportPath("prom"); got != filepath.Join(dir, "watch.prom") {
+		t.Fatalf("expected watch.prom next to results.json, got %q", got)
+	}
+	app.settings.ResultsPath = filepath.Join(dir, "servers.lp")
+	if got := app.watchExportPath("lp"); got != app.settings.ResultsPath {
+		t.Fatalf("expected the configured .lp file, got %q", got)
+	}
+}
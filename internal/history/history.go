@@ -0,0 +1,419 @@
+package history
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const fileExtension = ".log"
+
+type Sample struct {
+	Time       time.Time
+	Count      int
+	UpCount    int
+	Players    int
+	MaxPlayers int
+	Latency    time.Duration
+	Version    string
+	MOTDHash   string
+	Failure    string
+}
+
+type Retention struct {
+	Raw    time.Duration
+	Keep   time.Duration
+	Bucket time.Duration
+}
+
+type Store struct {
+	dir string
+	mu  sync.Mutex
+}
+
+type VersionChange struct {
+	At   time.Time `json:"at"`
+	From string    `json:"from"`
+	To   string    `json:"to"`
+}
+
+type Summary struct {
+	Checks      int
+	Up          int
+	Uptime      float64
+	AvgLatency  time.Duration
+	PeakPlayers int
+	PeakAt      time.Time
+	FirstAt     time.Time
+	LastAt      time.Time
+	LastUp      bool
+	Version     string
+	Versions    []VersionChange
+	MOTDChanges int
+}
+
+func DefaultRetention() Retention {
+	return Retention{Raw: 48 * time.Hour, Keep: 90 * 24 * time.Hour, Bucket: 15 * time.Minute}
+}
+
+func Open(dir string) (*Store, error) {
+	if strings.TrimSpace(dir) == "" {
+		return nil, fmt.Errorf("history directory cannot be empty")
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &Store{dir: dir}, nil
+}
+
+func (s Sample) Up() bool {
+	return s.UpCount > 0 && s.UpCount*2 >= s.Count
+}
+
+func HashMOTD(value string) string {
+	if value == "" {
+		return ""
+	}
+	hash := fnv.New32a()
+	_, _ = hash.Write([]byte(value))
+	return fmt.Sprintf("%08x", hash.Sum32())
+}
+
+func Key(edition, host string, port int) string {
+	host = strings.ToLower(strings.TrimSpace(host))
+	var builder strings.Builder
+	for _, r := range host {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '.', r == '-':
+			builder.WriteRune(r)
+		default:
+			builder.WriteRune('_')
+		}
+	}
+	return fmt.Sprintf("%s_%s_%d", strings.ToLower(edition), builder.String(), port)
+}
+
+func (s *Store) Append(key string, sample Sample) error {
+	if sample.Count <= 0 {
+		sample.Count = 1
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	file, err := os.OpenFile(s.path(key), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	writer := csv.NewWriter(file)
+	if err := writer.Write(encodeSample(sample)); err != nil {
+		file.Close()
+		return err
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+func (s *Store) Read(key string, since time.Time) ([]Sample, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.readLocked(key, since)
+}
+
+func (s *Store) Keys() ([]string, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, fileExtension) {
+			continue
+		}
+		keys = append(keys, strings.TrimSuffix(name, fileExtension))
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+func (s *Store) Compact(key string, retention Retention, now time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	samples, err := s.readLocked(key, time.Time{})
+	if err != nil {
+		return err
+	}
+	compacted := Downsample(samples, retention, now)
+	if len(compacted) == len(samples) {
+		return nil
+	}
+
+	path := s.path(key)
+	tmp, err := os.CreateTemp(s.dir, filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	writer := csv.NewWriter(tmp)
+	for _, sample := range compacted {
+		if err := writer.Write(encodeSample(sample)); err != nil {
+			tmp.Close()
+			os.Remove(tmp.Name())
+			return err
+		}
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+func Downsample(samples []Sample, retention Retention, now time.Time) []Sample {
+	if retention.Bucket <= 0 {
+		retention.Bucket = DefaultRetention().Bucket
+	}
+	result := make([]Sample, 0, len(samples))
+	var bucket []Sample
+	var bucketStart time.Time
+	flush := func() {
+		if len(bucket) > 0 {
+			result = append(result, mergeSamples(bucket))
+			bucket = bucket[:0]
+		}
+	}
+	for _, sample := range samples {
+		age := now.Sub(sample.Time)
+		if retention.Keep > 0 && age > retention.Keep {
+			continue
+		}
+		if retention.Raw <= 0 || age <= retention.Raw {
+			flush()
+			result = append(result, sample)
+			continue
+		}
+		start := sample.Time.Truncate(retention.Bucket)
+		if len(bucket) > 0 && !start.Equal(bucketStart) {
+			flush()
+		}
+		bucketStart = start
+		bucket = append(bucket, sample)
+	}
+	flush()
+	return result
+}
+
+func Summarize(samples []Sample) Summary {
+	var summary Summary
+	var latencySum time.Duration
+	latencyCount := 0
+	lastMOTD := ""
+	for i, sample := range samples {
+		if i == 0 {
+			summary.FirstAt = sample.Time
+		}
+		summary.LastAt = sample.Time
+		summary.Checks += sample.Count
+		summary.Up += sample.UpCount
+		summary.LastUp = sample.Up()
+		if sample.UpCount == 0 {
+			continue
+		}
+		if sample.Latency > 0 {
+			latencySum += sample.Latency * time.Duration(sample.UpCount)
+			latencyCount += sample.UpCount
+		}
+		if sample.Players > summary.PeakPlayers || summary.PeakAt.IsZero() {
+			summary.PeakPlayers = sample.Players
+			summary.PeakAt = sample.Time
+		}
+		if sample.Version != "" && sample.Version != summary.Version {
+			if summary.Version != "" {
+				summary.Versions = append(summary.Versions, VersionChange{At: sample.Time, From: summary.Version, To: sample.Version})
+			}
+			summary.Version = sample.Version
+		}
+		if sample.MOTDHash != "" {
+			if lastMOTD != "" && sample.MOTDHash != lastMOTD {
+				summary.MOTDChanges++
+			}
+			lastMOTD = sample.MOTDHash
+		}
+	}
+	if summary.Checks > 0 {
+		summary.Uptime = float64(summary.Up) / float64(summary.Checks) * 100
+	}
+	if latencyCount > 0 {
+		summary.AvgLatency = latencySum / time.Duration(latencyCount)
+	}
+	return summary
+}
+
+func PlayerSeries(samples []Sample, from, to time.Time, buckets int) []float64 {
+	if buckets <= 0 {
+		return nil
+	}
+	series := make([]float64, buckets)
+	counts := make([]int, buckets)
+	for i := range series {
+		series[i] = -1
+	}
+	span := to.Sub(from)
+	if span <= 0 {
+		return series
+	}
+	for _, sample := range samples {
+		if sample.UpCount == 0 || sample.Time.Before(from) || sample.Time.After(to) {
+			continue
+		}
+		index := int(float64(sample.Time.Sub(from)) / float64(span) * float64(buckets))
+		if index >= buckets {
+			index = buckets - 1
+		}
+		if counts[index] == 0 {
+			series[index] = 0
+		}
+		series[index] += float64(sample.Players)
+		counts[index]++
+	}
+	for i, count := range counts {
+		if count > 0 {
+			series[i] /= float64(count)
+		}
+	}
+	return series
+}
+
+func (s *Store) path(key string) string {
+	return filepath.Join(s.dir, key+fileExtension)
+}
+
+func (s *Store) readLocked(key string, since time.Time) ([]Sample, error) {
+	file, err := os.Open(s.path(key))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(bufio.NewReader(file))
+	reader.FieldsPerRecord = -1
+	samples := make([]Sample, 0)
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) {
+				continue
+			}
+			return samples, err
+		}
+		sample, ok := decodeSample(record)
+		if !ok || sample.Time.Before(since) {
+			continue
+		}
+		samples = append(samples, sample)
+	}
+	return samples, nil
+}
+
+func encodeSample(sample Sample) []string {
+	return []string{
+		strconv.FormatInt(sample.Time.Unix(), 10),
+		strconv.Itoa(sample.Count),
+		strconv.Itoa(sample.UpCount),
+		strconv.Itoa(sample.Players),
+		strconv.Itoa(sample.MaxPlayers),
+		strconv.FormatInt(sample.Latency.Milliseconds(), 10),
+		sample.MOTDHash,
+		sample.Failure,
+		sample.Version,
+	}
+}
+
+func decodeSample(record []string) (Sample, bool) {
+	if len(record) < 9 {
+		return Sample{}, false
+	}
+	values := make([]int64, 6)
+	for i := range values {
+		value, err := strconv.ParseInt(record[i], 10, 64)
+		if err != nil {
+			return Sample{}, false
+		}
+		values[i] = value
+	}
+	return Sample{
+		Time:       time.Unix(values[0], 0),
+		Count:      int(values[1]),
+		UpCount:    int(values[2]),
+		Players:    int(values[3]),
+		MaxPlayers: int(values[4]),
+		Latency:    time.Duration(values[5]) * time.Millisecond,
+		MOTDHash:   record[6],
+		Failure:    record[7],
+		Version:    record[8],
+	}, true
+}
+
+func mergeSamples(samples []Sample) Sample {
+	merged := Sample{Time: samples[0].Time.Truncate(time.Second)}
+	var latencySum time.Duration
+	latencyCount := 0
+	playerSum := 0
+	for _, sample := range samples {
+		merged.Count += sample.Count
+		merged.UpCount += sample.UpCount
+		if sample.UpCount > 0 {
+			playerSum += sample.Players * sample.UpCount
+			if sample.Latency > 0 {
+				latencySum += sample.Latency * time.Duration(sample.UpCount)
+				latencyCount += sample.UpCount
+			}
+		}
+		if sample.MaxPlayers > merged.MaxPlayers {
+			merged.MaxPlayers = sample.MaxPlayers
+		}
+		if sample.Version != "" {
+			merged.Version = sample.Version
+		}
+		if sample.MOTDHash != "" {
+			merged.MOTDHash = sample.MOTDHash
+		}
+		if sample.Failure != "" {
+			merged.Failure = sample.Failure
+		}
+	}
+	if merged.UpCount > 0 {
+		merged.Players = playerSum / merged.UpCount
+	}
+	if latencyCount > 0 {
+		merged.Latency = latencySum / time.Duration(latencyCount)
+	}
+	return merged
+}
@@ -0,0 +1,89 @@
+package history
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStoreAppendAndRead(t *testing.T) {
+	store, err := Open(t.TempDir())
+	if err != nil {
+		t.Fatalf("Open returned error: %v", err)
+	}
+	key := Key("java", "Play.Example.com", 25565)
+	start := time.Unix(1_700_000_000, 0)
+	samples := []Sample{
+		{Time: start, UpCount: 1, Players: 4, MaxPlayers: 20, Latency: 40 * time.Millisecond, Version: "Paper 1.20.4", MOTDHash: HashMOTD("Welcome")},
+		{Time: start.Add(time.Minute), Failure: "read_timeout"},
+	}
+	for _, sample := range samples {
+		if err := store.Append(key, sample); err != nil {
+			t.Fatalf("Append returned error: %v", err)
+		}
+	}
+
+	got, err := store.Read(key, time.Time{})
+	if err != nil {
+		t.Fatalf("Read returned error: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("expected 2 samples, got %d", len(got))
+	}
+	if !got[0].Up() || got[0].Version != "Paper 1.20.4" || got[0].Latency != 40*time.Millisecond || got[0].Count != 1 {
+		t.Fatalf("unexpected first sample: %+v", got[0])
+	}
+	if got[1].Up() || got[1].Failure != "read_timeout" {
+		t.Fatalf("unexpected second sample: %+v", got[1])
+	}
+	keys, err := store.Keys()
+	if err != nil || len(keys) != 1 || keys[0] != "java_play.example.com_25565" {
+		t.Fatalf("unexpected keys: %v (%v)", keys, err)
+	}
+}
+
+func TestDownsampleMergesOldSamplesAndDropsExpired(t *testing.T) {
+	now := time.Date(2024, 6, 10, 12, 0, 0, 0, time.UTC)
+	retention := Retention{Raw: time.Hour, Keep: 24 * time.Hour, Bucket: 15 * time.Minute}
+	old := now.Add(-3 * time.Hour).Truncate(15 * time.Minute)
+	samples := []Sample{
+		{Time: now.Add(-48 * time.Hour), Count: 1, UpCount: 1},
+		{Time: old, Count: 1, UpCount: 1, Players: 10, Latency: 20 * time.Millisecond},
+		{Time: old.Add(5 * time.Minute), Count: 1, UpCount: 1, Players: 20, Latency: 40 * time.Millisecond},
+		{Time: old.Add(10 * time.Minute), Count: 1},
+		{Time: now.Add(-10 * time.Minute), Count: 1, UpCount: 1, Players: 3},
+	}
+
+	got := Downsample(samples, retention, now)
+	if len(got) != 2 {
+		t.Fatalf("expected one bucket and one raw sample, got %+v", got)
+	}
+	bucket := got[0]
+	if bucket.Count != 3 || bucket.UpCount != 2 || bucket.Players != 15 || bucket.Latency != 30*time.Millisecond {
+		t.Fatalf("unexpected merged bucket: %+v", bucket)
+	}
+	if got[1].Players != 3 {
+		t.Fatalf("expected raw sample to stay untouched, got %+v", got[1])
+	}
+}
+
+func TestSummarizeReportsUptimeAndVersionChanges(t *testing.T) {
+	start := time.Unix(1_700_000_000, 0)
+	summary := Summarize([]Sample{
+		{Time: start, Count: 1, UpCount: 1, Players: 2, Version: "1.20.4", MOTDHash: "aa"},
+		{Time: start.Add(time.Minute), Count: 1},
+		{Time: start.Add(2 * time.Minute), Count: 1, UpCount: 1, Players: 9, Version: "1.21", MOTDHash: "bb"},
+		{Time: start.Add(3 * time.Minute), Count: 1, UpCount: 1, Players: 5, Version: "1.21", MOTDHash: "bb"},
+	})
+	if summary.Checks != 4 || summary.Up != 3 || summary.Uptime != 75 {
+		t.Fatalf("unexpected uptime: %+v", summary)
+	}
+	if summary.PeakPlayers != 9 || !summary.PeakAt.Equal(start.Add(2*time.Minute)) {
+		t.Fatalf("unexpected peak: %d at %s", summary.PeakPlayers, summary.PeakAt)
+	}
+	if len(summary.Versions) != 1 || summary.Versions[0].From != "1.20.4" || summary.Versions[0].To != "1.21" {
+		t.Fatalf("unexpected version changes: %+v", summary.Versions)
+	}
+	if summary.MOTDChanges != 1 || !summary.LastUp {
+		t.Fatalf("unexpected MOTD changes or last state: %+v", summary)
+	}
+}
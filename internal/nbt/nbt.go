@@ -0,0 +1,150 @@
+package nbt
+
+import (
+	"fmt"
+)
+
+type Type byte
+
+const (
+	TagEnd Type = iota
+	TagByte
+	TagShort
+	TagInt
+	TagLong
+	TagFloat
+	TagDouble
+	TagByteArray
+	TagString
+	TagList
+	TagCompound
+	TagIntArray
+	TagLongArray
+)
+
+func (t Type) String() string {
+	switch t {
+	case TagEnd:
+		return "TAG_End"
+	case TagByte:
+		return "TAG_Byte"
+	case TagShort:
+		return "TAG_Short"
+	case TagInt:
+		return "TAG_Int"
+	case TagLong:
+		return "TAG_Long"
+	case TagFloat:
+		return "TAG_Float"
+	case TagDouble:
+		return "TAG_Double"
+	case TagByteArray:
+		return "TAG_Byte_Array"
+	case TagString:
+		return "TAG_String"
+	case TagList:
+		return "TAG_List"
+	case TagCompound:
+		return "TAG_Compound"
+	case TagIntArray:
+		return "TAG_Int_Array"
+	case TagLongArray:
+		return "TAG_Long_Array"
+	default:
+		return fmt.Sprintf("TAG_Unknown(%d)", byte(t))
+	}
+}
+
+type Value interface {
+	Type() Type
+}
+
+type (
+	Byte      int8
+	Short     int16
+	Int       int32
+	Long      int64
+	Float     float32
+	Double    float64
+	ByteArray []byte
+	String    string
+	IntArray  []int32
+	LongArray []int64
+)
+
+type List struct {
+	Elem  Type
+	Items []Value
+}
+
+type Field struct {
+	Name  string
+	Value Value
+}
+
+type Compound []Field
+
+func (Byte) Type() Type      { return TagByte }
+func (Short) Type() Type     { return TagShort }
+func (Int) Type() Type       { return TagInt }
+func (Long) Type() Type      { return TagLong }
+func (Float) Type() Type     { return TagFloat }
+func (Double) Type() Type    { return TagDouble }
+func (ByteArray) Type() Type { return TagByteArray }
+func (String) Type() Type    { return TagString }
+func (List) Type() Type      { return TagList }
+func (Compound) Type() Type  { return TagCompound }
+func (IntArray) Type() Type  { return TagIntArray }
+func (LongArray) Type() Type { return TagLongArray }
+
+func (c Compound) Get(name string) (Value, bool) {
+	for _, field := range c {
+		if field.Name == name {
+			return field.Value, true
+		}
+	}
+	return nil, false
+}
+
+func (c Compound) GetString(name string) string {
+	value, _ := c.Get(name)
+	text, _ := value.(String)
+	return string(text)
+}
+
+func (c Compound) GetCompound(name string) (Compound, bool) {
+	value, _ := c.Get(name)
+	compound, ok := value.(Compound)
+	return compound, ok
+}
+
+func (c Compound) GetList(name string) (List, bool) {
+	value, _ := c.Get(name)
+	list, ok := value.(List)
+	return list, ok
+}
+
+func (c *Compound) Set(name string, value Value) {
+	for i, field := range *c {
+		if field.Name == name {
+			(*c)[i].Value = value
+			return
+		}
+	}
+	*c = append(*c, Field{Name: name, Value: value})
+}
+
+func (c *Compound) Delete(name string) {
+	for i, field := range *c {
+		if field.Name == name {
+			*c = append((*c)[:i], (*c)[i+1:]...)
+			return
+		}
+	}
+}
+
+type Document struct {
+	Name       string
+	Root       Compound
+	Compressed bool
+}
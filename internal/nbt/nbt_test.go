@@ -0,0 +1,120 @@
+package nbt
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func allTypesDocument() Document {
+	return Document{Name: "all", Root: Compound{
+		{Name: "byte", Value: Byte(-5)},
+		{Name: "short", Value: Short(-1234)},
+		{Name: "int", Value: Int(123456789)},
+		{Name: "long", Value: Long(-9007199254740993)},
+		{Name: "float", Value: Float(0.5)},
+		{Name: "double", Value: Double(3.25)},
+		{Name: "bytes", Value: ByteArray{0x00, 0x7f, 0xff}},
+		{Name: "string", Value: String("nul\x00 and \U0001F600")},
+		{Name: "empty", Value: List{Elem: TagEnd, Items: []Value{}}},
+		{Name: "ints", Value: List{Elem: TagInt, Items: []Value{Int(1), Int(-1)}}},
+		{Name: "nested", Value: Compound{{Name: "k", Value: String("v")}}},
+		{Name: "intarray", Value: IntArray{7, -7}},
+		{Name: "longarray", Value: LongArray{1 << 40}},
+	}}
+}
+
+func readGolden(t *testing.T, name string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatalf("read golden: %v", err)
+	}
+	return data
+}
+
+func TestGoldenRoundTrip(t *testing.T) {
+	for _, name := range []string{"hello_world.nbt", "servers.dat", "all_types.nbt"} {
+		golden := readGolden(t, name)
+		doc, err := Decode(bytes.NewReader(golden))
+		if err != nil {
+			t.Fatalf("%s: Decode: %v", name, err)
+		}
+		if doc.Compressed {
+			t.Fatalf("%s: expected uncompressed document", name)
+		}
+		var buffer bytes.Buffer
+		if err := Encode(&buffer, doc); err != nil {
+			t.Fatalf("%s: Encode: %v", name, err)
+		}
+		if !bytes.Equal(buffer.Bytes(), golden) {
+			t.Fatalf("%s: re-encoded bytes differ from golden file", name)
+		}
+	}
+}
+
+func TestDecodeAllTypes(t *testing.T) {
+	want := allTypesDocument()
+	for _, name := range []string{"all_types.nbt", "all_types.nbt.gz"} {
+		doc, err := Decode(bytes.NewReader(readGolden(t, name)))
+		if err != nil {
+			t.Fatalf("%s: Decode: %v", name, err)
+		}
+		if doc.Compressed != (filepath.Ext(name) == ".gz") {
+			t.Fatalf("%s: unexpected compression flag %v", name, doc.Compressed)
+		}
+		doc.Compressed = false
+		if !reflect.DeepEqual(doc, want) {
+			t.Fatalf("%s: decoded %#v, want %#v", name, doc, want)
+		}
+	}
+
+	var buffer bytes.Buffer
+	if err := Encode(&buffer, want); err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	if !bytes.Equal(buffer.Bytes(), readGolden(t, "all_types.nbt")) {
+		t.Fatalf("encoded bytes differ from golden file")
+	}
+}
+
+func TestGzipRoundTrip(t *testing.T) {
+	doc := allTypesDocument()
+	doc.Compressed = true
+	path := filepath.Join(t.TempDir(), "level.dat")
+	if err := WriteFile(path, doc); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	decoded, err := ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	if !reflect.DeepEqual(decoded, doc) {
+		t.Fatalf("gzip round trip mismatch: %#v", decoded)
+	}
+}
+
+func TestDecodeRejectsInvalidInput(t *testing.T) {
+	for name, data := range map[string][]byte{
+		"not a compound":   {0x08, 0x00, 0x00, 0x00, 0x00},
+		"truncated":        readGolden(t, "servers.dat")[:40],
+		"negative length":  {0x0a, 0x00, 0x00, 0x07, 0x00, 0x01, 'a', 0xff, 0xff, 0xff, 0xff},
+		"unknown tag type": {0x0a, 0x00, 0x00, 0x63, 0x00, 0x00},
+	} {
+		if _, err := Decode(bytes.NewReader(data)); err == nil {
+			t.Fatalf("%s: expected error", name)
+		}
+	}
+}
+
+func TestCompoundSet(t *testing.T) {
+	compound := Compound{{Name: "a", Value: Byte(1)}}
+	compound.Set("a", Byte(2))
+	compound.Set("b", String("x"))
+	compound.Delete("a")
+	if len(compound) != 1 || compound.GetString("b") != "x" {
+		t.Fatalf("unexpected compound %#v", compound)
+	}
+}
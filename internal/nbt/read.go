@@ -0,0 +1,265 @@
+package nbt
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+const (
+	maxDepth       = 512
+	maxArrayLength = 1 << 24
+)
+
+func ReadFile(path string) (Document, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return Document{}, err
+	}
+	defer file.Close()
+	return Decode(file)
+}
+
+func Decode(r io.Reader) (Document, error) {
+	buffered := bufio.NewReader(r)
+	var doc Document
+	if magic, err := buffered.Peek(2); err == nil && magic[0] == 0x1f && magic[1] == 0x8b {
+		gz, err := gzip.NewReader(buffered)
+		if err != nil {
+			return Document{}, fmt.Errorf("nbt: %w", err)
+		}
+		defer gz.Close()
+		buffered = bufio.NewReader(gz)
+		doc.Compressed = true
+	}
+
+	d := decoder{r: buffered}
+	tag, err := d.readByte()
+	if err != nil {
+		return Document{}, fmt.Errorf("nbt: read root tag: %w", err)
+	}
+	if Type(tag) != TagCompound {
+		return Document{}, fmt.Errorf("nbt: root tag is %s, want %s", Type(tag), TagCompound)
+	}
+	if doc.Name, err = d.readString(); err != nil {
+		return Document{}, fmt.Errorf("nbt: read root name: %w", err)
+	}
+	root, err := d.readCompound(1)
+	if err != nil {
+		return Document{}, err
+	}
+	doc.Root = root
+	return doc, nil
+}
+
+type decoder struct {
+	r   *bufio.Reader
+	buf [8]byte
+}
+
+func (d *decoder) readByte() (byte, error) {
+	return d.r.ReadByte()
+}
+
+func (d *decoder) readN(n int) ([]byte, error) {
+	if _, err := io.ReadFull(d.r, d.buf[:n]); err != nil {
+		return nil, err
+	}
+	return d.buf[:n], nil
+}
+
+func (d *decoder) readInt16() (int16, error) {
+	data, err := d.readN(2)
+	if err != nil {
+		return 0, err
+	}
+	return int16(binary.BigEndian.Uint16(data)), nil
+}
+
+func (d *decoder) readInt32() (int32, error) {
+	data, err := d.readN(4)
+	if err != nil {
+		return 0, err
+	}
+	return int32(binary.BigEndian.Uint32(data)), nil
+}
+
+func (d *decoder) readInt64() (int64, error) {
+	data, err := d.readN(8)
+	if err != nil {
+		return 0, err
+	}
+	return int64(binary.BigEndian.Uint64(data)), nil
+}
+
+func (d *decoder) readLength() (int, error) {
+	length, err := d.readInt32()
+	if err != nil {
+		return 0, err
+	}
+	if length < 0 || length > maxArrayLength {
+		return 0, fmt.Errorf("invalid length %d", length)
+	}
+	return int(length), nil
+}
+
+func (d *decoder) readString() (string, error) {
+	length, err := d.readInt16()
+	if err != nil {
+		return "", err
+	}
+	data := make([]byte, uint16(length))
+	if _, err := io.ReadFull(d.r, data); err != nil {
+		return "", err
+	}
+	return decodeModifiedUTF8(data), nil
+}
+
+func (d *decoder) readCompound(depth int) (Compound, error) {
+	if depth > maxDepth {
+		return nil, fmt.Errorf("nbt: nesting deeper than %d", maxDepth)
+	}
+	compound := Compound{}
+	for {
+		tag, err := d.readByte()
+		if err != nil {
+			return nil, fmt.Errorf("nbt: read tag: %w", err)
+		}
+		if Type(tag) == TagEnd {
+			return compound, nil
+		}
+		name, err := d.readString()
+		if err != nil {
+			return nil, fmt.Errorf("nbt: read tag name: %w", err)
+		}
+		value, err := d.readPayload(Type(tag), depth)
+		if err != nil {
+			return nil, fmt.Errorf("nbt: %s %q: %w", Type(tag), name, err)
+		}
+		compound = append(compound, Field{Name: name, Value: value})
+	}
+}
+
+func (d *decoder) readPayload(tag Type, depth int) (Value, error) {
+	switch tag {
+	case TagByte:
+		value, err := d.readByte()
+		return Byte(int8(value)), err
+	case TagShort:
+		value, err := d.readInt16()
+		return Short(value), err
+	case TagInt:
+		value, err := d.readInt32()
+		return Int(value), err
+	case TagLong:
+		value, err := d.readInt64()
+		return Long(value), err
+	case TagFloat:
+		value, err := d.readInt32()
+		return Float(math.Float32frombits(uint32(value))), err
+	case TagDouble:
+		value, err := d.readInt64()
+		return Double(math.Float64frombits(uint64(value))), err
+	case TagByteArray:
+		length, err := d.readLength()
+		if err != nil {
+			return nil, err
+		}
+		data := make([]byte, length)
+		if _, err := io.ReadFull(d.r, data); err != nil {
+			return nil, err
+		}
+		return ByteArray(data), nil
+	case TagString:
+		value, err := d.readString()
+		return String(value), err
+	case TagList:
+		elem, err := d.readByte()
+		if err != nil {
+			return nil, err
+		}
+		length, err := d.readLength()
+		if err != nil {
+			return nil, err
+		}
+		if Type(elem) == TagEnd && length > 0 {
+			return nil, fmt.Errorf("list of %s with %d items", TagEnd, length)
+		}
+		list := List{Elem: Type(elem), Items: make([]Value, 0, minInt(length, 1024))}
+		for i := 0; i < length; i++ {
+			item, err := d.readPayload(Type(elem), depth+1)
+			if err != nil {
+				return nil, err
+			}
+			list.Items = append(list.Items, item)
+		}
+		return list, nil
+	case TagCompound:
+		return d.readCompound(depth + 1)
+	case TagIntArray:
+		length, err := d.readLength()
+		if err != nil {
+			return nil, err
+		}
+		values := make([]int32, 0, minInt(length, 1024))
+		for i := 0; i < length; i++ {
+			value, err := d.readInt32()
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, value)
+		}
+		return IntArray(values), nil
+	case TagLongArray:
+		length, err := d.readLength()
+		if err != nil {
+			return nil, err
+		}
+		values := make([]int64, 0, minInt(length, 1024))
+		for i := 0; i < length; i++ {
+			value, err := d.readInt64()
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, value)
+		}
+		return LongArray(values), nil
+	default:
+		return nil, fmt.Errorf("unknown tag type %d", byte(tag))
+	}
+}
+
+func decodeModifiedUTF8(data []byte) string {
+	units := make([]uint16, 0, len(data))
+	for i := 0; i < len(data); {
+		b := data[i]
+		switch {
+		case b < 0x80:
+			units = append(units, uint16(b))
+			i++
+		case b&0xE0 == 0xC0 && i+1 < len(data):
+			units = append(units, uint16(b&0x1F)<<6|uint16(data[i+1]&0x3F))
+			i += 2
+		case b&0xF0 == 0xE0 && i+2 < len(data):
+			units = append(units, uint16(b&0x0F)<<12|uint16(data[i+1]&0x3F)<<6|uint16(data[i+2]&0x3F))
+			i += 3
+		default:
+			units = append(units, utf8.RuneError)
+			i++
+		}
+	}
+	return string(utf16.Decode(units))
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
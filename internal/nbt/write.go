@@ -0,0 +1,186 @@
+package nbt
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"unicode/utf16"
+)
+
+func WriteFile(path string, doc Document) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if err := Encode(tmp, doc); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+func Encode(w io.Writer, doc Document) error {
+	var gz *gzip.Writer
+	if doc.Compressed {
+		gz = gzip.NewWriter(w)
+		w = gz
+	}
+	buffered := bufio.NewWriter(w)
+	e := encoder{w: buffered}
+	e.writeByte(byte(TagCompound))
+	e.writeString(doc.Name)
+	e.writeCompound(doc.Root, 1)
+	if e.err != nil {
+		return e.err
+	}
+	if err := buffered.Flush(); err != nil {
+		return err
+	}
+	if gz != nil {
+		return gz.Close()
+	}
+	return nil
+}
+
+type encoder struct {
+	w   *bufio.Writer
+	buf [8]byte
+	err error
+}
+
+func (e *encoder) fail(format string, args ...any) {
+	if e.err == nil {
+		e.err = fmt.Errorf("nbt: "+format, args...)
+	}
+}
+
+func (e *encoder) write(data []byte) {
+	if e.err != nil {
+		return
+	}
+	_, e.err = e.w.Write(data)
+}
+
+func (e *encoder) writeByte(value byte) {
+	e.buf[0] = value
+	e.write(e.buf[:1])
+}
+
+func (e *encoder) writeInt16(value int16) {
+	binary.BigEndian.PutUint16(e.buf[:2], uint16(value))
+	e.write(e.buf[:2])
+}
+
+func (e *encoder) writeInt32(value int32) {
+	binary.BigEndian.PutUint32(e.buf[:4], uint32(value))
+	e.write(e.buf[:4])
+}
+
+func (e *encoder) writeInt64(value int64) {
+	binary.BigEndian.PutUint64(e.buf[:8], uint64(value))
+	e.write(e.buf[:8])
+}
+
+func (e *encoder) writeString(value string) {
+	data := encodeModifiedUTF8(value)
+	if len(data) > math.MaxUint16 {
+		e.fail("string of %d bytes is too long", len(data))
+		return
+	}
+	e.writeInt16(int16(uint16(len(data))))
+	e.write(data)
+}
+
+func (e *encoder) writeCompound(compound Compound, depth int) {
+	if depth > maxDepth {
+		e.fail("nesting deeper than %d", maxDepth)
+		return
+	}
+	for _, field := range compound {
+		if field.Value == nil {
+			e.fail("field %q has no value", field.Name)
+			return
+		}
+		e.writeByte(byte(field.Value.Type()))
+		e.writeString(field.Name)
+		e.writePayload(field.Value, depth)
+	}
+	e.writeByte(byte(TagEnd))
+}
+
+func (e *encoder) writePayload(value Value, depth int) {
+	switch v := value.(type) {
+	case Byte:
+		e.writeByte(byte(v))
+	case Short:
+		e.writeInt16(int16(v))
+	case Int:
+		e.writeInt32(int32(v))
+	case Long:
+		e.writeInt64(int64(v))
+	case Float:
+		e.writeInt32(int32(math.Float32bits(float32(v))))
+	case Double:
+		e.writeInt64(int64(math.Float64bits(float64(v))))
+	case ByteArray:
+		e.writeInt32(int32(len(v)))
+		e.write(v)
+	case String:
+		e.writeString(string(v))
+	case List:
+		elem := v.Elem
+		if len(v.Items) > 0 && elem == TagEnd {
+			elem = v.Items[0].Type()
+		}
+		e.writeByte(byte(elem))
+		e.writeInt32(int32(len(v.Items)))
+		for _, item := range v.Items {
+			if item == nil || item.Type() != elem {
+				e.fail("list of %s contains a different tag", elem)
+				return
+			}
+			e.writePayload(item, depth+1)
+		}
+	case Compound:
+		e.writeCompound(v, depth+1)
+	case IntArray:
+		e.writeInt32(int32(len(v)))
+		for _, item := range v {
+			e.writeInt32(item)
+		}
+	case LongArray:
+		e.writeInt32(int32(len(v)))
+		for _, item := range v {
+			e.writeInt64(item)
+		}
+	default:
+		e.fail("unsupported value %T", value)
+	}
+}
+
+func encodeModifiedUTF8(value string) []byte {
+	data := make([]byte, 0, len(value))
+	for _, unit := range utf16.Encode([]rune(value)) {
+		switch {
+		case unit != 0 && unit < 0x80:
+			data = append(data, byte(unit))
+		case unit < 0x800:
+			data = append(data, byte(0xC0|unit>>6), byte(0x80|unit&0x3F))
+		default:
+			data = append(data, byte(0xE0|unit>>12), byte(0x80|(unit>>6)&0x3F), byte(0x80|unit&0x3F))
+		}
+	}
+	return data
+}
@@ -0,0 +1,259 @@
+package ping
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	adaptiveCeilingFactor   = 4
+	adaptiveFloorDivisor    = 8
+	adaptiveWindowMin       = 16
+	adaptiveTimeoutJump     = 0.15
+	adaptiveTimeoutFactor   = 1.5
+	adaptiveLatencyFactor   = 1.5
+	adaptiveDecreasePercent = 70
+)
+
+type ConcurrencyController struct {
+	mu          sync.Mutex
+	wake        chan struct{}
+	adaptive    bool
+	limit       int
+	floor       int
+	ceiling     int
+	peak        int
+	active      int
+	step        int
+	samples     int
+	timeouts    int
+	successes   int
+	latencySum  time.Duration
+	hasBaseline bool
+	baseRatio   float64
+	baseLatency time.Duration
+	reason      string
+}
+
+func NewLookupConcurrency(configured, total int, adaptive bool) *ConcurrencyController {
+	if total <= 0 {
+		return NewConcurrencyController(1, 1, 1, false)
+	}
+	initial := configured
+	if initial <= 0 {
+		initial = DefaultLookupConcurrency(total)
+	}
+	if initial > total {
+		initial = total
+	}
+	if !adaptive {
+		return NewConcurrencyController(initial, initial, initial, false)
+	}
+	ceiling := initial
+	if configured <= 0 {
+		ceiling = AutoLookupConcurrencyTarget() * adaptiveCeilingFactor
+	}
+	if ceiling > total {
+		ceiling = total
+	}
+	floor := initial / adaptiveFloorDivisor
+	if floor < 1 {
+		floor = 1
+	}
+	return NewConcurrencyController(initial, floor, ceiling, true)
+}
+
+func NewConcurrencyController(initial, floor, ceiling int, adaptive bool) *ConcurrencyController {
+	if ceiling < 1 {
+		ceiling = 1
+	}
+	if floor < 1 {
+		floor = 1
+	}
+	if floor > ceiling {
+		floor = ceiling
+	}
+	if initial < floor {
+		initial = floor
+	}
+	if initial > ceiling {
+		initial = ceiling
+	}
+	step := initial / adaptiveFloorDivisor
+	if step < 1 {
+		step = 1
+	}
+	reason := "fixed"
+	if adaptive {
+		reason = "calibrating"
+	}
+	return &ConcurrencyController{
+		wake:     make(chan struct{}),
+		adaptive: adaptive,
+		limit:    initial,
+		floor:    floor,
+		ceiling:  ceiling,
+		peak:     initial,
+		step:     step,
+		reason:   reason,
+	}
+}
+
+func (c *ConcurrencyController) Acquire(ctx context.Context) error {
+	for {
+		c.mu.Lock()
+		if c.active < c.limit {
+			c.active++
+			c.mu.Unlock()
+			return nil
+		}
+		wake := c.wake
+		c.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-wake:
+		}
+	}
+}
+
+func (c *ConcurrencyController) Release() {
+	c.mu.Lock()
+	if c.active > 0 {
+		c.active--
+	}
+	c.signalLocked()
+	c.mu.Unlock()
+}
+
+func (c *ConcurrencyController) Observe(latency time.Duration, err error) {
+	if !c.adaptive || errors.Is(err, context.Canceled) {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.samples++
+	if err == nil {
+		c.successes++
+		c.latencySum += latency
+	} else if isTimeoutError(err) {
+		c.timeouts++
+	}
+	if c.samples < c.windowLocked() {
+		return
+	}
+	c.adjustLocked()
+}
+
+func (c *ConcurrencyController) Limit() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.limit
+}
+
+func (c *ConcurrencyController) Peak() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.peak
+}
+
+func (c *ConcurrencyController) Max() int {
+	return c.ceiling
+}
+
+func (c *ConcurrencyController) Adaptive() bool {
+	return c.adaptive
+}
+
+func (c *ConcurrencyController) Reason() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.reason
+}
+
+func (c *ConcurrencyController) windowLocked() int {
+	if c.limit > adaptiveWindowMin {
+		return c.limit
+	}
+	return adaptiveWindowMin
+}
+
+func (c *ConcurrencyController) adjustLocked() {
+	ratio := float64(c.timeouts) / float64(c.samples)
+	var latency time.Duration
+	if c.successes > 0 {
+		latency = c.latencySum / time.Duration(c.successes)
+	}
+	c.samples = 0
+	c.timeouts = 0
+	c.successes = 0
+	c.latencySum = 0
+
+	if !c.hasBaseline {
+		c.hasBaseline = true
+		c.baseRatio = ratio
+		c.baseLatency = latency
+		c.reason = fmt.Sprintf("baseline %.0f%% timeouts", ratio*100)
+		return
+	}
+
+	timeoutJump := ratio > c.baseRatio+adaptiveTimeoutJump && ratio > c.baseRatio*adaptiveTimeoutFactor
+	c.baseRatio = c.baseRatio*0.9 + ratio*0.1
+	if timeoutJump {
+		next := c.limit * adaptiveDecreasePercent / 100
+		if next < c.floor {
+			next = c.floor
+		}
+		if next < c.limit {
+			c.reason = fmt.Sprintf("backed off %d -> %d: timeouts %.0f%% vs %.0f%% baseline", c.limit, next, ratio*100, c.baseRatio*100)
+			c.limit = next
+		} else {
+			c.reason = fmt.Sprintf("holding at floor: timeouts %.0f%%", ratio*100)
+		}
+		return
+	}
+
+	if latency > 0 && c.baseLatency > 0 && float64(latency) > float64(c.baseLatency)*adaptiveLatencyFactor {
+		c.reason = fmt.Sprintf("holding at %d: latency %s vs %s baseline", c.limit, roundLatency(latency), roundLatency(c.baseLatency))
+		return
+	}
+	if latency > 0 {
+		if c.baseLatency == 0 {
+			c.baseLatency = latency
+		} else {
+			c.baseLatency = (c.baseLatency*4 + latency) / 5
+		}
+	}
+	if c.limit >= c.ceiling {
+		c.reason = fmt.Sprintf("holding at ceiling %d: latency stable", c.ceiling)
+		return
+	}
+	next := c.limit + c.step
+	if next > c.ceiling {
+		next = c.ceiling
+	}
+	c.reason = fmt.Sprintf("grew %d -> %d: latency stable", c.limit, next)
+	c.limit = next
+	if next > c.peak {
+		c.peak = next
+	}
+	c.signalLocked()
+}
+
+func (c *ConcurrencyController) signalLocked() {
+	close(c.wake)
+	c.wake = make(chan struct{})
+}
+
+func roundLatency(value time.Duration) time.Duration {
+	return value.Round(time.Millisecond)
+}
+
+func isTimeoutError(err error) bool {
+	return ClassifyError(err).Timeout()
+}
@@ -0,0 +1,78 @@
+package ping
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestConcurrencyControllerGrowsWhileLatencyStable(t *testing.T) {
+	controller := NewConcurrencyController(16, 2, 64, true)
+
+	observeWindow(controller, 16, 0, 40*time.Millisecond)
+	if got := controller.Limit(); got != 16 {
+		t.Fatalf("expected baseline window to keep limit, got %d", got)
+	}
+
+	observeWindow(controller, 16, 0, 42*time.Millisecond)
+	if got := controller.Limit(); got != 18 {
+		t.Fatalf("expected additive increase to 18, got %d", got)
+	}
+	if controller.Peak() != 18 {
+		t.Fatalf("expected peak 18, got %d", controller.Peak())
+	}
+}
+
+func TestConcurrencyControllerBacksOffOnTimeoutJump(t *testing.T) {
+	controller := NewConcurrencyController(40, 4, 64, true)
+
+	observeWindow(controller, 40, 4, 40*time.Millisecond)
+	observeWindow(controller, 40, 30, 40*time.Millisecond)
+	if got := controller.Limit(); got != 28 {
+		t.Fatalf("expected multiplicative decrease to 28, got %d", got)
+	}
+	if reason := controller.Reason(); !strings.HasPrefix(reason, "backed off") {
+		t.Fatalf("unexpected adjustment reason: %q", reason)
+	}
+}
+
+func TestConcurrencyControllerFixedIgnoresSamples(t *testing.T) {
+	controller := NewLookupConcurrency(8, 100, false)
+
+	observeWindow(controller, 64, 64, time.Second)
+	if got := controller.Limit(); got != 8 {
+		t.Fatalf("expected fixed limit 8, got %d", got)
+	}
+	if controller.Max() != 8 {
+		t.Fatalf("expected fixed ceiling 8, got %d", controller.Max())
+	}
+}
+
+func TestConcurrencyControllerAcquireBlocksAtLimit(t *testing.T) {
+	controller := NewConcurrencyController(1, 1, 1, false)
+	if err := controller.Acquire(context.Background()); err != nil {
+		t.Fatalf("unexpected acquire error: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := controller.Acquire(ctx); err == nil {
+		t.Fatal("expected second acquire to block until the context expired")
+	}
+
+	controller.Release()
+	if err := controller.Acquire(context.Background()); err != nil {
+		t.Fatalf("expected acquire after release to succeed: %v", err)
+	}
+}
+
+func observeWindow(controller *ConcurrencyController, samples, timeouts int, latency time.Duration) {
+	for i := 0; i < samples; i++ {
+		if i < timeouts {
+			controller.Observe(latency, context.DeadlineExceeded)
+			continue
+		}
+		controller.Observe(latency, nil)
+	}
+}
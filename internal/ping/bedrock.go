@@ -23,16 +23,16 @@ func mustHex(s string) []byte {
 
 func parsePong(buf []byte) (BedrockPong, error) {
 	if len(buf) < 35 {
-		return BedrockPong{}, fmt.Errorf("pong too short: %d bytes", len(buf))
+		return BedrockPong{}, protocolFailure(fmt.Errorf("pong too short: %d bytes", len(buf)))
 	}
 
 	if buf[0] != 0x1c {
-		return BedrockPong{}, fmt.Errorf("unexpected packet id: 0x%02x", buf[0])
+		return BedrockPong{}, unexpectedPacket(fmt.Errorf("unexpected packet id: 0x%02x", buf[0]))
 	}
 
 	nameLen := int(binary.BigEndian.Uint16(buf[33:35]))
 	if 35+nameLen > len(buf) {
-		return BedrockPong{}, fmt.Errorf("invalid advertise length: %d (buf=%d)", nameLen, len(buf))
+		return BedrockPong{}, protocolFailure(fmt.Errorf("invalid advertise length: %d (buf=%d)", nameLen, len(buf)))
 	}
 
 	advertise := string(buf[35 : 35+nameLen])
@@ -79,7 +79,7 @@ func PingBedrock(ctx context.Context, ip net.IP, host string, port int) (Bedrock
 	raddr := &net.UDPAddr{IP: ip, Port: port}
 	conn, err := net.DialUDP(network, nil, raddr)
 	if err != nil {
-		return BedrockPong{}, err
+		return BedrockPong{}, connectFailure(err)
 	}
 	defer conn.Close()
 
@@ -119,9 +119,9 @@ func PingBedrock(ctx context.Context, ip net.IP, host string, port int) (Bedrock
 	if err != nil {
 		var netErr net.Error
 		if errors.As(err, &netErr) && netErr.Timeout() {
-			return BedrockPong{}, fmt.Errorf("timeout while pinging %s:%d", host, port)
+			return BedrockPong{}, classifyFailure(FailureReadTimeout, fmt.Errorf("timeout while pinging %s:%d", host, port))
 		}
-		return BedrockPong{}, err
+		return BedrockPong{}, readFailure(err)
 	}
 
 	return parsePong(buf[:n])
@@ -12,15 +12,35 @@ type Result interface {
 	String() string
 }
 
+type ProbeKind string
+
+const (
+	ProbeStatus ProbeKind = "status"
+	ProbeLegacy ProbeKind = "legacy"
+)
+
+func ParseProbeKind(value string) (ProbeKind, bool) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "", "status", "default":
+		return ProbeStatus, true
+	case "legacy", "1.6":
+		return ProbeLegacy, true
+	default:
+		return "", false
+	}
+}
+
 type ExecuteConfig struct {
-	Edition    Edition
-	Host       string
-	Port       int
-	Timeout    time.Duration
-	RetryCount int
-	RetryDelay time.Duration
-	EnableSRV  bool
-	IPMode     IPMode
+	Edition       Edition
+	Host          string
+	Port          int
+	Timeout       time.Duration
+	RetryCount    int
+	RetryDelay    time.Duration
+	EnableSRV     bool
+	IPMode        IPMode
+	HandshakeHost string
+	Probe         ProbeKind
 }
 
 type ExecuteOptions struct {
@@ -42,6 +62,7 @@ type ExecuteDetails struct {
 	SRVHost       string
 	SRVPort       int
 	SRVError      string
+	ResolveTime   time.Duration
 	Attempts      int
 	LastError     string
 }
@@ -84,7 +105,7 @@ func Execute(ctx context.Context, config ExecuteConfig) (Result, ExecuteDetails,
 		if attempt < attempts && config.RetryDelay > 0 {
 			select {
 			case <-ctx.Done():
-				return nil, details, ctx.Err()
+				return nil, details, classifyFailure(FailureCanceled, ctx.Err())
 			case <-time.After(config.RetryDelay):
 			}
 		}
@@ -118,13 +139,16 @@ func ParsePort(value string) (int, error) {
 }
 
 func executeOnce(ctx context.Context, config ExecuteConfig) (Result, ExecuteDetails, error) {
+	if config.Probe == ProbeLegacy && config.Edition != EditionJava {
+		return nil, ExecuteDetails{}, classifyFailure(FailureOther, fmt.Errorf("legacy probe is only available for Java"))
+	}
 	switch config.Edition {
 	case EditionJava:
 		return executeJava(ctx, config)
 	case EditionBedrock:
 		return executeBedrock(ctx, config)
 	default:
-		return nil, ExecuteDetails{}, fmt.Errorf("unknown edition: %s", config.Edition)
+		return nil, ExecuteDetails{}, classifyFailure(FailureOther, fmt.Errorf("unknown edition: %s", config.Edition))
 	}
 }
 
@@ -153,5 +177,8 @@ func mergeDetails(base ExecuteDetails, next ExecuteDetails) ExecuteDetails {
 	if next.SRVError != "" {
 		base.SRVError = next.SRVError
 	}
+	if next.ResolveTime > 0 {
+		base.ResolveTime = next.ResolveTime
+	}
 	return base
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net"
+	"time"
 )
 
 func executeJava(ctx context.Context, config ExecuteConfig) (Result, ExecuteDetails, error) {
@@ -14,6 +15,7 @@ func executeJava(ctx context.Context, config ExecuteConfig) (Result, ExecuteDeta
 		DialPort:      config.Port,
 	}
 
+	resolveStart := time.Now()
 	dialHost := config.Host
 	dialPort := config.Port
 	if config.EnableSRV {
@@ -30,6 +32,7 @@ func executeJava(ctx context.Context, config ExecuteConfig) (Result, ExecuteDeta
 	}
 
 	selectedIP, resolved, err := resolveIP(ctx, dialHost, config.IPMode)
+	details.ResolveTime = time.Since(resolveStart)
 	if err != nil {
 		return nil, details, err
 	}
@@ -38,7 +41,15 @@ func executeJava(ctx context.Context, config ExecuteConfig) (Result, ExecuteDeta
 	details.SelectedIP = selectedIP
 	details.ResolvedIPs = resolved
 
-	status, err := PingJava(ctx, selectedIP, config.Host, dialPort)
+	handshakeHost := config.Host
+	if config.HandshakeHost != "" {
+		handshakeHost = config.HandshakeHost
+	}
+	probe := PingJava
+	if config.Probe == ProbeLegacy {
+		probe = PingJavaLegacy
+	}
+	status, err := probe(ctx, selectedIP, handshakeHost, dialPort)
 	if err != nil {
 		return nil, details, err
 	}
@@ -53,7 +64,9 @@ func executeBedrock(ctx context.Context, config ExecuteConfig) (Result, ExecuteD
 		DialPort:      config.Port,
 	}
 
+	resolveStart := time.Now()
 	selectedIP, resolved, err := resolveIP(ctx, config.Host, config.IPMode)
+	details.ResolveTime = time.Since(resolveStart)
 	if err != nil {
 		return nil, details, err
 	}
@@ -0,0 +1,224 @@
+package ping
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"syscall"
+)
+
+const wsaeConnRefused = syscall.Errno(10061)
+
+type FailureClass string
+
+const (
+	FailureNXDomain         FailureClass = "nxdomain"
+	FailureDNSTimeout       FailureClass = "dns_timeout"
+	FailureDNS              FailureClass = "dns_error"
+	FailureConnRefused      FailureClass = "connection_refused"
+	FailureConnectTimeout   FailureClass = "connect_timeout"
+	FailureReadTimeout      FailureClass = "read_timeout"
+	FailureProtocol         FailureClass = "protocol_error"
+	FailureUnexpectedPacket FailureClass = "unexpected_packet"
+	FailureCanceled         FailureClass = "canceled"
+	FailureOther            FailureClass = "other"
+)
+
+var FailureClasses = []FailureClass{
+	FailureNXDomain,
+	FailureDNSTimeout,
+	FailureDNS,
+	FailureConnRefused,
+	FailureConnectTimeout,
+	FailureReadTimeout,
+	FailureProtocol,
+	FailureUnexpectedPacket,
+	FailureCanceled,
+	FailureOther,
+}
+
+type ProbeError struct {
+	Class FailureClass
+	Err   error
+}
+
+func (e *ProbeError) Error() string {
+	if e == nil {
+		return ""
+	}
+	if e.Err == nil {
+		return e.Class.Label()
+	}
+	return e.Err.Error()
+}
+
+func (e *ProbeError) Unwrap() error {
+	if e == nil {
+		return nil
+	}
+	return e.Err
+}
+
+func (c FailureClass) Label() string {
+	switch c {
+	case FailureNXDomain:
+		return "NXDOMAIN"
+	case FailureDNSTimeout:
+		return "DNS timeout"
+	case FailureDNS:
+		return "DNS error"
+	case FailureConnRefused:
+		return "connection refused"
+	case FailureConnectTimeout:
+		return "connect timeout"
+	case FailureReadTimeout:
+		return "read timeout"
+	case FailureProtocol:
+		return "protocol error"
+	case FailureUnexpectedPacket:
+		return "unexpected packet"
+	case FailureCanceled:
+		return "canceled"
+	case FailureOther:
+		return "other"
+	default:
+		return string(c)
+	}
+}
+
+func (c FailureClass) Timeout() bool {
+	return c == FailureDNSTimeout || c == FailureConnectTimeout || c == FailureReadTimeout
+}
+
+func ClassifyError(err error) FailureClass {
+	if err == nil {
+		return ""
+	}
+	var probeErr *ProbeError
+	if errors.As(err, &probeErr) && probeErr.Class != "" {
+		return probeErr.Class
+	}
+	if errors.Is(err, context.Canceled) {
+		return FailureCanceled
+	}
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return dnsFailureClass(dnsErr)
+	}
+	if isConnRefused(err) {
+		return FailureConnRefused
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return FailureReadTimeout
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return FailureReadTimeout
+	}
+	return FailureOther
+}
+
+func classifyFailure(class FailureClass, err error) error {
+	if err == nil {
+		return nil
+	}
+	var probeErr *ProbeError
+	if errors.As(err, &probeErr) {
+		return err
+	}
+	if errors.Is(err, context.Canceled) {
+		class = FailureCanceled
+	}
+	return &ProbeError{Class: class, Err: err}
+}
+
+func dnsFailure(err error) error {
+	if err == nil {
+		return nil
+	}
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return classifyFailure(dnsFailureClass(dnsErr), err)
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return classifyFailure(FailureDNSTimeout, err)
+	}
+	return classifyFailure(FailureDNS, err)
+}
+
+func dnsFailureClass(dnsErr *net.DNSError) FailureClass {
+	switch {
+	case dnsErr.IsNotFound:
+		return FailureNXDomain
+	case dnsErr.Timeout():
+		return FailureDNSTimeout
+	default:
+		return FailureDNS
+	}
+}
+
+func connectFailure(err error) error {
+	if err == nil {
+		return nil
+	}
+	if isConnRefused(err) {
+		return classifyFailure(FailureConnRefused, err)
+	}
+	if isNetTimeout(err) {
+		return classifyFailure(FailureConnectTimeout, err)
+	}
+	return classifyFailure(FailureOther, err)
+}
+
+func writeFailure(err error) error {
+	if err == nil {
+		return nil
+	}
+	if isConnRefused(err) {
+		return classifyFailure(FailureConnRefused, err)
+	}
+	if isNetTimeout(err) {
+		return classifyFailure(FailureConnectTimeout, err)
+	}
+	if errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.EPIPE) {
+		return classifyFailure(FailureProtocol, err)
+	}
+	return classifyFailure(FailureOther, err)
+}
+
+func readFailure(err error) error {
+	if err == nil {
+		return nil
+	}
+	if isConnRefused(err) {
+		return classifyFailure(FailureConnRefused, err)
+	}
+	if isNetTimeout(err) {
+		return classifyFailure(FailureReadTimeout, err)
+	}
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, syscall.ECONNRESET) {
+		return classifyFailure(FailureProtocol, err)
+	}
+	return classifyFailure(FailureOther, err)
+}
+
+func protocolFailure(err error) error {
+	return classifyFailure(FailureProtocol, err)
+}
+
+func unexpectedPacket(err error) error {
+	return classifyFailure(FailureUnexpectedPacket, err)
+}
+
+func isConnRefused(err error) bool {
+	return errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, wsaeConnRefused)
+}
+
+func isNetTimeout(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
@@ -0,0 +1,51 @@
+package ping
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"syscall"
+	"testing"
+)
+
+func TestClassifyError(t *testing.T) {
+	cases := []struct {
+		name string
+		err  error
+		want FailureClass
+	}{
+		{name: "nil", err: nil, want: ""},
+		{name: "nxdomain", err: dnsFailure(&net.DNSError{Err: "no such host", Name: "missing.example", IsNotFound: true}), want: FailureNXDomain},
+		{name: "dns-timeout", err: dnsFailure(&net.DNSError{Err: "i/o timeout", Name: "slow.example", IsTimeout: true}), want: FailureDNSTimeout},
+		{name: "refused", err: connectFailure(fmt.Errorf("dial tcp: %w", syscall.ECONNREFUSED)), want: FailureConnRefused},
+		{name: "connect-timeout", err: connectFailure(fmt.Errorf("dial tcp: %w", context.DeadlineExceeded)), want: FailureConnectTimeout},
+		{name: "refused-windows", err: readFailure(fmt.Errorf("wsarecv: %w", wsaeConnRefused)), want: FailureConnRefused},
+		{name: "refused-text", err: readFailure(errors.New("connection refused")), want: FailureOther},
+		{name: "write-timeout", err: writeFailure(fmt.Errorf("write: %w", context.DeadlineExceeded)), want: FailureConnectTimeout},
+		{name: "write-reset", err: writeFailure(fmt.Errorf("write: %w", syscall.EPIPE)), want: FailureProtocol},
+		{name: "read-timeout", err: readFailure(fmt.Errorf("read: %w", context.DeadlineExceeded)), want: FailureReadTimeout},
+		{name: "canceled", err: connectFailure(fmt.Errorf("dial tcp: %w", context.Canceled)), want: FailureCanceled},
+		{name: "wrapped", err: fmt.Errorf("batch: %w", protocolFailure(errors.New("varint too long"))), want: FailureProtocol},
+		{name: "plain", err: errors.New("boom"), want: FailureOther},
+	}
+
+	for _, tc := range cases {
+		if got := ClassifyError(tc.err); got != tc.want {
+			t.Fatalf("ClassifyError(%s) = %q, want %q", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestParsePongClassifiesFailures(t *testing.T) {
+	short := []byte{0x1c, 0x00}
+	if _, err := parsePong(short); ClassifyError(err) != FailureProtocol {
+		t.Fatalf("expected protocol error for short pong, got %v", err)
+	}
+
+	wrongID := make([]byte, 40)
+	wrongID[0] = 0x05
+	if _, err := parsePong(wrongID); ClassifyError(err) != FailureUnexpectedPacket {
+		t.Fatalf("expected unexpected packet error, got %v", err)
+	}
+}
@@ -19,7 +19,7 @@ func PingJava(ctx context.Context, dialHost string, handshakeHost string, port i
 	dialer := &net.Dialer{}
 	conn, err := dialer.DialContext(ctx, "tcp", addr)
 	if err != nil {
-		return JavaStatus{}, err
+		return JavaStatus{}, connectFailure(err)
 	}
 	defer conn.Close()
 
@@ -30,39 +30,39 @@ func PingJava(ctx context.Context, dialHost string, handshakeHost string, port i
 	}
 
 	if err := writeHandshake(conn, handshakeHost, port); err != nil {
-		return JavaStatus{}, err
+		return JavaStatus{}, writeFailure(err)
 	}
 	if err := writeStatusRequest(conn); err != nil {
-		return JavaStatus{}, err
+		return JavaStatus{}, writeFailure(err)
 	}
 
 	respPayload, err := readPacket(conn)
 	if err != nil {
-		return JavaStatus{}, err
+		return JavaStatus{}, readFailure(err)
 	}
 
 	respReader := bytes.NewReader(respPayload)
 	packetID, err := readVarInt(respReader)
 	if err != nil {
-		return JavaStatus{}, err
+		return JavaStatus{}, protocolFailure(err)
 	}
 	if packetID != 0x00 {
-		return JavaStatus{}, fmt.Errorf("unexpected status packet id: %d", packetID)
+		return JavaStatus{}, unexpectedPacket(fmt.Errorf("unexpected status packet id: %d", packetID))
 	}
 
 	statusJSON, err := readString(respReader)
 	if err != nil {
-		return JavaStatus{}, err
+		return JavaStatus{}, protocolFailure(err)
 	}
 
 	status, err := parseJavaStatus([]byte(statusJSON))
 	if err != nil {
-		return JavaStatus{}, err
+		return JavaStatus{}, protocolFailure(err)
 	}
 
 	latency, err := pingJavaLatency(conn)
 	if err != nil {
-		return JavaStatus{}, err
+		return JavaStatus{}, readFailure(err)
 	}
 	status.LatencyMillis = latency
 
@@ -97,24 +97,24 @@ func pingJavaLatency(conn net.Conn) (int64, error) {
 		return 0, err
 	}
 	if err := writePacket(conn, payload.Bytes()); err != nil {
-		return 0, err
+		return 0, writeFailure(err)
 	}
 
 	respPayload, err := readPacket(conn)
 	if err != nil {
-		return 0, err
+		return 0, readFailure(err)
 	}
 	respReader := bytes.NewReader(respPayload)
 	packetID, err := readVarInt(respReader)
 	if err != nil {
-		return 0, err
+		return 0, protocolFailure(err)
 	}
 	if packetID != 0x01 {
-		return 0, fmt.Errorf("unexpected pong packet id: %d", packetID)
+		return 0, unexpectedPacket(fmt.Errorf("unexpected pong packet id: %d", packetID))
 	}
 	var sent uint64
 	if err := binary.Read(respReader, binary.BigEndian, &sent); err != nil {
-		return 0, err
+		return 0, protocolFailure(err)
 	}
 	return time.Now().UnixMilli() - int64(sent), nil
 }
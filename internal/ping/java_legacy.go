@@ -0,0 +1,121 @@
+package ping
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf16"
+)
+
+const legacyProtocolVersion = 74
+
+// PingJavaLegacy sends the 1.6 server list ping (0xFE 0x01 with an
+// MC|PingHost plugin message), which servers from 1.4 onwards answer.
+func PingJavaLegacy(ctx context.Context, dialHost string, handshakeHost string, port int) (JavaStatus, error) {
+	addr := net.JoinHostPort(dialHost, strconv.Itoa(port))
+	dialer := &net.Dialer{}
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return JavaStatus{}, connectFailure(err)
+	}
+	defer conn.Close()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	} else {
+		_ = conn.SetDeadline(time.Now().Add(3 * time.Second))
+	}
+
+	startedAt := time.Now()
+	if _, err := conn.Write(legacyPingRequest(handshakeHost, port)); err != nil {
+		return JavaStatus{}, writeFailure(err)
+	}
+	text, err := readLegacyKick(conn)
+	if err != nil {
+		return JavaStatus{}, err
+	}
+	status, err := parseLegacyStatus(text)
+	if err != nil {
+		return JavaStatus{}, protocolFailure(err)
+	}
+	status.LatencyMillis = time.Since(startedAt).Milliseconds()
+	return status, nil
+}
+
+func legacyPingRequest(host string, port int) []byte {
+	hostUnits := utf16.Encode([]rune(host))
+	payload := &bytes.Buffer{}
+	payload.Write([]byte{0xfe, 0x01, 0xfa})
+	writeLegacyString(payload, "MC|PingHost")
+	_ = binary.Write(payload, binary.BigEndian, uint16(7+2*len(hostUnits)))
+	payload.WriteByte(legacyProtocolVersion)
+	writeLegacyString(payload, host)
+	_ = binary.Write(payload, binary.BigEndian, int32(port))
+	return payload.Bytes()
+}
+
+func writeLegacyString(w *bytes.Buffer, value string) {
+	units := utf16.Encode([]rune(value))
+	_ = binary.Write(w, binary.BigEndian, uint16(len(units)))
+	_ = binary.Write(w, binary.BigEndian, units)
+}
+
+func readLegacyKick(r io.Reader) (string, error) {
+	var header [3]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return "", readFailure(err)
+	}
+	if header[0] != 0xff {
+		return "", unexpectedPacket(fmt.Errorf("unexpected legacy packet id: %d", header[0]))
+	}
+	length := binary.BigEndian.Uint16(header[1:])
+	units := make([]uint16, length)
+	if err := binary.Read(r, binary.BigEndian, units); err != nil {
+		return "", readFailure(err)
+	}
+	return string(utf16.Decode(units)), nil
+}
+
+// parseLegacyStatus reads the kick message of a legacy ping: the 1.4+ form
+// "§1\x00protocol\x00version\x00motd\x00online\x00max" or the older
+// "motd§online§max".
+func parseLegacyStatus(text string) (JavaStatus, error) {
+	var status JavaStatus
+	var online, max string
+	if strings.HasPrefix(text, "§1\x00") {
+		fields := strings.Split(text, "\x00")
+		if len(fields) != 6 {
+			return JavaStatus{}, fmt.Errorf("legacy status has %d fields", len(fields))
+		}
+		protocol, err := strconv.Atoi(fields[1])
+		if err != nil {
+			return JavaStatus{}, fmt.Errorf("invalid legacy protocol %q", fields[1])
+		}
+		status.ProtocolVersion = protocol
+		status.VersionName = fields[2]
+		status.MOTD = fields[3]
+		online, max = fields[4], fields[5]
+	} else {
+		fields := strings.Split(text, "§")
+		if len(fields) < 3 {
+			return JavaStatus{}, fmt.Errorf("unrecognized legacy status")
+		}
+		status.MOTD = strings.Join(fields[:len(fields)-2], "§")
+		online, max = fields[len(fields)-2], fields[len(fields)-1]
+	}
+	var err error
+	if status.CurrentPlayers, err = strconv.Atoi(online); err != nil {
+		return JavaStatus{}, fmt.Errorf("invalid legacy player count %q", online)
+	}
+	if status.MaxPlayers, err = strconv.Atoi(max); err != nil {
+		return JavaStatus{}, fmt.Errorf("invalid legacy player limit %q", max)
+	}
+	status.CleanMOTD = stripMCFormatting(status.MOTD)
+	return status, nil
+}
@@ -0,0 +1,78 @@
+package ping
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"testing"
+	"time"
+	"unicode/utf16"
+)
+
+func TestParseLegacyStatus(t *testing.T) {
+	status, err := parseLegacyStatus("§1\x0047\x001.4.2\x00§aA Minecraft Server\x003\x0020")
+	if err != nil {
+		t.Fatalf("parseLegacyStatus: %v", err)
+	}
+	if status.ProtocolVersion != 47 || status.VersionName != "1.4.2" || status.CleanMOTD != "A Minecraft Server" || status.CurrentPlayers != 3 || status.MaxPlayers != 20 {
+		t.Fatalf("unexpected status %#v", status)
+	}
+
+	status, err = parseLegacyStatus("Old §lserver§0§10")
+	if err != nil {
+		t.Fatalf("parseLegacyStatus beta: %v", err)
+	}
+	if status.MOTD != "Old §lserver" || status.CurrentPlayers != 0 || status.MaxPlayers != 10 {
+		t.Fatalf("unexpected beta status %#v", status)
+	}
+
+	if _, err := parseLegacyStatus("§1\x0047\x001.4.2"); err == nil {
+		t.Fatalf("expected error for truncated status")
+	}
+}
+
+func TestPingJavaLegacy(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer listener.Close()
+
+	requests := make(chan []byte, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		want := len(legacyPingRequest("play.example.com", 25565))
+		request := make([]byte, want)
+		if _, err := io.ReadFull(conn, request); err != nil {
+			return
+		}
+		requests <- request
+		units := utf16.Encode([]rune("§1\x00127\x001.20.4\x00Hello\x005\x0050"))
+		var response bytes.Buffer
+		response.WriteByte(0xff)
+		_ = binary.Write(&response, binary.BigEndian, uint16(len(units)))
+		_ = binary.Write(&response, binary.BigEndian, units)
+		_, _ = conn.Write(response.Bytes())
+	}()
+
+	port := listener.Addr().(*net.TCPAddr).Port
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	status, err := PingJavaLegacy(ctx, "127.0.0.1", "play.example.com", port)
+	if err != nil {
+		t.Fatalf("PingJavaLegacy: %v", err)
+	}
+	if status.VersionName != "1.20.4" || status.MOTD != "Hello" || status.CurrentPlayers != 5 || status.MaxPlayers != 50 {
+		t.Fatalf("unexpected status %#v", status)
+	}
+	request := <-requests
+	if !bytes.HasPrefix(request, []byte{0xfe, 0x01, 0xfa}) || !bytes.Equal(request, legacyPingRequest("play.example.com", port)) {
+		t.Fatalf("unexpected request % x", request)
+	}
+}
@@ -15,46 +15,81 @@ type LookupConfig struct {
 	Port          int
 	Ports         []int
 	BaseHost      string
+	Targets       []LookupTarget
 	Subdomains    []string
 	DomainEndings []string
 	Concurrency   int
+	Adaptive      bool
 	RateLimit     int
 	Options       ExecuteOptions
+	Unresponsive  bool
 	Progress      func(progress LookupProgress)
+	Found         func(match LookupMatch)
+	Accept        func(match LookupMatch) bool
 	Paused        func() bool
 }
 
+type LookupTarget struct {
+	Host      string
+	Subdomain string
+	Ending    string
+	Source    string
+}
+
 type LookupMatch struct {
+	Host      string
+	Port      int
+	Subdomain string
+	Ending    string
+	Source    string
+	Result    Result
+	Detail    ExecuteDetails
+}
+
+type LookupFailure struct {
 	Host   string
 	Port   int
-	Result Result
+	Source string
+	Class  FailureClass
+	Err    error
 	Detail ExecuteDetails
 }
 
 type LookupResult struct {
-	Matches   []LookupMatch
-	Attempts  int
-	Completed int
+	Matches      []LookupMatch
+	Attempts     int
+	Completed    int
+	Workers      int
+	PeakWorkers  int
+	Rejected     int
+	Failures     map[FailureClass]int
+	Unresponsive []LookupFailure
 }
 
 type LookupProgress struct {
-	Subdomain string
-	Ending    string
-	Host      string
-	Port      int
-	Attempt   int
-	Total     int
-	Completed int
+	Subdomain  string
+	Ending     string
+	Host       string
+	Source     string
+	Port       int
+	Attempt    int
+	Total      int
+	Completed  int
+	Workers    int
+	Adjustment string
 }
 
 type lookupCandidate struct {
 	subdomain string
 	ending    string
 	host      string
+	source    string
 	port      int
 	attempt   int
 }
 
+const LookupSourceGenerated = "generated"
+
 const (
 	lookupAutoWorkersPerCPU = 16
 	lookupAutoWorkersMin    = 64
@@ -81,35 +116,37 @@ func DefaultLookupConcurrency(total int) int {
 
 func LookupDomains(ctx context.Context, config LookupConfig) (LookupResult, error) {
 	baseHost := strings.TrimSpace(config.BaseHost)
-	if baseHost == "" {
+	targets := normalizeLookupTargets(config.Targets)
+	if baseHost == "" && len(targets) == 0 {
 		return LookupResult{}, fmt.Errorf("base host cannot be empty")
 	}
 
-	subdomains := normalizeSubdomains(config.Subdomains)
-	endings := normalizeEndings(config.DomainEndings)
-	if len(endings) == 0 {
-		return LookupResult{}, fmt.Errorf("no domain endings provided")
-	}
-	if len(subdomains) == 0 {
-		subdomains = []string{""}
+	var subdomains, endings []string
+	if baseHost != "" {
+		subdomains = normalizeSubdomains(config.Subdomains)
+		endings = normalizeEndings(config.DomainEndings)
+		if len(endings) == 0 {
+			return LookupResult{}, fmt.Errorf("no domain endings provided")
+		}
+		if len(subdomains) == 0 {
+			subdomains = []string{""}
+		}
 	}
 	ports := normalizeLookupPorts(config.Port, config.Ports)
 
-	total := len(subdomains) * len(endings) * len(ports)
+	total := (len(subdomains)*len(endings) - countGeneratedOverlap(targets, subdomains, endings, baseHost) + len(targets)) * len(ports)
 	if total == 0 {
 		return LookupResult{}, fmt.Errorf("no combinations available")
 	}
-	concurrency := config.Concurrency
-	if concurrency <= 0 {
-		concurrency = DefaultLookupConcurrency(total)
-	}
-	if concurrency > total {
-		concurrency = total
-	}
+	controller := NewLookupConcurrency(config.Concurrency, total, config.Adaptive)
+	concurrency := controller.Max()
 
 	candidates := make(chan lookupCandidate, concurrency)
 	results := make(chan LookupMatch, concurrency)
 	var completed int64
+	var failuresMu sync.Mutex
+	failures := make(map[FailureClass]int)
+	unresponsive := make([]LookupFailure, 0)
 
 	var limiter <-chan time.Time
 	if config.RateLimit > 0 {
@@ -125,13 +162,23 @@ func LookupDomains(ctx context.Context, config LookupConfig) (LookupResult, erro
 	var wg sync.WaitGroup
 	worker := func() {
 		defer wg.Done()
-		for candidate := range candidates {
+		for {
+			if controller.Acquire(ctx) != nil {
+				return
+			}
+			candidate, ok := <-candidates
+			if !ok {
+				controller.Release()
+				return
+			}
 			select {
 			case <-ctx.Done():
+				controller.Release()
 				return
 			default:
 			}
 
+			startedAt := time.Now()
 			res, detail, err := Execute(ctx, ExecuteConfig{
 				Edition:    config.Edition,
 				Host:       candidate.host,
@@ -142,25 +189,55 @@ func LookupDomains(ctx context.Context, config LookupConfig) (LookupResult, erro
 				EnableSRV:  config.Options.EnableSRV,
 				IPMode:     config.Options.IPMode,
 			})
+			controller.Observe(time.Since(startedAt), err)
+			controller.Release()
 			currentCompleted := int(atomic.AddInt64(&completed, 1))
 			if config.Progress != nil {
 				config.Progress(LookupProgress{
-					Subdomain: candidate.subdomain,
-					Ending:    candidate.ending,
-					Host:      candidate.host,
-					Port:      candidate.port,
-					Attempt:   candidate.attempt,
-					Total:     total,
-					Completed: currentCompleted,
+					Subdomain:  candidate.subdomain,
+					Ending:     candidate.ending,
+					Host:       candidate.host,
+					Source:     candidate.source,
+					Port:       candidate.port,
+					Attempt:    candidate.attempt,
+					Total:      total,
+					Completed:  currentCompleted,
+					Workers:    controller.Limit(),
+					Adjustment: controller.Reason(),
 				})
 			}
 			if err != nil {
+				class := ClassifyError(err)
+				if class == FailureCanceled {
+					continue
+				}
+				failuresMu.Lock()
+				failures[class]++
+				if config.Unresponsive && detail.SelectedIP != "" {
+					unresponsive = append(unresponsive, LookupFailure{
+						Host:   candidate.host,
+						Port:   candidate.port,
+						Source: candidate.source,
+						Class:  class,
+						Err:    err,
+						Detail: detail,
+					})
+				}
+				failuresMu.Unlock()
 				continue
 			}
 			select {
 			case <-ctx.Done():
 				return
-			case results <- LookupMatch{Host: candidate.host, Port: candidate.port, Result: res, Detail: detail}:
+			case results <- LookupMatch{
+				Host:      candidate.host,
+				Port:      candidate.port,
+				Subdomain: candidate.subdomain,
+				Ending:    candidate.ending,
+				Source:    candidate.source,
+				Result:    res,
+				Detail:    detail,
+			}:
 			}
 		}
 	}
@@ -171,7 +248,7 @@ func LookupDomains(ctx context.Context, config LookupConfig) (LookupResult, erro
 	}
 
 	go func() {
-		enqueueLookupCandidates(ctx, candidates, subdomains, endings, ports, baseHost, limiter, config.Paused)
+		enqueueLookupCandidates(ctx, candidates, targets, subdomains, endings, ports, baseHost, limiter, config.Paused)
 	}()
 
 	go func() {
@@ -180,14 +257,27 @@ func LookupDomains(ctx context.Context, config LookupConfig) (LookupResult, erro
 	}()
 
 	matches := make([]LookupMatch, 0)
+	rejected := 0
 	for match := range results {
+		if config.Accept != nil && !config.Accept(match) {
+			rejected++
+			continue
+		}
 		matches = append(matches, match)
+		if config.Found != nil {
+			config.Found(match)
+		}
 	}
 
 	return LookupResult{
-		Matches:   matches,
-		Attempts:  total,
-		Completed: int(atomic.LoadInt64(&completed)),
+		Matches:      matches,
+		Attempts:     total,
+		Completed:    int(atomic.LoadInt64(&completed)),
+		Workers:      controller.Limit(),
+		PeakWorkers:  controller.Peak(),
+		Rejected:     rejected,
+		Failures:     failures,
+		Unresponsive: unresponsive,
 	}, ctx.Err()
 }
 
@@ -210,6 +300,47 @@ func normalizeLookupPorts(defaultPort int, values []int) []int {
 	return list
 }
 
+func normalizeLookupTargets(values []LookupTarget) []LookupTarget {
+	list := make([]LookupTarget, 0, len(values))
+	seen := make(map[string]struct{}, len(values))
+	for _, value := range values {
+		value.Host = strings.TrimSuffix(strings.TrimSpace(strings.ToLower(value.Host)), ".")
+		if value.Host == "" {
+			continue
+		}
+		if _, ok := seen[value.Host]; ok {
+			continue
+		}
+		seen[value.Host] = struct{}{}
+		list = append(list, value)
+	}
+	return list
+}
+
+func countGeneratedOverlap(targets []LookupTarget, subdomains, endings []string, baseHost string) int {
+	if len(targets) == 0 || baseHost == "" {
+		return 0
+	}
+	explicit := lookupTargetHosts(targets)
+	overlap := 0
+	for _, sub := range subdomains {
+		for _, ending := range endings {
+			if _, ok := explicit[buildHost(sub, baseHost, ending)]; ok {
+				overlap++
+			}
+		}
+	}
+	return overlap
+}
+
+func lookupTargetHosts(targets []LookupTarget) map[string]struct{} {
+	hosts := make(map[string]struct{}, len(targets))
+	for _, target := range targets {
+		hosts[target.Host] = struct{}{}
+	}
+	return hosts
+}
+
 func normalizeSubdomains(values []string) []string {
 	list := make([]string, 0, len(values))
 	seen := make(map[string]struct{}, len(values))
@@ -262,44 +393,65 @@ func buildHost(subdomain, baseHost, ending string) string {
 	return strings.Join(parts, ".")
 }
 
-func enqueueLookupCandidates(ctx context.Context, candidates chan<- lookupCandidate, subdomains, endings []string, ports []int, baseHost string, limiter <-chan time.Time, paused func() bool) {
+func enqueueLookupCandidates(ctx context.Context, candidates chan<- lookupCandidate, targets []LookupTarget, subdomains, endings []string, ports []int, baseHost string, limiter <-chan time.Time, paused func() bool) {
 	defer close(candidates)
 
 	attempt := 0
+	emit := func(candidate lookupCandidate) bool {
+		attempt++
+		candidate.attempt = attempt
+		if paused != nil {
+			for paused() {
+				select {
+				case <-ctx.Done():
+					return false
+				case <-time.After(120 * time.Millisecond):
+				}
+			}
+		}
+		if limiter != nil {
+			select {
+			case <-ctx.Done():
+				return false
+			case <-limiter:
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return false
+		case candidates <- candidate:
+			return true
+		}
+	}
+
+	for _, target := range targets {
+		for _, port := range ports {
+			candidate := lookupCandidate{
+				subdomain: target.Subdomain,
+				ending:    target.Ending,
+				host:      target.Host,
+				source:    target.Source,
+				port:      port,
+			}
+			if !emit(candidate) {
+				return
+			}
+		}
+	}
+	if baseHost == "" {
+		return
+	}
+	explicit := lookupTargetHosts(targets)
 	for _, sub := range subdomains {
 		for _, ending := range endings {
 			host := buildHost(sub, baseHost, ending)
+			if _, ok := explicit[host]; ok {
+				continue
+			}
 			for _, port := range ports {
-				attempt++
-				if paused != nil {
-					for paused() {
-						select {
-						case <-ctx.Done():
-							return
-						case <-time.After(120 * time.Millisecond):
-						}
-					}
-				}
-				if limiter != nil {
-					select {
-					case <-ctx.Done():
-						return
-					case <-limiter:
-					}
-				}
-
-				candidate := lookupCandidate{
-					subdomain: sub,
-					ending:    ending,
-					host:      host,
-					port:      port,
-					attempt:   attempt,
-				}
-
-				select {
-				case <-ctx.Done():
+				if !emit(lookupCandidate{subdomain: sub, ending: ending, host: host, source: LookupSourceGenerated, port: port}) {
 					return
-				case candidates <- candidate:
 				}
 			}
 		}
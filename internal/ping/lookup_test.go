@@ -13,7 +13,7 @@ func TestEnqueueLookupCandidatesWaitsForLimiter(t *testing.T) {
 	candidates := make(chan lookupCandidate, 2)
 	limiter := make(chan time.Time)
 
-	go enqueueLookupCandidates(ctx, candidates, []string{""}, []string{"com", "net"}, []int{19132}, "example", limiter, nil)
+	go enqueueLookupCandidates(ctx, candidates, nil, []string{""}, []string{"com", "net"}, []int{19132}, "example", limiter, nil)
 
 	select {
 	case candidate := <-candidates:
@@ -73,7 +73,7 @@ func TestEnqueueLookupCandidatesIncludesPorts(t *testing.T) {
 	defer cancel()
 
 	candidates := make(chan lookupCandidate, 4)
-	go enqueueLookupCandidates(ctx, candidates, []string{""}, []string{"com"}, []int{19132, 19133}, "example", nil, nil)
+	go enqueueLookupCandidates(ctx, candidates, nil, []string{""}, []string{"com"}, []int{19132, 19133}, "example", nil, nil)
 
 	first, ok := <-candidates
 	if !ok {
@@ -110,3 +110,32 @@ func TestDefaultLookupConcurrency(t *testing.T) {
 		t.Fatalf("expected no workers for empty lookup, got %d", got)
 	}
 }
+
+func TestEnqueueLookupCandidatesProbesExplicitTargetsFirst(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	targets := []LookupTarget{
+		{Host: "192.0.2.10", Source: "network"},
+		{Host: "play.example.com", Subdomain: "play", Ending: "com", Source: "crt.json"},
+	}
+	candidates := make(chan lookupCandidate, 8)
+	go enqueueLookupCandidates(ctx, candidates, targets, []string{"", "play"}, []string{"com"}, []int{19132}, "example", nil, nil)
+
+	var got []lookupCandidate
+	for candidate := range candidates {
+		got = append(got, candidate)
+	}
+	if len(got) != 3 {
+		t.Fatalf("expected generated duplicate to be skipped, got %+v", got)
+	}
+	if got[0].host != "192.0.2.10" || got[1].host != "play.example.com" || got[2].host != "example.com" {
+		t.Fatalf("unexpected candidate order: %+v", got)
+	}
+	if got[1].source != "crt.json" || got[2].source != LookupSourceGenerated {
+		t.Fatalf("unexpected candidate sources: %q, %q", got[1].source, got[2].source)
+	}
+	if overlap := countGeneratedOverlap(normalizeLookupTargets(targets), []string{"", "play"}, []string{"com"}, "example"); overlap != 1 {
+		t.Fatalf("expected one overlapping combination, got %d", overlap)
+	}
+}
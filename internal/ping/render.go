@@ -0,0 +1,227 @@
+package ping
+
+import (
+	"encoding/base64"
+	"fmt"
+	"html"
+	"strings"
+)
+
+const ObfuscatedClass = "mc-obfuscated"
+
+type formatState struct {
+	color      string
+	bold       bool
+	italic     bool
+	underline  bool
+	strike     bool
+	obfuscated bool
+}
+
+func (s formatState) plain() bool {
+	return s == formatState{}
+}
+
+func minecraftColorHex(code rune) string {
+	switch code {
+	case '0':
+		return "#000000"
+	case '1':
+		return "#0000AA"
+	case '2':
+		return "#00AA00"
+	case '3':
+		return "#00AAAA"
+	case '4':
+		return "#AA0000"
+	case '5':
+		return "#AA00AA"
+	case '6':
+		return "#FFAA00"
+	case '7':
+		return "#AAAAAA"
+	case '8':
+		return "#555555"
+	case '9':
+		return "#5555FF"
+	case 'a':
+		return "#55FF55"
+	case 'b':
+		return "#55FFFF"
+	case 'c':
+		return "#FF5555"
+	case 'd':
+		return "#FF55FF"
+	case 'e':
+		return "#FFFF55"
+	case 'f':
+		return "#FFFFFF"
+	default:
+		return ""
+	}
+}
+
+func walkFormatting(s string, emit func(text string, state formatState)) {
+	var state formatState
+	var run strings.Builder
+	flush := func() {
+		if run.Len() > 0 {
+			emit(run.String(), state)
+			run.Reset()
+		}
+	}
+	runes := []rune(s)
+	for i := 0; i < len(runes); i++ {
+		if runes[i] != '§' || i+1 >= len(runes) {
+			run.WriteRune(runes[i])
+			continue
+		}
+		i++
+		code := runes[i]
+		if code >= 'A' && code <= 'Z' {
+			code += 'a' - 'A'
+		}
+		next := state
+		if color := minecraftColorHex(code); color != "" {
+			next = formatState{color: color}
+		} else {
+			switch code {
+			case 'k':
+				next.obfuscated = true
+			case 'l':
+				next.bold = true
+			case 'm':
+				next.strike = true
+			case 'n':
+				next.underline = true
+			case 'o':
+				next.italic = true
+			case 'r':
+				next = formatState{}
+			default:
+				continue
+			}
+		}
+		if next != state {
+			flush()
+			state = next
+		}
+	}
+	flush()
+}
+
+func formatStyle(state formatState, colorProperty string) string {
+	var parts []string
+	if state.color != "" {
+		parts = append(parts, colorProperty+":"+state.color)
+	}
+	if state.bold {
+		parts = append(parts, "font-weight:bold")
+	}
+	if state.italic {
+		parts = append(parts, "font-style:italic")
+	}
+	var decorations []string
+	if state.underline {
+		decorations = append(decorations, "underline")
+	}
+	if state.strike {
+		decorations = append(decorations, "line-through")
+	}
+	if len(decorations) > 0 {
+		parts = append(parts, "text-decoration:"+strings.Join(decorations, " "))
+	}
+	return strings.Join(parts, ";")
+}
+
+func RenderFormattingHTML(s string) string {
+	var builder strings.Builder
+	walkFormatting(s, func(text string, state formatState) {
+		escaped := strings.ReplaceAll(html.EscapeString(text), "\n", "<br>")
+		if state.plain() {
+			builder.WriteString(escaped)
+			return
+		}
+		builder.WriteString("<span")
+		if state.obfuscated {
+			builder.WriteString(` class="` + ObfuscatedClass + `"`)
+		}
+		if style := formatStyle(state, "color"); style != "" {
+			builder.WriteString(` style="` + style + `"`)
+		}
+		builder.WriteString(">" + escaped + "</span>")
+	})
+	return builder.String()
+}
+
+type Banner struct {
+	Name          string
+	MOTD          string
+	IconPNG       []byte
+	Online        bool
+	Players       int
+	MaxPlayers    int
+	LatencyMillis int64
+}
+
+const (
+	bannerWidth    = 700
+	bannerHeight   = 88
+	bannerTextLeft = 88
+)
+
+func RenderBannerSVG(banner Banner) string {
+	var builder strings.Builder
+	fmt.Fprintf(&builder, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="Minecraft, Monocraft, monospace" font-size="16">`, bannerWidth, bannerHeight, bannerWidth, bannerHeight)
+	builder.WriteString(`<style>.` + ObfuscatedClass + `{opacity:.6}</style>`)
+	fmt.Fprintf(&builder, `<rect width="%d" height="%d" rx="4" fill="#1b1b1b" stroke="#555555"/>`, bannerWidth, bannerHeight)
+	if len(banner.IconPNG) > 0 {
+		fmt.Fprintf(&builder, `<image x="12" y="12" width="64" height="64" style="image-rendering:pixelated" href="data:image/png;base64,%s"/>`, base64.StdEncoding.EncodeToString(banner.IconPNG))
+	} else {
+		builder.WriteString(`<rect x="12" y="12" width="64" height="64" fill="#3a3a3a"/>`)
+	}
+	fmt.Fprintf(&builder, `<text x="%d" y="30" fill="#FFFFFF">%s</text>`, bannerTextLeft, html.EscapeString(StripFormatting(banner.Name)))
+
+	status := "Can't connect to server"
+	statusColor := "#AA0000"
+	if banner.Online {
+		status = fmt.Sprintf("%d/%d", banner.Players, banner.MaxPlayers)
+		statusColor = "#AAAAAA"
+		if banner.LatencyMillis > 0 {
+			status = fmt.Sprintf("%s  %dms", status, banner.LatencyMillis)
+		}
+	}
+	fmt.Fprintf(&builder, `<text x="%d" y="30" text-anchor="end" fill="%s">%s</text>`, bannerWidth-12, statusColor, html.EscapeString(status))
+
+	if banner.Online {
+		lines := strings.SplitN(banner.MOTD, "\n", 3)
+		if len(lines) > 2 {
+			lines = lines[:2]
+		}
+		for i, line := range lines {
+			fmt.Fprintf(&builder, `<text x="%d" y="%d" fill="#AAAAAA" xml:space="preserve">%s</text>`, bannerTextLeft, 54+i*20, svgFormattingSpans(line))
+		}
+	}
+	builder.WriteString("</svg>")
+	return builder.String()
+}
+
+func svgFormattingSpans(s string) string {
+	var builder strings.Builder
+	walkFormatting(s, func(text string, state formatState) {
+		escaped := html.EscapeString(text)
+		if state.plain() {
+			builder.WriteString(escaped)
+			return
+		}
+		builder.WriteString("<tspan")
+		if state.obfuscated {
+			builder.WriteString(` class="` + ObfuscatedClass + `"`)
+		}
+		if style := formatStyle(state, "fill"); style != "" {
+			builder.WriteString(` style="` + style + `"`)
+		}
+		builder.WriteString(">" + escaped + "</tspan>")
+	})
+	return builder.String()
+}
@@ -0,0 +1,52 @@
+package ping
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderFormattingHTML(t *testing.T) {
+	cases := map[string]string{
+		"plain <b>":           "plain &lt;b&gt;",
+		"§aGreen§r text":      `<span style="color:#55FF55">Green</span> text`,
+		"§c§lBold §nred":      `<span style="color:#FF5555;font-weight:bold">Bold </span><span style="color:#FF5555;font-weight:bold;text-decoration:underline">red</span>`,
+		"§lbold§eyellow":      `<span style="font-weight:bold">bold</span><span style="color:#FFFF55">yellow</span>`,
+		"§kxx§r§o§mit\nline2": `<span class="mc-obfuscated">xx</span><span style="font-style:italic;text-decoration:line-through">it<br>line2</span>`,
+		"trailing §":          "trailing §",
+		"§zunknown":           "unknown",
+	}
+	for input, want := range cases {
+		if got := RenderFormattingHTML(input); got != want {
+			t.Fatalf("RenderFormattingHTML(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+func TestRenderBannerSVG(t *testing.T) {
+	svg := RenderBannerSVG(Banner{
+		Name:       "play.example.com",
+		MOTD:       "§aHello & welcome\n§7second\nthird",
+		IconPNG:    []byte{0x89, 'P', 'N', 'G'},
+		Online:     true,
+		Players:    12,
+		MaxPlayers: 100,
+	})
+	for _, want := range []string{
+		`<svg xmlns="http://www.w3.org/2000/svg"`,
+		`href="data:image/png;base64,iVBORw=="`,
+		">play.example.com</text>",
+		">12/100</text>",
+		`<tspan style="fill:#55FF55">Hello &amp; welcome</tspan>`,
+	} {
+		if !strings.Contains(svg, want) {
+			t.Fatalf("banner missing %q:\n%s", want, svg)
+		}
+	}
+	if strings.Contains(svg, "third") {
+		t.Fatalf("banner should only render two MOTD lines:\n%s", svg)
+	}
+	offline := RenderBannerSVG(Banner{Name: "down.example.com"})
+	if !strings.Contains(offline, "Can&#39;t connect to server") {
+		t.Fatalf("offline banner missing status:\n%s", offline)
+	}
+}
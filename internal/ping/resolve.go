@@ -19,12 +19,12 @@ const (
 
 func resolveIP(ctx context.Context, host string, mode IPMode) (string, []string, error) {
 	if host == "" {
-		return "", nil, fmt.Errorf("host cannot be empty")
+		return "", nil, classifyFailure(FailureDNS, fmt.Errorf("host cannot be empty"))
 	}
 	ip := net.ParseIP(host)
 	if ip != nil {
 		if !matchesMode(ip, mode) {
-			return "", nil, fmt.Errorf("host does not match IP mode %s", mode)
+			return "", nil, classifyFailure(FailureDNS, fmt.Errorf("host does not match IP mode %s", mode))
 		}
 		ipText := ip.String()
 		return ipText, []string{ipText}, nil
@@ -35,13 +35,13 @@ func resolveIP(ctx context.Context, host string, mode IPMode) (string, []string,
 	case IPModeIPv4:
 		ips, err := resolver.LookupIP(ctx, "ip4", host)
 		if err != nil {
-			return "", nil, err
+			return "", nil, dnsFailure(err)
 		}
 		return pickIP(host, ips)
 	case IPModeIPv6:
 		ips, err := resolver.LookupIP(ctx, "ip6", host)
 		if err != nil {
-			return "", nil, err
+			return "", nil, dnsFailure(err)
 		}
 		return pickIP(host, ips)
 	default:
@@ -52,12 +52,12 @@ func resolveIP(ctx context.Context, host string, mode IPMode) (string, []string,
 		if err != nil {
 			var dnsErr *net.DNSError
 			if !errors.As(err, &dnsErr) || !dnsErr.IsNotFound {
-				return "", nil, err
+				return "", nil, dnsFailure(err)
 			}
 		}
 		ips, err = resolver.LookupIP(ctx, "ip6", host)
 		if err != nil {
-			return "", nil, err
+			return "", nil, dnsFailure(err)
 		}
 		return pickIP(host, ips)
 	}
@@ -65,7 +65,7 @@ func resolveIP(ctx context.Context, host string, mode IPMode) (string, []string,
 
 func pickIP(host string, ips []net.IP) (string, []string, error) {
 	if len(ips) == 0 {
-		return "", nil, fmt.Errorf("no IP address found for %s", host)
+		return "", nil, classifyFailure(FailureNXDomain, fmt.Errorf("no IP address found for %s", host))
 	}
 	list := make([]string, 0, len(ips))
 	for _, ip := range ips {
@@ -0,0 +1,340 @@
+package web
+
+import (
+	"encoding/base64"
+	"fmt"
+	"html/template"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"UWP-TCP-Con/internal/ping"
+)
+
+type DashboardRow struct {
+	Name          string
+	Edition       string
+	Host          string
+	Port          int
+	Online        bool
+	Players       int
+	MaxPlayers    int
+	Version       string
+	MOTD          string
+	LatencyMillis int64
+	Source        string
+	Error         string
+	IconPNG       []byte
+}
+
+type Dashboard struct {
+	Title       string
+	GeneratedAt time.Time
+	Rows        []DashboardRow
+}
+
+type ResultFile struct {
+	Name     string
+	Size     int64
+	Modified time.Time
+}
+
+type dashboardRowView struct {
+	Index      int
+	Row        DashboardRow
+	Target     string
+	MOTD       template.HTML
+	IconURL    template.URL
+	AddURL     template.URL
+	ConnectURL template.URL
+}
+
+type dashboardView struct {
+	Title       string
+	GeneratedAt string
+	Rows        []dashboardRowView
+	Online      int
+	HasResults  bool
+	Report      bool
+}
+
+type resultsView struct {
+	Path  string
+	Files []ResultFile
+	Error string
+}
+
+func bedrockAddURI(name, host string, port int) string {
+	addValue := fmt.Sprintf("%s|%s:%d", name, host, port)
+	return "minecraft://?addExternalServer=" + url.QueryEscape(addValue)
+}
+
+func bedrockConnectURI(host string, port int) string {
+	return fmt.Sprintf("minecraft://connect/?serverUrl=%s&serverPort=%d", url.QueryEscape(host), port)
+}
+
+func (s *LinkServer) SetDashboard(dashboard Dashboard) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.dashboard = &dashboard
+}
+
+func (s *LinkServer) SetResultsPath(path string) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.resultsPath = path
+}
+
+func (s *LinkServer) snapshot() (*Dashboard, string) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.dashboard, s.resultsPath
+}
+
+func (s *LinkServer) serveDashboard(w http.ResponseWriter, r *http.Request) {
+	dashboard, resultsPath := s.snapshot()
+	view := newDashboardView(dashboard, false)
+	view.HasResults = resultsPath != ""
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := dashboardTemplate.Execute(w, view); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func WriteReport(w io.Writer, dashboard Dashboard) error {
+	return dashboardTemplate.Execute(w, newDashboardView(&dashboard, true))
+}
+
+func newDashboardView(dashboard *Dashboard, report bool) dashboardView {
+	view := dashboardView{Report: report}
+	if dashboard == nil {
+		return view
+	}
+	view.Title = dashboard.Title
+	view.GeneratedAt = dashboard.GeneratedAt.Format("2006-01-02 15:04:05")
+	for i, row := range dashboard.Rows {
+		item := dashboardRowView{
+			Index:  i,
+			Row:    row,
+			Target: fmt.Sprintf("%s:%d", row.Host, row.Port),
+			MOTD:   template.HTML(ping.RenderFormattingHTML(row.MOTD)),
+		}
+		if len(row.IconPNG) > 0 {
+			item.IconURL = template.URL(fmt.Sprintf("/icon/%d", i))
+			if report {
+				item.IconURL = template.URL("data:image/png;base64," + base64.StdEncoding.EncodeToString(row.IconPNG))
+			}
+		}
+		if row.Edition == "bedrock" && row.Online {
+			name := row.Name
+			if name == "" {
+				name = row.Host
+			}
+			item.AddURL = template.URL(bedrockAddURI(name, row.Host, row.Port))
+			item.ConnectURL = template.URL(bedrockConnectURI(row.Host, row.Port))
+		}
+		if row.Online {
+			view.Online++
+		}
+		view.Rows = append(view.Rows, item)
+	}
+	return view
+}
+
+func (s *LinkServer) serveIcon(w http.ResponseWriter, r *http.Request, value string) {
+	dashboard, _ := s.snapshot()
+	index, err := strconv.Atoi(value)
+	if dashboard == nil || err != nil || index < 0 || index >= len(dashboard.Rows) || len(dashboard.Rows[index].IconPNG) == 0 {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "image/png")
+	w.Header().Set("Cache-Control", "max-age=3600")
+	_, _ = w.Write(dashboard.Rows[index].IconPNG)
+}
+
+func (s *LinkServer) serveBanner(w http.ResponseWriter, r *http.Request, value string) {
+	dashboard, _ := s.snapshot()
+	index, err := strconv.Atoi(strings.TrimSuffix(value, ".svg"))
+	if dashboard == nil || err != nil || index < 0 || index >= len(dashboard.Rows) {
+		http.NotFound(w, r)
+		return
+	}
+	row := dashboard.Rows[index]
+	name := row.Name
+	if name == "" {
+		name = fmt.Sprintf("%s:%d", row.Host, row.Port)
+	}
+	w.Header().Set("Content-Type", "image/svg+xml")
+	_, _ = io.WriteString(w, ping.RenderBannerSVG(ping.Banner{
+		Name:          name,
+		MOTD:          row.MOTD,
+		IconPNG:       row.IconPNG,
+		Online:        row.Online,
+		Players:       row.Players,
+		MaxPlayers:    row.MaxPlayers,
+		LatencyMillis: row.LatencyMillis,
+	}))
+}
+
+func (s *LinkServer) serveResults(w http.ResponseWriter, r *http.Request, name string) {
+	_, resultsPath := s.snapshot()
+	if resultsPath == "" {
+		http.NotFound(w, r)
+		return
+	}
+	if name != "" {
+		if name != filepath.Base(name) || strings.HasPrefix(name, ".") {
+			http.NotFound(w, r)
+			return
+		}
+		path := filepath.Join(resultsPath, name)
+		info, err := os.Stat(path)
+		if err != nil || info.IsDir() {
+			http.NotFound(w, r)
+			return
+		}
+		switch strings.ToLower(filepath.Ext(name)) {
+		case ".txt", ".log", ".csv", ".ndjson", ".md":
+			w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		}
+		http.ServeFile(w, r, path)
+		return
+	}
+	view := resultsView{Path: resultsPath}
+	files, err := ListResultFiles(resultsPath)
+	if err != nil && !os.IsNotExist(err) {
+		view.Error = err.Error()
+	}
+	view.Files = files
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := resultsTemplate.Execute(w, view); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func ListResultFiles(dir string) ([]ResultFile, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	files := make([]ResultFile, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		files = append(files, ResultFile{Name: entry.Name(), Size: info.Size(), Modified: info.ModTime()})
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].Modified.After(files[j].Modified) })
+	return files, nil
+}
+
+var dashboardFuncs = template.FuncMap{
+	"kb": func(size int64) string {
+		if size < 1024 {
+			return fmt.Sprintf("%d B", size)
+		}
+		return fmt.Sprintf("%.1f KB", float64(size)/1024)
+	},
+	"stamp": func(value time.Time) string {
+		return value.Format("2006-01-02 15:04:05")
+	},
+	"path":  url.PathEscape,
+	"plain": ping.StripFormatting,
+}
+
+const dashboardStyle = `<style>
+body{font-family:system-ui,sans-serif;margin:24px;background:#1e1f22;color:#ddd}
+a{color:#7ab7ff}
+h1{font-size:20px;margin:0 0 4px}
+.meta{color:#999;margin-bottom:16px}
+input{background:#2b2d31;color:#ddd;border:1px solid #444;padding:6px 8px;width:320px}
+table{border-collapse:collapse;width:100%;margin-top:12px}
+th,td{padding:6px 8px;border-bottom:1px solid #333;text-align:left;vertical-align:middle}
+th{cursor:pointer;user-select:none;color:#aaa}
+th.sorted-asc::after{content:" ▲"}
+th.sorted-desc::after{content:" ▼"}
+img.icon{width:32px;height:32px;image-rendering:pixelated}
+.motd{font-family:"Minecraft",monospace;background:#000;padding:4px 6px;color:#aaa}
+.mc-obfuscated{animation:mc-obfuscated .2s steps(2) infinite}
+@keyframes mc-obfuscated{50%{opacity:.35}}
+.down{color:#ff6b6b}
+.up{color:#69db7c}
+.button{display:inline-block;padding:2px 8px;border:1px solid #555;border-radius:4px;margin-right:4px;text-decoration:none}
+</style>`
+
+var dashboardTemplate = template.Must(template.New("dashboard").Funcs(dashboardFuncs).Parse(`<!doctype html>
+<html><head><meta charset="utf-8"><title>MCQuery {{if .Report}}report{{else}}dashboard{{end}}</title>` + dashboardStyle + `</head><body>
+<h1>MCQuery{{if .Title}}: {{.Title}}{{end}}</h1>
+<div class="meta">{{if .Rows}}{{len .Rows}} servers, {{.Online}} online, generated {{.GeneratedAt}}{{else}}No results yet. Run a lookup or batch check in the terminal.{{end}}{{if .HasResults}} · <a href="/results/">Saved results</a>{{end}}</div>
+{{if .Rows}}<input id="filter" type="search" placeholder="Filter by host, version, MOTD…" autofocus>
+<table id="results"><thead><tr>
+<th data-type="text"></th><th data-type="text">Server</th><th data-type="text">Edition</th><th data-type="text">Status</th><th data-type="number">Players</th><th data-type="text">Version</th><th data-type="number">Latency</th><th data-type="text">MOTD</th><th data-type="text">Source</th><th></th>
+</tr></thead><tbody>
+{{range .Rows}}<tr>
+<td data-value="">{{if .IconURL}}<img class="icon" src="{{.IconURL}}" alt="">{{end}}</td>
+<td data-value="{{.Target}}">{{if and .Row.Name (ne .Row.Name .Row.Host)}}{{.Row.Name}}<br>{{end}}{{.Target}}</td>
+<td data-value="{{.Row.Edition}}">{{.Row.Edition}}</td>
+<td data-value="{{if .Row.Online}}up{{else}}down{{end}}">{{if .Row.Online}}<span class="up">online</span>{{else}}<span class="down">{{if .Row.Error}}{{.Row.Error}}{{else}}offline{{end}}</span>{{end}}</td>
+<td data-value="{{.Row.Players}}">{{if .Row.Online}}{{.Row.Players}}/{{.Row.MaxPlayers}}{{end}}</td>
+<td data-value="{{.Row.Version}}">{{.Row.Version}}</td>
+<td data-value="{{.Row.LatencyMillis}}">{{if .Row.LatencyMillis}}{{.Row.LatencyMillis}} ms{{end}}</td>
+<td data-value="{{plain .Row.MOTD}}"><div class="motd">{{.MOTD}}</div></td>
+<td data-value="{{.Row.Source}}">{{.Row.Source}}</td>
+<td>{{if not $.Report}}<a class="button" href="/banner/{{.Index}}.svg">Banner</a>{{end}}{{if .AddURL}}<a class="button" href="{{.AddURL}}">Add</a><a class="button" href="{{.ConnectURL}}">Join</a>{{end}}</td>
+</tr>{{end}}
+</tbody></table>
+<script>
+(function(){
+  var table=document.getElementById("results"),body=table.tBodies[0],headers=table.tHead.rows[0].cells;
+  document.getElementById("filter").addEventListener("input",function(e){
+    var needle=e.target.value.toLowerCase();
+    Array.prototype.forEach.call(body.rows,function(row){
+      row.style.display=row.textContent.toLowerCase().indexOf(needle)>=0?"":"none";
+    });
+  });
+  Array.prototype.forEach.call(headers,function(header,column){
+    header.addEventListener("click",function(){
+      var asc=!header.classList.contains("sorted-asc"),numeric=header.dataset.type==="number";
+      Array.prototype.forEach.call(headers,function(h){h.classList.remove("sorted-asc","sorted-desc")});
+      header.classList.add(asc?"sorted-asc":"sorted-desc");
+      var rows=Array.prototype.slice.call(body.rows);
+      rows.sort(function(a,b){
+        var x=a.cells[column].dataset.value||"",y=b.cells[column].dataset.value||"";
+        var result=numeric?(parseFloat(x)||0)-(parseFloat(y)||0):x.localeCompare(y,undefined,{numeric:true});
+        return asc?result:-result;
+      });
+      rows.forEach(function(row){body.appendChild(row)});
+    });
+  });
+})();
+</script>{{end}}
+</body></html>
+`))
+
+var resultsTemplate = template.Must(template.New("results").Funcs(dashboardFuncs).Parse(`<!doctype html>
+<html><head><meta charset="utf-8"><title>MCQuery results</title>` + dashboardStyle + `</head><body>
+<h1>Saved results</h1>
+<div class="meta">{{.Path}} · <a href="/">Dashboard</a></div>
+{{if .Error}}<p class="down">{{.Error}}</p>{{end}}
+{{if .Files}}<table><thead><tr><th>File</th><th>Size</th><th>Modified</th></tr></thead><tbody>
+{{range .Files}}<tr><td><a href="/results/{{path .Name}}">{{.Name}}</a></td><td>{{kb .Size}}</td><td>{{stamp .Modified}}</td></tr>{{end}}
+</tbody></table>{{else}}<p>No saved results yet.</p>{{end}}
+</body></html>
+`))
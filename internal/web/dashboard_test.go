@@ -0,0 +1,113 @@
+package web
+
+import (
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDashboardServer(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "result.json"), []byte(`{"ok":true}`), 0o644); err != nil {
+		t.Fatalf("write result: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(filepath.Dir(dir), "secret.txt"), []byte("secret"), 0o644); err != nil {
+		t.Fatalf("write secret: %v", err)
+	}
+
+	server, err := StartLookupLinkServer(nil, 0)
+	if err != nil {
+		t.Fatalf("StartLookupLinkServer: %v", err)
+	}
+	defer server.Close()
+	server.SetResultsPath(dir)
+	server.SetDashboard(Dashboard{
+		Title:       "Lookup",
+		GeneratedAt: time.Now(),
+		Rows: []DashboardRow{
+			{Edition: "bedrock", Host: "play.example.com", Port: 19132, Online: true, MOTD: "§bWelcome"},
+			{Edition: "java", Host: "java.example.com", Port: 25565, Online: true, IconPNG: []byte("\x89PNG")},
+		},
+	})
+
+	body := fetch(t, server.URL+"/", http.StatusOK)
+	for _, want := range []string{"minecraft://?addExternalServer=", "minecraft://connect/?serverUrl=play.example.com", `<span style="color:#55FFFF">Welcome</span>`, `/icon/1`} {
+		if !strings.Contains(body, want) {
+			t.Fatalf("dashboard missing %q:\n%s", want, body)
+		}
+	}
+	if body := fetch(t, server.URL+"/icon/1", http.StatusOK); body != "\x89PNG" {
+		t.Fatalf("unexpected icon body %q", body)
+	}
+	fetch(t, server.URL+"/icon/0", http.StatusNotFound)
+	if body := fetch(t, server.URL+"/banner/0.svg", http.StatusOK); !strings.HasPrefix(body, "<svg") || !strings.Contains(body, "Welcome") {
+		t.Fatalf("unexpected banner:\n%s", body)
+	}
+	if body := fetch(t, server.URL+"/results/", http.StatusOK); !strings.Contains(body, "result.json") {
+		t.Fatalf("results listing missing file:\n%s", body)
+	}
+	fetch(t, server.URL+"/results/result.json", http.StatusOK)
+	fetch(t, server.URL+"/results/..%2Fsecret.txt", http.StatusNotFound)
+
+	port := strings.TrimPrefix(server.URL, "http://127.0.0.1:")
+	fetch(t, "http://localhost:"+port+"/", http.StatusOK)
+	request, err := http.NewRequest(http.MethodGet, server.URL+"/results/result.json", nil)
+	if err != nil {
+		t.Fatalf("NewRequest: %v", err)
+	}
+	request.Host = "attacker.example:" + port
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		t.Fatalf("GET with foreign host: %v", err)
+	}
+	response.Body.Close()
+	if response.StatusCode != http.StatusForbidden {
+		t.Fatalf("expected foreign Host to be rejected, got %d", response.StatusCode)
+	}
+}
+
+func fetch(t *testing.T, url string, status int) string {
+	t.Helper()
+	response, err := http.Get(url)
+	if err != nil {
+		t.Fatalf("GET %s: %v", url, err)
+	}
+	defer response.Body.Close()
+	data, err := io.ReadAll(response.Body)
+	if err != nil {
+		t.Fatalf("read %s: %v", url, err)
+	}
+	if response.StatusCode != status {
+		t.Fatalf("GET %s: expected %d, got %d", url, status, response.StatusCode)
+	}
+	return string(data)
+}
+
+func TestWriteReport(t *testing.T) {
+	var builder strings.Builder
+	err := WriteReport(&builder, Dashboard{
+		Title:       "Batch check",
+		GeneratedAt: time.Now(),
+		Rows: []DashboardRow{
+			{Edition: "java", Host: "java.example.com", Port: 25565, Online: true, MOTD: "§bWelcome", IconPNG: []byte("\x89PNG")},
+		},
+	})
+	if err != nil {
+		t.Fatalf("WriteReport: %v", err)
+	}
+	body := builder.String()
+	for _, want := range []string{"<title>MCQuery report</title>", `src="data:image/png;base64,iVBORw=="`, `<span style="color:#55FFFF">Welcome</span>`} {
+		if !strings.Contains(body, want) {
+			t.Fatalf("report missing %q:\n%s", want, body)
+		}
+	}
+	for _, unwanted := range []string{"/icon/", "/banner/", "/results/"} {
+		if strings.Contains(body, unwanted) {
+			t.Fatalf("report links to the local server (%q):\n%s", unwanted, body)
+		}
+	}
+}
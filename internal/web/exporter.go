@@ -0,0 +1,272 @@
+package web
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"UWP-TCP-Con/internal/ping"
+)
+
+const (
+	exporterWorkers       = 16
+	defaultScrapeDeadline = 10 * time.Second
+)
+
+type ProbeTarget struct {
+	Name    string
+	Edition ping.Edition
+	Host    string
+	Port    int
+}
+
+type ProbeSample struct {
+	Target      ProbeTarget
+	Up          bool
+	Players     int
+	MaxPlayers  int
+	Latency     time.Duration
+	Protocol    int
+	Version     string
+	SRVUsed     bool
+	ResolveTime time.Duration
+	Duration    time.Duration
+	ErrorClass  ping.FailureClass
+}
+
+type Exporter struct {
+	targets []ProbeTarget
+	options ping.ExecuteOptions
+	execute func(ctx context.Context, config ping.ExecuteConfig) (ping.Result, ping.ExecuteDetails, error)
+}
+
+type metricFamily struct {
+	name  string
+	help  string
+	value func(sample ProbeSample) (float64, bool)
+}
+
+var probeMetricFamilies = []metricFamily{
+	{"mcquery_up", "Whether the server answered the status request.", func(s ProbeSample) (float64, bool) { return boolMetric(s.Up), true }},
+	{"mcquery_players_online", "Players currently online.", func(s ProbeSample) (float64, bool) { return float64(s.Players), s.Up }},
+	{"mcquery_players_max", "Maximum player slots.", func(s ProbeSample) (float64, bool) { return float64(s.MaxPlayers), s.Up }},
+	{"mcquery_latency_seconds", "Status round-trip latency.", func(s ProbeSample) (float64, bool) { return s.Latency.Seconds(), s.Up }},
+	{"mcquery_protocol_version", "Protocol version reported by the server.", func(s ProbeSample) (float64, bool) { return float64(s.Protocol), s.Up }},
+	{"mcquery_srv_used", "Whether a Java SRV record redirected the probe.", func(s ProbeSample) (float64, bool) { return boolMetric(s.SRVUsed), true }},
+	{"mcquery_resolve_duration_seconds", "Time spent on SRV and address resolution.", func(s ProbeSample) (float64, bool) { return s.ResolveTime.Seconds(), s.ResolveTime > 0 }},
+	{"mcquery_probe_duration_seconds", "Total probe duration including retries.", func(s ProbeSample) (float64, bool) { return s.Duration.Seconds(), true }},
+}
+
+func NewExporter(targets []ProbeTarget, options ping.ExecuteOptions) *Exporter {
+	return &Exporter{
+		targets: append([]ProbeTarget(nil), targets...),
+		options: options,
+		execute: ping.Execute,
+	}
+}
+
+func (e *Exporter) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		_, _ = io.WriteString(w, "MCQuery exporter\n\n/metrics                                  configured targets\n/probe?target=host:port&edition=java      probe a single server\n")
+	})
+	mux.HandleFunc("/metrics", e.handleMetrics)
+	mux.HandleFunc("/probe", e.handleProbe)
+	return mux
+}
+
+func (e *Exporter) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), scrapeDeadline(r))
+	defer cancel()
+	samples := e.ProbeAll(ctx)
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	WriteProbeMetrics(w, samples)
+	fmt.Fprintln(w, "# HELP mcquery_exporter_targets Number of configured targets.")
+	fmt.Fprintln(w, "# TYPE mcquery_exporter_targets gauge")
+	fmt.Fprintf(w, "mcquery_exporter_targets %d\n", len(e.targets))
+}
+
+func (e *Exporter) handleProbe(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	edition := ping.Edition(strings.ToLower(strings.TrimSpace(query.Get("edition"))))
+	if edition == "" {
+		edition = ping.EditionJava
+	}
+	if edition != ping.EditionJava && edition != ping.EditionBedrock {
+		http.Error(w, "edition must be java or bedrock", http.StatusBadRequest)
+		return
+	}
+	target, err := ParseProbeTarget(query.Get("target"), edition)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), scrapeDeadline(r))
+	defer cancel()
+	sample := e.Probe(ctx, target)
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	WriteProbeMetrics(w, []ProbeSample{sample})
+}
+
+func (e *Exporter) ProbeAll(ctx context.Context) []ProbeSample {
+	samples := make([]ProbeSample, len(e.targets))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	workers := exporterWorkers
+	if len(e.targets) < workers {
+		workers = len(e.targets)
+	}
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range jobs {
+				samples[index] = e.Probe(ctx, e.targets[index])
+			}
+		}()
+	}
+	for i := range e.targets {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	return samples
+}
+
+func (e *Exporter) Probe(ctx context.Context, target ProbeTarget) ProbeSample {
+	startedAt := time.Now()
+	result, details, err := e.execute(ctx, ping.ExecuteConfig{
+		Edition:    target.Edition,
+		Host:       target.Host,
+		Port:       target.Port,
+		Timeout:    e.options.Timeout,
+		RetryCount: e.options.RetryCount,
+		RetryDelay: e.options.RetryDelay,
+		EnableSRV:  e.options.EnableSRV,
+		IPMode:     e.options.IPMode,
+	})
+	sample := ProbeSample{
+		Target:      target,
+		Duration:    time.Since(startedAt),
+		SRVUsed:     details.SRVUsed,
+		ResolveTime: details.ResolveTime,
+	}
+	if err != nil {
+		sample.ErrorClass = ping.ClassifyError(err)
+		return sample
+	}
+	sample.Up = true
+	switch value := result.(type) {
+	case ping.JavaStatus:
+		sample.Players = value.CurrentPlayers
+		sample.MaxPlayers = value.MaxPlayers
+		sample.Protocol = value.ProtocolVersion
+		sample.Version = value.VersionName
+		sample.Latency = time.Duration(value.LatencyMillis) * time.Millisecond
+	case ping.BedrockPong:
+		sample.Players, _ = strconv.Atoi(strings.TrimSpace(value.CurrentPlayers))
+		sample.MaxPlayers, _ = strconv.Atoi(strings.TrimSpace(value.MaxPlayers))
+		sample.Protocol, _ = strconv.Atoi(strings.TrimSpace(value.ProtocolVersion))
+		sample.Version = value.GameVersion
+		sample.Latency = sample.Duration - sample.ResolveTime
+	}
+	return sample
+}
+
+func ParseProbeTarget(value string, edition ping.Edition) (ProbeTarget, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return ProbeTarget{}, fmt.Errorf("target parameter is required")
+	}
+	host, portText, err := net.SplitHostPort(value)
+	if err != nil {
+		host = strings.Trim(value, "[]")
+		portText = ""
+	}
+	if host == "" {
+		return ProbeTarget{}, fmt.Errorf("target host is empty")
+	}
+	port, err := ping.ParsePort(portText)
+	if err != nil {
+		return ProbeTarget{}, fmt.Errorf("target %s: %w", value, err)
+	}
+	if port == 0 {
+		port = ping.DefaultPort(edition)
+	}
+	return ProbeTarget{Name: value, Edition: edition, Host: host, Port: port}, nil
+}
+
+func WriteProbeMetrics(w io.Writer, samples []ProbeSample) {
+	for _, family := range probeMetricFamilies {
+		fmt.Fprintf(w, "# HELP %s %s\n", family.name, family.help)
+		fmt.Fprintf(w, "# TYPE %s gauge\n", family.name)
+		for _, sample := range samples {
+			value, ok := family.value(sample)
+			if !ok {
+				continue
+			}
+			fmt.Fprintf(w, "%s{%s} %s\n", family.name, sampleLabels(sample, ""), strconv.FormatFloat(value, 'g', -1, 64))
+		}
+	}
+	fmt.Fprintln(w, "# HELP mcquery_probe_error Failure class of an unsuccessful probe.")
+	fmt.Fprintln(w, "# TYPE mcquery_probe_error gauge")
+	for _, sample := range samples {
+		if sample.Up {
+			continue
+		}
+		fmt.Fprintf(w, "mcquery_probe_error{%s} 1\n", sampleLabels(sample, string(sample.ErrorClass)))
+	}
+	fmt.Fprintln(w, "# HELP mcquery_version_info Version string reported by the server.")
+	fmt.Fprintln(w, "# TYPE mcquery_version_info gauge")
+	for _, sample := range samples {
+		if !sample.Up || sample.Version == "" {
+			continue
+		}
+		fmt.Fprintf(w, "mcquery_version_info{%s,version=\"%s\"} 1\n", sampleLabels(sample, ""), escapeLabel(sample.Version))
+	}
+}
+
+func sampleLabels(sample ProbeSample, errorClass string) string {
+	target := net.JoinHostPort(sample.Target.Host, strconv.Itoa(sample.Target.Port))
+	name := sample.Target.Name
+	if name == "" {
+		name = target
+	}
+	labels := fmt.Sprintf("name=\"%s\",edition=\"%s\",target=\"%s\"", escapeLabel(name), escapeLabel(string(sample.Target.Edition)), escapeLabel(target))
+	if errorClass != "" {
+		labels += fmt.Sprintf(",error_class=\"%s\"", escapeLabel(errorClass))
+	}
+	return labels
+}
+
+func escapeLabel(value string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	return replacer.Replace(value)
+}
+
+func boolMetric(value bool) float64 {
+	if value {
+		return 1
+	}
+	return 0
+}
+
+func scrapeDeadline(r *http.Request) time.Duration {
+	if header := r.Header.Get("X-Prometheus-Scrape-Timeout-Seconds"); header != "" {
+		if seconds, err := strconv.ParseFloat(header, 64); err == nil && seconds > 0 {
+			return time.Duration(seconds * float64(time.Second))
+		}
+	}
+	return defaultScrapeDeadline
+}
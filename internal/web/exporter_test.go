@@ -0,0 +1,82 @@
+package web
+
+import (
+	"context"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"UWP-TCP-Con/internal/ping"
+)
+
+func TestParseProbeTarget(t *testing.T) {
+	target, err := ParseProbeTarget("play.example.com", ping.EditionBedrock)
+	if err != nil {
+		t.Fatalf("ParseProbeTarget: %v", err)
+	}
+	if target.Host != "play.example.com" || target.Port != 19132 {
+		t.Fatalf("unexpected target: %+v", target)
+	}
+	target, err = ParseProbeTarget("[2001:db8::1]:25570", ping.EditionJava)
+	if err != nil {
+		t.Fatalf("ParseProbeTarget: %v", err)
+	}
+	if target.Host != "2001:db8::1" || target.Port != 25570 {
+		t.Fatalf("unexpected target: %+v", target)
+	}
+	if _, err := ParseProbeTarget("host:99999", ping.EditionJava); err == nil {
+		t.Fatalf("expected port error")
+	}
+}
+
+func TestProbeEndpoint(t *testing.T) {
+	exporter := NewExporter(nil, ping.ExecuteOptions{})
+	exporter.execute = func(ctx context.Context, config ping.ExecuteConfig) (ping.Result, ping.ExecuteDetails, error) {
+		details := ping.ExecuteDetails{SRVUsed: true, ResolveTime: 3 * time.Millisecond}
+		if config.Host == "down.example.com" {
+			return nil, details, &ping.ProbeError{Class: ping.FailureConnRefused}
+		}
+		return ping.JavaStatus{VersionName: "Paper 1.21", ProtocolVersion: 767, CurrentPlayers: 12, MaxPlayers: 100, LatencyMillis: 42}, details, nil
+	}
+	handler := exporter.Handler()
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest("GET", "/probe?target=up.example.com:25565&edition=java", nil))
+	body, _ := io.ReadAll(recorder.Result().Body)
+	text := string(body)
+	for _, want := range []string{
+		`mcquery_up{name="up.example.com:25565",edition="java",target="up.example.com:25565"} 1`,
+		`mcquery_players_online{name="up.example.com:25565",edition="java",target="up.example.com:25565"} 12`,
+		`mcquery_latency_seconds{name="up.example.com:25565",edition="java",target="up.example.com:25565"} 0.042`,
+		`mcquery_protocol_version{name="up.example.com:25565",edition="java",target="up.example.com:25565"} 767`,
+		`mcquery_srv_used{name="up.example.com:25565",edition="java",target="up.example.com:25565"} 1`,
+		`mcquery_resolve_duration_seconds{name="up.example.com:25565",edition="java",target="up.example.com:25565"} 0.003`,
+		`version="Paper 1.21"} 1`,
+	} {
+		if !strings.Contains(text, want) {
+			t.Fatalf("missing %q in:\n%s", want, text)
+		}
+	}
+
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest("GET", "/probe?target=down.example.com", nil))
+	body, _ = io.ReadAll(recorder.Result().Body)
+	text = string(body)
+	if !strings.Contains(text, `mcquery_up{name="down.example.com",edition="java",target="down.example.com:25565"} 0`) {
+		t.Fatalf("missing down gauge in:\n%s", text)
+	}
+	if !strings.Contains(text, `error_class="connection_refused"} 1`) {
+		t.Fatalf("missing error class in:\n%s", text)
+	}
+	if strings.Contains(text, "mcquery_players_online{") {
+		t.Fatalf("players reported for a down target:\n%s", text)
+	}
+
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest("GET", "/probe?target=x&edition=pocket", nil))
+	if recorder.Code != 400 {
+		t.Fatalf("expected 400 for unknown edition, got %d", recorder.Code)
+	}
+}
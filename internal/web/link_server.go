@@ -4,9 +4,10 @@ import (
 	"fmt"
 	"net"
 	"net/http"
-	"net/url"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -21,6 +22,11 @@ type LinkServer struct {
 	server   *http.Server
 	listener net.Listener
 	entries  []LookupLink
+	closed   atomic.Bool
+
+	mu          sync.RWMutex
+	dashboard   *Dashboard
+	resultsPath string
 }
 
 func StartLookupLinkServer(entries []LookupLink, ttl time.Duration) (*LinkServer, error) {
@@ -31,14 +37,23 @@ func StartLookupLinkServer(entries []LookupLink, ttl time.Duration) (*LinkServer
 
 	mux := http.NewServeMux()
 	server := &http.Server{
-		Handler: mux,
+		Handler: LocalHostOnly(listener.Addr().(*net.TCPAddr).Port, mux),
+	}
+	linkServer := &LinkServer{
+		URL:      fmt.Sprintf("http://%s", listener.Addr().String()),
+		server:   server,
+		listener: listener,
+		entries:  append([]LookupLink(nil), entries...),
 	}
 
 	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
 		path := strings.TrimPrefix(r.URL.Path, "/")
 		if path == "" {
-			w.WriteHeader(http.StatusOK)
-			_, _ = w.Write([]byte("MCQuery link server is running. Use the links shown in the terminal.\n"))
+			linkServer.serveDashboard(w, r)
+			return
+		}
+		if path == "results" || strings.HasPrefix(path, "results/") {
+			linkServer.serveResults(w, r, strings.TrimPrefix(strings.TrimPrefix(path, "results"), "/"))
 			return
 		}
 
@@ -47,6 +62,14 @@ func StartLookupLinkServer(entries []LookupLink, ttl time.Duration) (*LinkServer
 			http.NotFound(w, r)
 			return
 		}
+		if parts[0] == "icon" {
+			linkServer.serveIcon(w, r, parts[1])
+			return
+		}
+		if parts[0] == "banner" {
+			linkServer.serveBanner(w, r, parts[1])
+			return
+		}
 
 		index, err := strconv.Atoi(parts[1])
 		if err != nil || index < 0 || index >= len(entries) {
@@ -57,16 +80,9 @@ func StartLookupLinkServer(entries []LookupLink, ttl time.Duration) (*LinkServer
 		entry := entries[index]
 		switch parts[0] {
 		case "add":
-			addValue := fmt.Sprintf("%s|%s:%d", entry.Name, entry.Host, entry.Port)
-			target := "minecraft://?addExternalServer=" + url.QueryEscape(addValue)
-			http.Redirect(w, r, target, http.StatusFound)
+			http.Redirect(w, r, bedrockAddURI(entry.Name, entry.Host, entry.Port), http.StatusFound)
 		case "connect":
-			target := fmt.Sprintf(
-				"minecraft://connect/?serverUrl=%s&serverPort=%d",
-				url.QueryEscape(entry.Host),
-				entry.Port,
-			)
-			http.Redirect(w, r, target, http.StatusFound)
+			http.Redirect(w, r, bedrockConnectURI(entry.Host, entry.Port), http.StatusFound)
 		default:
 			http.NotFound(w, r)
 		}
@@ -77,13 +93,6 @@ func StartLookupLinkServer(entries []LookupLink, ttl time.Duration) (*LinkServer
 		serverErr <- server.Serve(listener)
 	}()
 
-	linkServer := &LinkServer{
-		URL:      fmt.Sprintf("http://%s", listener.Addr().String()),
-		server:   server,
-		listener: listener,
-		entries:  append([]LookupLink(nil), entries...),
-	}
-
 	if ttl > 0 {
 		time.AfterFunc(ttl, func() {
 			_ = linkServer.Close()
@@ -99,6 +108,21 @@ func StartLookupLinkServer(entries []LookupLink, ttl time.Duration) (*LinkServer
 	return linkServer, nil
 }
 
+func LocalHostOnly(port int, next http.Handler) http.Handler {
+	allowed := map[string]bool{
+		net.JoinHostPort("127.0.0.1", strconv.Itoa(port)): true,
+		net.JoinHostPort("localhost", strconv.Itoa(port)): true,
+		net.JoinHostPort("::1", strconv.Itoa(port)):       true,
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !allowed[strings.ToLower(r.Host)] {
+			http.Error(w, "forbidden host", http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
 type LookupLinkURLs struct {
 	Name       string
 	AddURL     string
@@ -120,10 +144,15 @@ func (s *LinkServer) Links() []LookupLinkURLs {
 	return links
 }
 
+func (s *LinkServer) Active() bool {
+	return s != nil && !s.closed.Load()
+}
+
 func (s *LinkServer) Close() error {
 	if s == nil {
 		return nil
 	}
+	s.closed.Store(true)
 	if s.server != nil {
 		_ = s.server.Close()
 	}
@@ -0,0 +1,106 @@
+package web
+
+import (
+	"bufio"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWebSocketAccept(t *testing.T) {
+	if got := WebSocketAccept("dGhlIHNhbXBsZSBub25jZQ=="); got != "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=" {
+		t.Fatalf("unexpected accept key %q", got)
+	}
+}
+
+func TestWebSocketEcho(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := UpgradeWebSocket(w, r)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		op, payload, err := conn.ReadMessage()
+		if err != nil || op != OpText {
+			return
+		}
+		_ = conn.WriteText(append([]byte("echo:"), payload...))
+	}))
+	defer server.Close()
+
+	conn, err := net.Dial("tcp", strings.TrimPrefix(server.URL, "http://"))
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(5 * time.Second))
+	request := "GET / HTTP/1.1\r\nHost: test\r\nUpgrade: websocket\r\nConnection: Upgrade\r\nSec-WebSocket-Version: 13\r\nSec-WebSocket-Key: dGhlIHNhbXBsZSBub25jZQ==\r\n\r\n"
+	if _, err := conn.Write([]byte(request)); err != nil {
+		t.Fatalf("write handshake: %v", err)
+	}
+	reader := bufio.NewReader(conn)
+	response, err := http.ReadResponse(reader, nil)
+	if err != nil {
+		t.Fatalf("read handshake: %v", err)
+	}
+	if response.StatusCode != http.StatusSwitchingProtocols || response.Header.Get("Sec-WebSocket-Accept") != "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=" {
+		t.Fatalf("unexpected handshake response: %d %v", response.StatusCode, response.Header)
+	}
+
+	mask := []byte{1, 2, 3, 4}
+	payload := []byte("pause")
+	frame := []byte{0x81, 0x80 | byte(len(payload))}
+	frame = append(frame, mask...)
+	for i, b := range payload {
+		frame = append(frame, b^mask[i%4])
+	}
+	if _, err := conn.Write(frame); err != nil {
+		t.Fatalf("write frame: %v", err)
+	}
+	header := make([]byte, 2)
+	if _, err := reader.Read(header); err != nil {
+		t.Fatalf("read frame header: %v", err)
+	}
+	if header[0] != 0x81 {
+		t.Fatalf("unexpected opcode byte %x", header[0])
+	}
+	body := make([]byte, int(header[1]))
+	if _, err := reader.Read(body); err != nil {
+		t.Fatalf("read frame body: %v", err)
+	}
+	if string(body) != "echo:pause" {
+		t.Fatalf("unexpected payload %q", body)
+	}
+}
+
+func TestUpgradeWebSocketChecksOrigin(t *testing.T) {
+	cases := []struct {
+		origin string
+		want   int
+	}{
+		{"https://evil.example.com", http.StatusForbidden},
+		{"http://127.0.0.1:8765.evil.example.com", http.StatusForbidden},
+		{"http://127.0.0.1:8765", http.StatusInternalServerError},
+		{"", http.StatusInternalServerError},
+	}
+	for _, tc := range cases {
+		request := httptest.NewRequest(http.MethodGet, "http://127.0.0.1:8765/lookups/x/events", nil)
+		request.Header.Set("Connection", "Upgrade")
+		request.Header.Set("Upgrade", "websocket")
+		request.Header.Set("Sec-WebSocket-Version", "13")
+		request.Header.Set("Sec-WebSocket-Key", "dGhlIHNhbXBsZSBub25jZQ==")
+		if tc.origin != "" {
+			request.Header.Set("Origin", tc.origin)
+		}
+		recorder := httptest.NewRecorder()
+		if _, err := UpgradeWebSocket(recorder, request); err == nil {
+			t.Fatalf("%q: expected the recorder upgrade to fail", tc.origin)
+		}
+		if recorder.Code != tc.want {
+			t.Fatalf("%q: expected %d, got %d", tc.origin, tc.want, recorder.Code)
+		}
+	}
+}